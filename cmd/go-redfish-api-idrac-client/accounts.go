@@ -0,0 +1,260 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io"
+	"strconv"
+)
+
+// accountOptions holds the arguments of the account management operations.
+// The passwords come from credential sources, i.e. never from command line
+// arguments.
+type accountOptions struct {
+	userName        string
+	newUserName     string
+	role            string
+	enabled         string
+	unlock          bool
+	passwordEnv     string
+	passwordFile    string
+	passwordCommand string
+	policy          struct {
+		authFailureLoggingThreshold     int64
+		accountLockoutThreshold         int64
+		accountLockoutDuration          int64
+		accountLockoutCounterResetAfter int64
+		minPasswordLength               int64
+		maxPasswordLength               int64
+	}
+}
+
+func (opts *accountOptions) bindFlags() {
+	flag.StringVar(&opts.userName, "account.username", "", "account operations: username of the account")
+	flag.StringVar(&opts.newUserName, "account.new-username", "", "update-account: new username of the account")
+	flag.StringVar(&opts.role, "account.role", "", "create-account, update-account: role of the account, e.g. Administrator, Operator, ReadOnly")
+	flag.StringVar(&opts.enabled, "account.enabled", "", "update-account: whether the account is enabled, true or false")
+	flag.BoolVar(&opts.unlock, "account.unlock", false, "update-account: unlock the account locked after failed logins")
+	flag.StringVar(&opts.passwordEnv, "account.password-env", "", "create-account, update-account: environment variable holding the password")
+	flag.StringVar(&opts.passwordFile, "account.password-file", "", "create-account, update-account: file holding the password")
+	flag.StringVar(&opts.passwordCommand, "account.password-command", "", "create-account, update-account: command printing the password of the host in IDRAC_API_HOST")
+	flag.Int64Var(&opts.policy.authFailureLoggingThreshold, "account.policy.auth-failure-logging-threshold", -1, "set-account-policy: number of failed logins triggering a log entry")
+	flag.Int64Var(&opts.policy.accountLockoutThreshold, "account.policy.lockout-threshold", -1, "set-account-policy: number of failed logins locking an account, 0 disables lockout")
+	flag.Int64Var(&opts.policy.accountLockoutDuration, "account.policy.lockout-duration", -1, "set-account-policy: seconds an account remains locked")
+	flag.Int64Var(&opts.policy.accountLockoutCounterResetAfter, "account.policy.lockout-reset-after", -1, "set-account-policy: seconds after which the failed login counter resets")
+	flag.Int64Var(&opts.policy.minPasswordLength, "account.policy.min-password-length", -1, "set-account-policy: minimum password length")
+	flag.Int64Var(&opts.policy.maxPasswordLength, "account.policy.max-password-length", -1, "set-account-policy: maximum password length")
+}
+
+// newPasswordProvider returns the provider of a password from an environment
+// variable, a file, or a command. It returns nil when no source is set.
+func newPasswordProvider(env, file, command string) (client.CredentialProvider, error) {
+	if env == "" && file == "" && command == "" {
+		return nil, nil
+	}
+	source := &client.CredentialSource{
+		PasswordEnv:     env,
+		PasswordFile:    file,
+		PasswordCommand: command,
+	}
+	return source.Provider()
+}
+
+// password returns the password of the account for a host, or an empty
+// string when no password source is set.
+func (opts *accountOptions) password(host string) (string, error) {
+	provider, err := newPasswordProvider(opts.passwordEnv, opts.passwordFile, opts.passwordCommand)
+	if err != nil || provider == nil {
+		return "", err
+	}
+	creds, err := provider.GetCredentials(host)
+	if err != nil {
+		return "", err
+	}
+	return creds.Password, nil
+}
+
+// accountPolicy returns the policy changes set via command line arguments.
+func (opts *accountOptions) accountPolicy() *client.AccountPolicy {
+	policy := &client.AccountPolicy{}
+	for _, entry := range []struct {
+		value int64
+		field **uint64
+	}{
+		{opts.policy.authFailureLoggingThreshold, &policy.AuthFailureLoggingThreshold},
+		{opts.policy.accountLockoutThreshold, &policy.AccountLockoutThreshold},
+		{opts.policy.accountLockoutDuration, &policy.AccountLockoutDuration},
+		{opts.policy.accountLockoutCounterResetAfter, &policy.AccountLockoutCounterResetAfter},
+		{opts.policy.minPasswordLength, &policy.MinPasswordLength},
+		{opts.policy.maxPasswordLength, &policy.MaxPasswordLength},
+	} {
+		if entry.value >= 0 {
+			v := uint64(entry.value)
+			*entry.field = &v
+		}
+	}
+	return policy
+}
+
+// accountChange is the outcome of an account management operation.
+type accountChange struct {
+	Host      string `yaml:"host" json:"host" xml:"host"`
+	Operation string `yaml:"operation" json:"operation" xml:"operation"`
+	UserName  string `yaml:"user_name" json:"user_name" xml:"user_name"`
+	AccountID string `yaml:"account_id" json:"account_id" xml:"account_id"`
+}
+
+func newAccountChangeResult(change *accountChange, message string) *operationResult {
+	return &operationResult{
+		data: change,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", change.Host)
+			fmt.Fprintf(w, "Account %s (ID %s) %s\n", change.UserName, change.AccountID, message)
+		},
+	}
+}
+
+// runAccountOperation performs the account management operations.
+func runAccountOperation(cli *client.Client, host string, operation string, opts *accountOptions) (*operationResult, error) {
+	switch operation {
+	case "list-accounts":
+		accounts, err := cli.ListAccounts()
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: accounts,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				for _, account := range accounts {
+					fmt.Fprintf(w, "Account: %s | ID: %s | Role: %s | Enabled: %t | Locked: %t\n",
+						account.UserName, account.ID, account.RoleID, account.Enabled, account.Locked)
+				}
+			},
+		}, nil
+	case "list-roles":
+		roles, err := cli.ListRoles()
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: roles,
+			text: func(w io.Writer) {
+				for _, role := range roles {
+					fmt.Fprintf(w, "Role: %s | Privileges: %v | OEM Privileges: %v\n",
+						role.ID, role.AssignedPrivileges, role.OemPrivileges)
+				}
+			},
+		}, nil
+	case "get-account-policy":
+		svc, err := cli.GetAccountService()
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: svc,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Local Account Authentication: %s\n", svc.LocalAccountAuth)
+				fmt.Fprintf(w, "Account Lockout Threshold: %d\n", svc.AccountLockoutThreshold)
+				fmt.Fprintf(w, "Account Lockout Duration: %ds\n", svc.AccountLockoutDuration)
+				fmt.Fprintf(w, "Account Lockout Counter Reset After: %ds\n", svc.AccountLockoutCounterResetAfter)
+				fmt.Fprintf(w, "Auth Failure Logging Threshold: %d\n", svc.AuthFailureLoggingThreshold)
+				fmt.Fprintf(w, "Password Length: %d-%d\n", svc.MinPasswordLength, svc.MaxPasswordLength)
+			},
+		}, nil
+	case "set-account-policy":
+		if err := cli.UpdateAccountPolicy(opts.accountPolicy()); err != nil {
+			return nil, err
+		}
+		svc, err := cli.GetAccountService()
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: svc,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Account policy updated\n")
+			},
+		}, nil
+	}
+
+	if opts.userName == "" {
+		return nil, fmt.Errorf("the --account.username argument is required")
+	}
+	change := &accountChange{
+		Host:      host,
+		Operation: operation,
+		UserName:  opts.userName,
+	}
+	password, err := opts.password(host)
+	if err != nil {
+		return nil, fmt.Errorf("password: %s", err)
+	}
+
+	if operation == "create-account" {
+		if password == "" {
+			return nil, fmt.Errorf("one of --account.password-env, --account.password-file, --account.password-command arguments is required")
+		}
+		role := opts.role
+		if role == "" {
+			role = "ReadOnly"
+		}
+		account, err := cli.CreateAccount(opts.userName, password, role)
+		if err != nil {
+			return nil, err
+		}
+		change.AccountID = account.ID
+		return newAccountChangeResult(change, "created"), nil
+	}
+
+	account, err := cli.GetAccountByUserName(opts.userName)
+	if err != nil {
+		return nil, err
+	}
+	change.AccountID = account.ID
+
+	switch operation {
+	case "update-account":
+		changes := &client.AccountChanges{}
+		if opts.newUserName != "" {
+			changes.UserName = &opts.newUserName
+		}
+		if password != "" {
+			changes.Password = &password
+		}
+		if opts.role != "" {
+			changes.RoleID = &opts.role
+		}
+		if opts.enabled != "" {
+			enabled, err := strconv.ParseBool(opts.enabled)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --account.enabled value %q", opts.enabled)
+			}
+			changes.Enabled = &enabled
+		}
+		if opts.unlock {
+			locked := false
+			changes.Locked = &locked
+		}
+		if err := cli.UpdateAccount(account.ID, changes); err != nil {
+			return nil, err
+		}
+		return newAccountChangeResult(change, "updated"), nil
+	case "disable-account":
+		if err := cli.DisableAccount(account.ID); err != nil {
+			return nil, err
+		}
+		return newAccountChangeResult(change, "disabled"), nil
+	case "delete-account":
+		if err := cli.DeleteAccount(account.ID); err != nil {
+			return nil, err
+		}
+		return newAccountChangeResult(change, "deleted"), nil
+	}
+	return nil, fmt.Errorf("the --operation %s is supported by API, but not this utility", operation)
+}
@@ -0,0 +1,233 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// The short names of the attribute sets.
+var attributeSetAliases = map[string]string{
+	"idrac":  client.AttributeSetIDRAC,
+	"system": client.AttributeSetSystem,
+	"lc":     client.AttributeSetLifecycleController,
+}
+
+// attrsOptions holds the arguments of the attribute operations.
+type attrsOptions struct {
+	set        string
+	names      string
+	values     string
+	file       string
+	noValidate bool
+
+	// The attribute registries are fetched once per version and shared by
+	// the hosts of a fleet.
+	mu         sync.Mutex
+	registries map[string]*client.AttributeRegistry
+}
+
+func (opts *attrsOptions) bindFlags() {
+	flag.StringVar(&opts.set, "attrs.set", "idrac", "get-attributes, set-attributes: attribute set, i.e. idrac, system, lc, or its id, e.g. iDRAC.Embedded.1")
+	flag.StringVar(&opts.names, "attrs.names", "", "get-attributes: comma-separated attribute names, groups, or patterns, e.g. NTPConfigGroup.1,IPMILan.1.Enable")
+	flag.StringVar(&opts.values, "attrs.values", "", "set-attributes: comma-separated attributes, e.g. NTPConfigGroup.1.NTPEnable=Enabled")
+	flag.StringVar(&opts.file, "attrs.file", "", "set-attributes: YAML file with the attribute names and values")
+	flag.BoolVar(&opts.noValidate, "attrs.no-validate", false, "get-attributes, set-attributes: skip the validation of the attributes against the attribute registry")
+}
+
+// attributeSet returns the id of the attribute set of the arguments.
+func (opts *attrsOptions) attributeSet() string {
+	if set, exists := attributeSetAliases[strings.ToLower(opts.set)]; exists {
+		return set
+	}
+	return opts.set
+}
+
+// registry returns the attribute registry with the id, fetching it from the
+// host once.
+func (opts *attrsOptions) registry(cli *client.Client, id string) (*client.AttributeRegistry, error) {
+	opts.mu.Lock()
+	defer opts.mu.Unlock()
+	if registry, exists := opts.registries[id]; exists {
+		return registry, nil
+	}
+	registry, err := cli.GetAttributeRegistry(id)
+	if err != nil {
+		return nil, err
+	}
+	if opts.registries == nil {
+		opts.registries = make(map[string]*client.AttributeRegistry)
+	}
+	opts.registries[id] = registry
+	return registry, nil
+}
+
+// desired returns the attributes of the --attrs.values and the
+// --attrs.file arguments.
+func (opts *attrsOptions) desired() (map[string]interface{}, error) {
+	attributes := make(map[string]interface{})
+	if opts.file != "" {
+		b, err := ioutil.ReadFile(opts.file)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.NewDecoder(bytes.NewReader(b)).Decode(&attributes); err != nil {
+			return nil, fmt.Errorf("attributes %s: %s", opts.file, err)
+		}
+	}
+	for _, entry := range splitList(opts.values) {
+		i := strings.Index(entry, "=")
+		if i < 1 {
+			return nil, fmt.Errorf("attribute %s is invalid, expecting name=value", entry)
+		}
+		attributes[strings.TrimSpace(entry[:i])] = strings.TrimSpace(entry[i+1:])
+	}
+	if len(attributes) == 0 {
+		return nil, fmt.Errorf("--attrs.values and --attrs.file are empty")
+	}
+	return attributes, nil
+}
+
+// matchAttribute returns true when the name matches an attribute name,
+// the group of the attribute, e.g. NTPConfigGroup.1, or a pattern.
+func matchAttribute(name, attribute string) bool {
+	if name == attribute || strings.HasPrefix(attribute, name+".") {
+		return true
+	}
+	matched, _ := path.Match(name, attribute)
+	return matched
+}
+
+// attributeChange is a change of an attribute by the set-attributes
+// operation.
+type attributeChange struct {
+	Set     string `yaml:"set" json:"set" xml:"set"`
+	Name    string `yaml:"name" json:"name" xml:"name"`
+	Current string `yaml:"current" json:"current" xml:"current"`
+	Desired string `yaml:"desired" json:"desired" xml:"desired"`
+}
+
+// runAttributesOperation performs the get-attributes and set-attributes
+// operations.
+func runAttributesOperation(cli *client.Client, host string, operation, format string, opts *attrsOptions) (*operationResult, error) {
+	set := opts.attributeSet()
+	attributeSet, err := cli.GetAttributeSet(set)
+	if err != nil {
+		return nil, err
+	}
+	var registry *client.AttributeRegistry
+	if !opts.noValidate {
+		if registry, err = opts.registry(cli, attributeSet.AttributeRegistry); err != nil {
+			return nil, err
+		}
+	}
+	if operation == "set-attributes" {
+		return runSetAttributes(cli, host, attributeSet, registry, opts)
+	}
+
+	attributes := registry.Describe(attributeSet)
+	names := splitList(opts.names)
+	if len(names) > 0 {
+		selected := []*client.Attribute{}
+		for _, attribute := range attributes {
+			for _, name := range names {
+				if matchAttribute(name, attribute.Name) {
+					selected = append(selected, attribute)
+					break
+				}
+			}
+		}
+		attributes = selected
+	}
+	return &operationResult{
+		data: attributes,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Attribute Set: %s\n", set)
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tVALUE\tTYPE\tREAD ONLY")
+			for _, attribute := range attributes {
+				fmt.Fprintf(tw, "%s\t%v\t%s\t%t\n", attribute.Name, attribute.Value, attribute.Type, attribute.ReadOnly)
+			}
+			tw.Flush()
+		},
+	}, nil
+}
+
+// runSetAttributes changes the attributes differing from the desired
+// values, and reports the changes.
+func runSetAttributes(cli *client.Client, host string, attributeSet *client.AttributeSet, registry *client.AttributeRegistry, opts *attrsOptions) (*operationResult, error) {
+	desired, err := opts.desired()
+	if err != nil {
+		return nil, err
+	}
+	if registry != nil {
+		if desired, err = registry.Validate(attributeSet.ID, desired); err != nil {
+			return nil, err
+		}
+	}
+	names := []string{}
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	changes := []*attributeChange{}
+	patch := make(map[string]interface{})
+	for _, name := range names {
+		current := ""
+		if value, exists := attributeSet.Attributes[name]; exists && value != nil {
+			current = fmt.Sprint(value)
+		}
+		if current == fmt.Sprint(desired[name]) {
+			continue
+		}
+		change := &attributeChange{
+			Set:     attributeSet.ID,
+			Name:    name,
+			Current: current,
+			Desired: fmt.Sprint(desired[name]),
+		}
+		if registry.IsPassword(attributeSet.ID, name) {
+			// The passwords are not printed.
+			change.Desired = client.MaskedValue
+			if change.Current != "" {
+				change.Current = client.MaskedValue
+			}
+		}
+		changes = append(changes, change)
+		patch[name] = desired[name]
+	}
+	if len(patch) > 0 {
+		if err := cli.SetAttributeSet(attributeSet.ID, patch); err != nil {
+			return nil, err
+		}
+	}
+	return &operationResult{
+		data: changes,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Attribute Set: %s\n", attributeSet.ID)
+			if len(changes) == 0 {
+				fmt.Fprintf(w, "No changes, the attributes have the desired values\n")
+				return
+			}
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tCURRENT\tDESIRED")
+			for _, change := range changes {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", change.Name, change.Current, change.Desired)
+			}
+			tw.Flush()
+		},
+	}, nil
+}
@@ -0,0 +1,390 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The exit codes of the check-health operation follow monitoring plugin
+// (Nagios, Icinga) conventions.
+const (
+	checkStateOK = iota
+	checkStateWarning
+	checkStateCritical
+	checkStateUnknown
+)
+
+var checkStateNames = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// checkStatePriority is used to determine the overall state of multiple
+// checks, i.e. CRITICAL > WARNING > UNKNOWN > OK.
+var checkStatePriority = []int{0, 2, 3, 1}
+
+var checkComponentNames = []string{"system", "processors", "memory", "temperatures", "fans", "power"}
+
+var perfdataLabelRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// checkThreshold is a monitoring plugin threshold range, i.e. [@][start:][end].
+// The range raises an alert when a value is outside of it, or inside of it
+// when the range starts with @.
+type checkThreshold struct {
+	raw    string
+	start  float64
+	end    float64
+	inside bool
+}
+
+func parseCheckThreshold(s string) (*checkThreshold, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t := &checkThreshold{
+		raw:   s,
+		start: 0,
+		end:   math.Inf(1),
+	}
+	if strings.HasPrefix(s, "@") {
+		t.inside = true
+		s = s[1:]
+	}
+	bounds := strings.SplitN(s, ":", 2)
+	if len(bounds) == 1 {
+		bounds = []string{"", bounds[0]}
+	}
+	switch bounds[0] {
+	case "":
+	case "~":
+		t.start = math.Inf(-1)
+	default:
+		v, err := strconv.ParseFloat(bounds[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q: %s", t.raw, err)
+		}
+		t.start = v
+	}
+	if bounds[1] != "" {
+		v, err := strconv.ParseFloat(bounds[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q: %s", t.raw, err)
+		}
+		t.end = v
+	}
+	if t.start > t.end {
+		return nil, fmt.Errorf("invalid threshold %q: start is greater than end", t.raw)
+	}
+	return t, nil
+}
+
+// alert returns true when the value violates the threshold.
+func (t *checkThreshold) alert(v float64) bool {
+	if t == nil {
+		return false
+	}
+	outside := v < t.start || v > t.end
+	if t.inside {
+		return !outside
+	}
+	return outside
+}
+
+func (t *checkThreshold) String() string {
+	if t == nil {
+		return ""
+	}
+	return t.raw
+}
+
+// healthCheckOptions holds the arguments of the check-health operation.
+type healthCheckOptions struct {
+	components    string
+	chassisID     string
+	tempWarning   string
+	tempCritical  string
+	fanWarning    string
+	fanCritical   string
+	powerWarning  string
+	powerCritical string
+}
+
+func (opts *healthCheckOptions) bindFlags() {
+	flag.StringVar(&opts.components, "check.components", strings.Join(checkComponentNames, ","), "check-health: comma-separated components to check")
+	flag.StringVar(&opts.chassisID, "check.chassis", "System.Embedded.1", "check-health: chassis providing thermal and power data")
+	flag.StringVar(&opts.tempWarning, "check.temp.warning", "", "check-health: temperature warning range in Celsius, defaults to sensor thresholds")
+	flag.StringVar(&opts.tempCritical, "check.temp.critical", "", "check-health: temperature critical range in Celsius, defaults to sensor thresholds")
+	flag.StringVar(&opts.fanWarning, "check.fan.warning", "", "check-health: fan speed warning range in RPM, defaults to sensor thresholds")
+	flag.StringVar(&opts.fanCritical, "check.fan.critical", "", "check-health: fan speed critical range in RPM, defaults to sensor thresholds")
+	flag.StringVar(&opts.powerWarning, "check.power.warning", "", "check-health: power consumption warning range in watts")
+	flag.StringVar(&opts.powerCritical, "check.power.critical", "", "check-health: power consumption critical range in watts")
+}
+
+// healthCheck holds the parsed thresholds and the state of a single
+// check-health run.
+type healthCheck struct {
+	components    map[string]bool
+	chassisID     string
+	tempWarning   *checkThreshold
+	tempCritical  *checkThreshold
+	fanWarning    *checkThreshold
+	fanCritical   *checkThreshold
+	powerWarning  *checkThreshold
+	powerCritical *checkThreshold
+
+	state    int
+	checks   int
+	messages []string
+	perfdata []string
+}
+
+func newHealthCheck(opts *healthCheckOptions) (*healthCheck, error) {
+	var err error
+	hc := &healthCheck{
+		components: make(map[string]bool),
+		chassisID:  opts.chassisID,
+	}
+	for _, name := range strings.Split(opts.components, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			for _, n := range checkComponentNames {
+				hc.components[n] = true
+			}
+			continue
+		}
+		found := false
+		for _, n := range checkComponentNames {
+			if n == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unsupported component %q, supported: %s", name, strings.Join(checkComponentNames, ", "))
+		}
+		hc.components[name] = true
+	}
+	if len(hc.components) == 0 {
+		return nil, fmt.Errorf("no components selected")
+	}
+	for _, entry := range []struct {
+		s string
+		t **checkThreshold
+	}{
+		{opts.tempWarning, &hc.tempWarning},
+		{opts.tempCritical, &hc.tempCritical},
+		{opts.fanWarning, &hc.fanWarning},
+		{opts.fanCritical, &hc.fanCritical},
+		{opts.powerWarning, &hc.powerWarning},
+		{opts.powerCritical, &hc.powerCritical},
+	} {
+		if *entry.t, err = parseCheckThreshold(entry.s); err != nil {
+			return nil, err
+		}
+	}
+	return hc, nil
+}
+
+// report records the outcome of a single check. The overall state is the
+// worst of the individual states.
+func (hc *healthCheck) report(state int, format string, args ...interface{}) {
+	hc.checks++
+	if state == checkStateOK {
+		return
+	}
+	hc.messages = append(hc.messages, fmt.Sprintf(format, args...))
+	if checkStatePriority[state] > checkStatePriority[hc.state] {
+		hc.state = state
+	}
+}
+
+func (hc *healthCheck) addPerfdata(label string, value float64, warning, critical string) {
+	label = strings.Trim(perfdataLabelRegexp.ReplaceAllString(strings.ToLower(label), "_"), "_")
+	hc.perfdata = append(hc.perfdata, fmt.Sprintf("%s=%s;%s;%s", label, strconv.FormatFloat(value, 'f', -1, 64), warning, critical))
+}
+
+// reportHealth evaluates Redfish health status of a component.
+func (hc *healthCheck) reportHealth(name string, status client.HealthStatus) {
+	if status.State == "Absent" || status.State == "Disabled" {
+		return
+	}
+	switch status.Health {
+	case "OK":
+		hc.report(checkStateOK, "")
+	case "Warning":
+		hc.report(checkStateWarning, "%s health is %s", name, status.Health)
+	case "Critical":
+		hc.report(checkStateCritical, "%s health is %s", name, status.Health)
+	case "":
+		return
+	default:
+		hc.report(checkStateUnknown, "%s health is %s", name, status.Health)
+	}
+}
+
+// reportValue evaluates a reading against warning and critical thresholds.
+func (hc *healthCheck) reportValue(name string, value float64, unit string, warning, critical *checkThreshold) {
+	switch {
+	case critical.alert(value):
+		hc.report(checkStateCritical, "%s is %s%s (critical %s)", name, strconv.FormatFloat(value, 'f', -1, 64), unit, critical)
+	case warning.alert(value):
+		hc.report(checkStateWarning, "%s is %s%s (warning %s)", name, strconv.FormatFloat(value, 'f', -1, 64), unit, warning)
+	default:
+		hc.report(checkStateOK, "")
+	}
+	hc.addPerfdata(name, value, warning.String(), critical.String())
+}
+
+// sensorThreshold returns a threshold derived from the limits of a sensor.
+// Zero limits are treated as not set.
+func sensorThreshold(lower, upper float64) *checkThreshold {
+	if lower == 0 && upper == 0 {
+		return nil
+	}
+	t := &checkThreshold{start: math.Inf(-1), end: math.Inf(1)}
+	if lower != 0 {
+		t.start = lower
+		t.raw = strconv.FormatFloat(lower, 'f', -1, 64) + ":"
+	}
+	if upper != 0 {
+		t.end = upper
+		if lower == 0 {
+			t.raw = "~:"
+		}
+		t.raw += strconv.FormatFloat(upper, 'f', -1, 64)
+	}
+	return t
+}
+
+func (hc *healthCheck) run(cli *client.Client) error {
+	if hc.components["system"] || hc.components["processors"] || hc.components["memory"] {
+		computerSystems, err := cli.GetComputerSystems()
+		if err != nil {
+			return err
+		}
+		for _, cs := range computerSystems {
+			if hc.components["system"] {
+				hc.reportHealth(cs.ID, cs.Status)
+			}
+			if hc.components["processors"] {
+				hc.reportHealth(cs.ID+" processors", cs.ProcessorStatus)
+			}
+			if hc.components["memory"] {
+				hc.reportHealth(cs.ID+" memory", cs.MemoryStatus)
+			}
+		}
+	}
+
+	if hc.components["temperatures"] || hc.components["fans"] {
+		thermal, err := cli.GetThermal(hc.chassisID)
+		if err != nil {
+			return err
+		}
+		if hc.components["temperatures"] {
+			for _, sensor := range thermal.Temperatures {
+				if sensor.Status.State == "Absent" {
+					continue
+				}
+				hc.reportHealth(sensor.Name, sensor.Status)
+				warning, critical := hc.tempWarning, hc.tempCritical
+				if warning == nil {
+					warning = sensorThreshold(sensor.LowerThresholdNonCritical, sensor.UpperThresholdNonCritical)
+				}
+				if critical == nil {
+					critical = sensorThreshold(sensor.LowerThresholdCritical, sensor.UpperThresholdCritical)
+				}
+				hc.reportValue(sensor.Name, sensor.ReadingCelsius, "C", warning, critical)
+			}
+		}
+		if hc.components["fans"] {
+			for _, fan := range thermal.Fans {
+				if fan.Status.State == "Absent" {
+					continue
+				}
+				hc.reportHealth(fan.Name, fan.Status)
+				warning, critical := hc.fanWarning, hc.fanCritical
+				if warning == nil {
+					warning = sensorThreshold(fan.LowerThresholdNonCritical, 0)
+				}
+				if critical == nil {
+					critical = sensorThreshold(fan.LowerThresholdCritical, 0)
+				}
+				hc.reportValue(fan.Name, fan.Reading, " RPM", warning, critical)
+			}
+		}
+	}
+
+	if hc.components["power"] {
+		power, err := cli.GetPower(hc.chassisID)
+		if err != nil {
+			return err
+		}
+		for _, pc := range power.PowerControl {
+			hc.reportValue(pc.Name, pc.PowerConsumedWatts, " W", hc.powerWarning, hc.powerCritical)
+		}
+		for _, psu := range power.PowerSupplies {
+			hc.reportHealth(psu.Name, psu.Status)
+		}
+	}
+	return nil
+}
+
+// String returns a single status line followed by performance data.
+func (hc *healthCheck) String() string {
+	var b strings.Builder
+	b.WriteString("IDRAC ")
+	b.WriteString(checkStateNames[hc.state])
+	b.WriteString(" - ")
+	if len(hc.messages) > 0 {
+		b.WriteString(strings.Join(hc.messages, ", "))
+	} else {
+		b.WriteString(fmt.Sprintf("%d checks passed", hc.checks))
+	}
+	if len(hc.perfdata) > 0 {
+		b.WriteString(" | ")
+		b.WriteString(strings.Join(hc.perfdata, " "))
+	}
+	return b.String()
+}
+
+// healthCheckReport is the machine-readable result of the check-health
+// operation.
+type healthCheckReport struct {
+	State    string   `yaml:"state" json:"state" xml:"state"`
+	Checks   int      `yaml:"checks" json:"checks" xml:"checks"`
+	Messages []string `yaml:"messages" json:"messages" xml:"messages"`
+	Perfdata []string `yaml:"perfdata" json:"perfdata" xml:"perfdata"`
+}
+
+// runHealthCheck performs the check-health operation. The exit code of the
+// result is the state of the check.
+func runHealthCheck(cli *client.Client, opts *healthCheckOptions) *operationResult {
+	hc, err := newHealthCheck(opts)
+	if err == nil {
+		err = hc.run(cli)
+	}
+	if err != nil {
+		hc = &healthCheck{state: checkStateUnknown}
+		hc.messages = []string{strings.SplitN(err.Error(), "\n", 2)[0]}
+	}
+	return &operationResult{
+		data: &healthCheckReport{
+			State:    checkStateNames[hc.state],
+			Checks:   hc.checks,
+			Messages: append([]string{}, hc.messages...),
+			Perfdata: append([]string{}, hc.perfdata...),
+		},
+		text: func(w io.Writer) {
+			fmt.Fprintln(w, hc.String())
+		},
+		exitCode: hc.state,
+	}
+}
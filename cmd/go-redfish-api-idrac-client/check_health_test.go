@@ -0,0 +1,175 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseCheckThreshold(t *testing.T) {
+	testFailed := 0
+	for i, test := range []struct {
+		input     string
+		alert     []float64
+		noAlert   []float64
+		shouldErr bool
+	}{
+		{input: ""},
+		{input: "10", alert: []float64{-1, 10.5, 11}, noAlert: []float64{0, 5, 10}},
+		{input: "10:", alert: []float64{-1, 0, 9.9}, noAlert: []float64{10, 100, math.Inf(1)}},
+		{input: ":20", alert: []float64{-1, 21}, noAlert: []float64{0, 20}},
+		{input: "~:20", alert: []float64{20.1, 21}, noAlert: []float64{-100, 0, 20}},
+		{input: "10:20", alert: []float64{9, 21}, noAlert: []float64{10, 15, 20}},
+		{input: "@10:20", alert: []float64{10, 15, 20}, noAlert: []float64{9, 21}},
+		{input: "@~:0", alert: []float64{-1, 0}, noAlert: []float64{1}},
+		{input: "abc", shouldErr: true},
+		{input: "10:x", shouldErr: true},
+		{input: "20:10", shouldErr: true},
+	} {
+		threshold, err := parseCheckThreshold(test.input)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input %q, expected to pass, but threw error: %v", i, test.input, err)
+				testFailed++
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input %q, expected to throw error, but passed", i, test.input)
+			testFailed++
+			continue
+		}
+		if threshold.String() != test.input {
+			t.Logf("FAIL: Test %d: input %q, expected the same string, but got %q", i, test.input, threshold.String())
+			testFailed++
+		}
+		for _, v := range test.alert {
+			if !threshold.alert(v) {
+				t.Logf("FAIL: Test %d: input %q, expected alert for %v", i, test.input, v)
+				testFailed++
+			}
+		}
+		for _, v := range test.noAlert {
+			if threshold.alert(v) {
+				t.Logf("FAIL: Test %d: input %q, expected no alert for %v", i, test.input, v)
+				testFailed++
+			}
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestSensorThreshold(t *testing.T) {
+	testFailed := 0
+	for i, test := range []struct {
+		lower   float64
+		upper   float64
+		exp     string
+		alert   []float64
+		noAlert []float64
+	}{
+		{lower: 0, upper: 0, exp: "", noAlert: []float64{-100, 0, 100}},
+		{lower: 3, upper: 42, exp: "3:42", alert: []float64{2, 43}, noAlert: []float64{3, 20, 42}},
+		{lower: 0, upper: 42, exp: "~:42", alert: []float64{43}, noAlert: []float64{-5, 0, 42}},
+		{lower: 600, upper: 0, exp: "600:", alert: []float64{0, 599}, noAlert: []float64{600, 12000}},
+		{lower: -7.5, upper: 0, exp: "-7.5:", alert: []float64{-8}, noAlert: []float64{-7.5, 0, 100}},
+	} {
+		threshold := sensorThreshold(test.lower, test.upper)
+		if threshold.String() != test.exp {
+			t.Logf("FAIL: Test %d: expected %q, but got %q", i, test.exp, threshold.String())
+			testFailed++
+		}
+		for _, v := range test.alert {
+			if !threshold.alert(v) {
+				t.Logf("FAIL: Test %d: range %q, expected alert for %v", i, test.exp, v)
+				testFailed++
+			}
+		}
+		for _, v := range test.noAlert {
+			if threshold.alert(v) {
+				t.Logf("FAIL: Test %d: range %q, expected no alert for %v", i, test.exp, v)
+				testFailed++
+			}
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestHealthCheckState(t *testing.T) {
+	warning, _ := parseCheckThreshold("~:40")
+	critical, _ := parseCheckThreshold("~:50")
+	testFailed := 0
+	for i, test := range []struct {
+		health   []string
+		values   []float64
+		exp      int
+		messages []string
+		perfdata []string
+	}{
+		{
+			health:   []string{"OK", ""},
+			values:   []float64{25},
+			exp:      checkStateOK,
+			perfdata: []string{"inlet_temp=25;~:40;~:50"},
+		},
+		{
+			values:   []float64{25, 45},
+			exp:      checkStateWarning,
+			messages: []string{"Inlet Temp is 45C (warning ~:40)"},
+			perfdata: []string{"inlet_temp=25;~:40;~:50", "inlet_temp=45;~:40;~:50"},
+		},
+		{
+			values:   []float64{45, 55.5},
+			exp:      checkStateCritical,
+			messages: []string{"Inlet Temp is 45C (warning ~:40)", "Inlet Temp is 55.5C (critical ~:50)"},
+			perfdata: []string{"inlet_temp=45;~:40;~:50", "inlet_temp=55.5;~:40;~:50"},
+		},
+		{
+			health:   []string{"Unavailable"},
+			exp:      checkStateUnknown,
+			messages: []string{"Inlet Temp health is Unavailable"},
+		},
+		{
+			// WARNING takes precedence over UNKNOWN, and CRITICAL over
+			// both.
+			health:   []string{"Unavailable", "Warning"},
+			exp:      checkStateWarning,
+			messages: []string{"Inlet Temp health is Unavailable", "Inlet Temp health is Warning"},
+		},
+		{
+			health:   []string{"Critical", "Warning", "Unavailable"},
+			exp:      checkStateCritical,
+			messages: []string{"Inlet Temp health is Critical", "Inlet Temp health is Warning", "Inlet Temp health is Unavailable"},
+		},
+	} {
+		hc := &healthCheck{}
+		for _, health := range test.health {
+			hc.reportHealth("Inlet Temp", client.HealthStatus{Health: health, State: "Enabled"})
+		}
+		for _, value := range test.values {
+			hc.reportValue("Inlet Temp", value, "C", warning, critical)
+		}
+		if hc.state != test.exp {
+			t.Logf("FAIL: Test %d: expected state %s, but got %s", i, checkStateNames[test.exp], checkStateNames[hc.state])
+			testFailed++
+		}
+		if !reflect.DeepEqual(hc.messages, test.messages) {
+			t.Logf("FAIL: Test %d: expected messages %q, but got %q", i, test.messages, hc.messages)
+			testFailed++
+		}
+		if !reflect.DeepEqual(hc.perfdata, test.perfdata) {
+			t.Logf("FAIL: Test %d: expected perfdata %q, but got %q", i, test.perfdata, hc.perfdata)
+			testFailed++
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
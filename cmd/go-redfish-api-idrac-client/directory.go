@@ -0,0 +1,164 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"text/tabwriter"
+)
+
+// directoryOptions holds the arguments of the directory service operations.
+type directoryOptions struct {
+	configFile     string
+	dryRun         bool
+	rotatePassword bool
+	config         *directoryConfig
+}
+
+func (opts *directoryOptions) bindFlags() {
+	flag.StringVar(&opts.configFile, "directory.config", "", "apply-directory: YAML file with the desired LDAP and Active Directory configuration")
+	flag.BoolVar(&opts.dryRun, "directory.dry-run", false, "apply-directory: report the differences without applying them")
+	flag.BoolVar(&opts.rotatePassword, "directory.rotate-password", false, "apply-directory: apply the bind password even when the other settings are unchanged")
+}
+
+// directoryConfigEntry is the desired configuration of a directory service.
+// The bind password comes from a credential source, e.g. password_env.
+type directoryConfigEntry struct {
+	client.DirectoryService `yaml:",inline"`
+	BindPassword            *client.CredentialSource `yaml:"bind_password"`
+}
+
+// directoryConfig is the desired configuration of directory services.
+type directoryConfig struct {
+	LDAP            *directoryConfigEntry `yaml:"ldap"`
+	ActiveDirectory *directoryConfigEntry `yaml:"active_directory"`
+}
+
+// init loads the desired configuration.
+func (opts *directoryOptions) init() error {
+	if opts.configFile == "" {
+		return fmt.Errorf("the --directory.config argument is required")
+	}
+	b, err := ioutil.ReadFile(opts.configFile)
+	if err != nil {
+		return err
+	}
+	cfg := &directoryConfig{}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("directory configuration %s: %s", opts.configFile, err)
+	}
+	if cfg.LDAP == nil && cfg.ActiveDirectory == nil {
+		return fmt.Errorf("directory configuration %s has neither ldap nor active_directory", opts.configFile)
+	}
+	opts.config = cfg
+	return nil
+}
+
+// desired returns the desired configuration of a directory service for
+// a host, i.e. with the bind password.
+func (entry *directoryConfigEntry) desired(host string) (*client.DirectoryService, error) {
+	service := entry.DirectoryService
+	if entry.BindPassword == nil {
+		return &service, nil
+	}
+	provider, err := entry.BindPassword.Provider()
+	if err != nil {
+		return nil, err
+	}
+	creds, err := provider.GetCredentials(host)
+	if err != nil {
+		return nil, err
+	}
+	authentication := &client.DirectoryAuthentication{}
+	if service.Authentication != nil {
+		*authentication = *service.Authentication
+	}
+	authentication.Password = creds.Password
+	service.Authentication = authentication
+	return &service, nil
+}
+
+// directoryReport is the outcome of the apply-directory operation.
+type directoryReport struct {
+	Host    string                           `yaml:"host" json:"host" xml:"host"`
+	DryRun  bool                             `yaml:"dry_run" json:"dry_run" xml:"dry_run"`
+	Applied bool                             `yaml:"applied" json:"applied" xml:"applied"`
+	Changes []*client.DirectoryServiceChange `yaml:"changes" json:"changes" xml:"changes"`
+}
+
+// runDirectoryOperation performs the directory service operations.
+func runDirectoryOperation(cli *client.Client, host string, operation string, opts *directoryOptions) (*operationResult, error) {
+	services, err := cli.GetDirectoryServices()
+	if err != nil {
+		return nil, err
+	}
+	if operation == "get-directory" {
+		return &operationResult{
+			data: services,
+			text: func(w io.Writer) {
+				b, _ := yaml.Marshal(services)
+				fmt.Fprintf(w, "Host: %s\n%s", host, b)
+			},
+		}, nil
+	}
+
+	report := &directoryReport{
+		Host:    host,
+		DryRun:  opts.dryRun,
+		Changes: []*client.DirectoryServiceChange{},
+	}
+	for _, entry := range []struct {
+		serviceType string
+		current     *client.DirectoryService
+		config      *directoryConfigEntry
+	}{
+		{client.DirectoryServiceLDAP, services.LDAP, opts.config.LDAP},
+		{client.DirectoryServiceActiveDirectory, services.ActiveDirectory, opts.config.ActiveDirectory},
+	} {
+		if entry.config == nil {
+			continue
+		}
+		desired, err := entry.config.desired(host)
+		if err != nil {
+			return nil, fmt.Errorf("%s bind password: %s", entry.serviceType, err)
+		}
+		changes, apply := client.DiffDirectoryService(entry.serviceType, entry.current, desired, opts.rotatePassword)
+		report.Changes = append(report.Changes, changes...)
+		if opts.dryRun || len(changes) == 0 {
+			continue
+		}
+		if err := cli.UpdateDirectoryService(entry.serviceType, apply); err != nil {
+			return nil, fmt.Errorf("failed applying %s configuration: %s", entry.serviceType, err)
+		}
+		report.Applied = true
+	}
+	return &operationResult{
+		data: report,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			if len(report.Changes) == 0 {
+				fmt.Fprintf(w, "No changes, the live configuration matches the desired configuration\n")
+				return
+			}
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "SERVICE\tFIELD\tCURRENT\tDESIRED")
+			for _, change := range report.Changes {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", change.Service, change.Field, change.Current, change.Desired)
+			}
+			tw.Flush()
+			if report.Applied {
+				fmt.Fprintf(w, "Applied %d changes\n", len(report.Changes))
+			} else {
+				fmt.Fprintf(w, "Dry run, %d changes not applied\n", len(report.Changes))
+			}
+		},
+	}, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/drift"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// driftOptions holds the arguments of the snapshot-config and drift
+// operations.
+type driftOptions struct {
+	snapshotFile      string
+	baselineFile      string
+	denylist          string
+	denylistFile      string
+	noDefaultDenylist bool
+
+	// The denylist is loaded once and shared by the hosts of a fleet.
+	denylistOnce sync.Once
+	patterns     drift.Denylist
+	denylistErr  error
+}
+
+func (opts *driftOptions) bindFlags() {
+	flag.StringVar(&opts.snapshotFile, "snapshot.file", "", "snapshot-config: JSON file of the snapshot, {host} is replaced with the host, default stdout")
+	flag.StringVar(&opts.baselineFile, "drift.baseline", "", "drift: JSON file of the baseline snapshot, {host} is replaced with the host")
+	flag.StringVar(&opts.denylist, "drift.denylist", "", "drift: comma-separated patterns of the ignored keys, e.g. idrac.CurrentIPv4.1.*")
+	flag.StringVar(&opts.denylistFile, "drift.denylist-file", "", "drift: file with a pattern of the ignored keys per line")
+	flag.BoolVar(&opts.noDefaultDenylist, "drift.no-default-denylist", false, "drift: do not ignore the volatile keys of the default denylist")
+}
+
+// init validates the snapshot file of the snapshot-config operation against
+// several hosts, i.e. each host requires its own file.
+func (opts *driftOptions) init(targets int) error {
+	if targets < 2 || strings.Contains(opts.snapshotFile, "{host}") {
+		return nil
+	}
+	if opts.snapshotFile == "" {
+		return fmt.Errorf("--snapshot.file is empty, the snapshots of %d hosts require {host} in the file path", targets)
+	}
+	return fmt.Errorf("--snapshot.file %s is the same for every host, use {host} with --inventory or --group", opts.snapshotFile)
+}
+
+// loadDenylist returns the default denylist, unless disabled, along with
+// the patterns of the arguments.
+func (opts *driftOptions) loadDenylist() (drift.Denylist, error) {
+	opts.denylistOnce.Do(func() {
+		denylist := drift.Denylist{}
+		if !opts.noDefaultDenylist {
+			denylist = append(denylist, drift.DefaultDenylist...)
+		}
+		patterns, err := drift.NewDenylist(splitList(opts.denylist))
+		if err != nil {
+			opts.denylistErr = err
+			return
+		}
+		denylist = append(denylist, patterns...)
+		if opts.denylistFile != "" {
+			patterns, err := drift.LoadDenylist(opts.denylistFile)
+			if err != nil {
+				opts.denylistErr = err
+				return
+			}
+			denylist = append(denylist, patterns...)
+		}
+		opts.patterns = denylist
+	})
+	return opts.patterns, opts.denylistErr
+}
+
+// driftReport is the outcome of the drift operation.
+type driftReport struct {
+	Host        string              `yaml:"host" json:"host" xml:"host"`
+	Baseline    string              `yaml:"baseline" json:"baseline" xml:"baseline"`
+	Differences []*drift.Difference `yaml:"differences" json:"differences" xml:"differences"`
+}
+
+// runDriftOperation performs the snapshot-config and drift operations. The
+// drift operation exits with code 2 when the live configuration differs
+// from the baseline.
+func runDriftOperation(cli *client.Client, host string, operation, format string, opts *driftOptions) (*operationResult, error) {
+	if operation == "drift" {
+		return runDrift(cli, host, format, opts)
+	}
+	snapshot, err := drift.NewSnapshot(cli, host, client.DefaultSystemID)
+	if err != nil {
+		return nil, err
+	}
+	s, err := snapshot.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if opts.snapshotFile == "" {
+		return &operationResult{
+			data: snapshot,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "%s\n", s)
+			},
+		}, nil
+	}
+	fp := strings.ReplaceAll(opts.snapshotFile, "{host}", host)
+	if err := ioutil.WriteFile(fp, append(s, '\n'), 0600); err != nil {
+		return nil, err
+	}
+	return &operationResult{
+		data: map[string]interface{}{
+			"file":      fp,
+			"version":   snapshot.Version,
+			"timestamp": snapshot.Timestamp,
+		},
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Snapshot: %s, version %d\n", fp, snapshot.Version)
+		},
+	}, nil
+}
+
+// runDrift compares the live configuration of a host with its baseline.
+func runDrift(cli *client.Client, host, format string, opts *driftOptions) (*operationResult, error) {
+	if opts.baselineFile == "" {
+		return nil, fmt.Errorf("--drift.baseline is empty")
+	}
+	denylist, err := opts.loadDenylist()
+	if err != nil {
+		return nil, err
+	}
+	fp := strings.ReplaceAll(opts.baselineFile, "{host}", host)
+	baseline, err := drift.LoadSnapshot(fp)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := drift.NewSnapshot(cli, host, baseline.SystemID)
+	if err != nil {
+		return nil, err
+	}
+	report := &driftReport{
+		Host:        host,
+		Baseline:    fp,
+		Differences: drift.Compare(baseline, snapshot, denylist),
+	}
+	result := &operationResult{
+		data: report,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Baseline: %s, %s\n", fp, baseline.Timestamp.Format(time.RFC3339))
+			if len(report.Differences) == 0 {
+				fmt.Fprintf(w, "No drift, the host matches the baseline\n")
+				return
+			}
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "RESOURCE\tTARGET\tNAME\tBASELINE\tCURRENT\tSTATUS")
+			for _, difference := range report.Differences {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", difference.Resource, difference.Target, difference.Name,
+					difference.Baseline, difference.Current, difference.Status)
+			}
+			tw.Flush()
+		},
+	}
+	if format == "table" || format == "csv" {
+		// The tabular formats have a row per difference.
+		result.data = report.Differences
+	}
+	if len(report.Differences) > 0 {
+		result.exitCode = 2
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// ethernetOptions holds the arguments of the ethernet interface operations.
+type ethernetOptions struct {
+	managerID   string
+	interfaceID string
+	configFile  string
+	newHost     string
+	timeout     time.Duration
+}
+
+func (opts *ethernetOptions) bindFlags() {
+	flag.StringVar(&opts.managerID, "ethernet.manager", "iDRAC.Embedded.1", "get-ethernet-interfaces, set-ethernet-interface: manager id")
+	flag.StringVar(&opts.interfaceID, "ethernet.interface", "NIC.1", "set-ethernet-interface: ethernet interface id")
+	flag.StringVar(&opts.configFile, "ethernet.config", "", "set-ethernet-interface: YAML file with the interface changes, {host} is replaced with the host")
+	flag.StringVar(&opts.newHost, "ethernet.new-host", "", "set-ethernet-interface: host to reconnect to after the changes, {host} is replaced with the host, default the first static IPv4 address of the changes, or the host")
+	flag.DurationVar(&opts.timeout, "ethernet.timeout", 5*time.Minute, "set-ethernet-interface: the maximum time to wait for the interface on the host")
+}
+
+// init validates the arguments of the set-ethernet-interface operation. When
+// the operation runs against many hosts, the static addresses and the host to
+// reconnect to must come from per-host arguments, i.e. with {host}.
+func (opts *ethernetOptions) init(fleet bool) error {
+	if !fleet {
+		return nil
+	}
+	if opts.newHost != "" && !strings.Contains(opts.newHost, "{host}") {
+		return fmt.Errorf("--ethernet.new-host %s is the same for every host, use {host} with --inventory or --group", opts.newHost)
+	}
+	if strings.Contains(opts.configFile, "{host}") {
+		return nil
+	}
+	changes, err := opts.changes("")
+	if err != nil {
+		return err
+	}
+	if len(changes.IPv4StaticAddresses) > 0 || len(changes.IPv6StaticAddresses) > 0 {
+		return fmt.Errorf("--ethernet.config %s assigns the same static addresses to every host, use {host} in the file path with --inventory or --group", opts.configFile)
+	}
+	return nil
+}
+
+// changes returns the interface changes of a host.
+func (opts *ethernetOptions) changes(host string) (*client.EthernetInterface, error) {
+	if opts.configFile == "" {
+		return nil, fmt.Errorf("--ethernet.config is empty")
+	}
+	fp := strings.ReplaceAll(opts.configFile, "{host}", host)
+	b, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	changes := &client.EthernetInterface{}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(changes); err != nil {
+		return nil, fmt.Errorf("ethernet interface changes %s: %s", fp, err)
+	}
+	return changes, nil
+}
+
+// ethernetChange is the outcome of the set-ethernet-interface operation.
+type ethernetChange struct {
+	Host      string                    `yaml:"host" json:"host" xml:"host"`
+	NewHost   string                    `yaml:"new_host" json:"new_host" xml:"new_host"`
+	Interface *client.EthernetInterface `yaml:"interface" json:"interface" xml:"interface"`
+}
+
+// runEthernetOperation performs the get-ethernet-interfaces and
+// set-ethernet-interface operations. The set-ethernet-interface operation
+// confirms the changes by reconnecting to the manager.
+func runEthernetOperation(cli *client.Client, host string, operation string, opts *ethernetOptions) (*operationResult, error) {
+	if operation == "get-ethernet-interfaces" {
+		interfaces, err := cli.GetManagerEthernetInterfaces(opts.managerID)
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: interfaces,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				for _, iface := range interfaces {
+					writeEthernetInterface(w, iface)
+				}
+			},
+		}, nil
+	}
+
+	changes, err := opts.changes(host)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(cli.Context(), opts.timeout)
+	defer cancel()
+	newHost := strings.ReplaceAll(opts.newHost, "{host}", host)
+	reconnected, iface, err := cli.ChangeManagerEthernetInterface(ctx, opts.managerID, opts.interfaceID, changes, newHost)
+	if err != nil {
+		return nil, err
+	}
+	change := &ethernetChange{
+		Host:      host,
+		NewHost:   reconnected.GetHost(),
+		Interface: iface,
+	}
+	return &operationResult{
+		data: change,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Reconnected: %s\n", change.NewHost)
+			writeEthernetInterface(w, iface)
+		},
+	}, nil
+}
+
+// writeEthernetInterface writes an ethernet interface in the text format.
+func writeEthernetInterface(w io.Writer, iface *client.EthernetInterface) {
+	fmt.Fprintf(w, "Interface: %s\n", iface.ID)
+	fmt.Fprintf(w, "  MAC Address: %s\n", iface.MACAddress)
+	fmt.Fprintf(w, "  Host Name: %s\n", iface.HostName)
+	fmt.Fprintf(w, "  FQDN: %s\n", iface.FQDN)
+	if iface.DHCPv4 != nil && iface.DHCPv4.DHCPEnabled != nil {
+		fmt.Fprintf(w, "  DHCPv4: %t\n", *iface.DHCPv4.DHCPEnabled)
+	}
+	for _, address := range iface.IPv4Addresses {
+		fmt.Fprintf(w, "  IPv4 Address: %s, mask %s, gateway %s, %s\n", address.Address, address.SubnetMask, address.Gateway, address.AddressOrigin)
+	}
+	if iface.DHCPv6 != nil {
+		fmt.Fprintf(w, "  DHCPv6: %s\n", iface.DHCPv6.OperatingMode)
+	}
+	for _, address := range iface.IPv6Addresses {
+		prefix := ""
+		if address.PrefixLength != nil {
+			prefix = fmt.Sprintf("/%d", *address.PrefixLength)
+		}
+		fmt.Fprintf(w, "  IPv6 Address: %s%s, %s\n", address.Address, prefix, address.AddressOrigin)
+	}
+	servers := []string{}
+	for _, server := range iface.NameServers {
+		if server != "" && server != "::" && server != "0.0.0.0" {
+			servers = append(servers, server)
+		}
+	}
+	fmt.Fprintf(w, "  DNS Servers: %s\n", strings.Join(servers, ", "))
+	if iface.VLAN != nil && iface.VLAN.VLANEnable != nil {
+		vlan := "disabled"
+		if *iface.VLAN.VLANEnable && iface.VLAN.VLANID != nil {
+			vlan = fmt.Sprintf("%d", *iface.VLAN.VLANID)
+		}
+		fmt.Fprintf(w, "  VLAN: %s\n", vlan)
+	}
+}
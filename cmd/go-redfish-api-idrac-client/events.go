@@ -0,0 +1,263 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/events"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// eventOptions holds the arguments of the event service operations.
+type eventOptions struct {
+	destination      string
+	eventTypes       string
+	messageIDs       string
+	registryPrefixes string
+	resourceTypes    string
+	context          string
+	protocol         string
+	subscriptionID   string
+	testEventType    string
+	testMessageID    string
+	listenAddress    string
+	listenPath       string
+	tlsCertFile      string
+	tlsKeyFile       string
+	outputFile       string
+	formatType       string
+	resolve          bool
+}
+
+func (opts *eventOptions) bindFlags() {
+	flag.StringVar(&opts.destination, "events.destination", "", "create-event-subscription: HTTPS URL receiving the events")
+	flag.StringVar(&opts.eventTypes, "events.event-types", "", "create-event-subscription: comma-separated event types, e.g. Alert,StatusChange")
+	flag.StringVar(&opts.messageIDs, "events.message-ids", "", "create-event-subscription, tail-events: comma-separated message ids")
+	flag.StringVar(&opts.registryPrefixes, "events.registry-prefixes", "", "create-event-subscription: comma-separated message registry prefixes, e.g. EEMI")
+	flag.StringVar(&opts.resourceTypes, "events.resource-types", "", "create-event-subscription: comma-separated resource types")
+	flag.StringVar(&opts.context, "events.context", "", "create-event-subscription: opaque string the events carry back to the destination")
+	flag.StringVar(&opts.protocol, "events.protocol", client.DefaultEventProtocol, "create-event-subscription: protocol of the event delivery")
+	flag.StringVar(&opts.subscriptionID, "events.subscription-id", "", "delete-event-subscription: id of the subscription")
+	flag.StringVar(&opts.testEventType, "events.test.event-type", "Alert", "submit-test-event: type of the test event")
+	flag.StringVar(&opts.testMessageID, "events.test.message-id", "", "submit-test-event: message id of the test event, e.g. TMP0118")
+	flag.StringVar(&opts.listenAddress, "events.listen", ":8443", "listen-events: address to listen on")
+	flag.StringVar(&opts.listenPath, "events.path", "", "listen-events: path accepting events, default any path")
+	flag.StringVar(&opts.tlsCertFile, "events.tls-cert", "", "listen-events: TLS certificate file, default self-signed certificate")
+	flag.StringVar(&opts.tlsKeyFile, "events.tls-key", "", "listen-events: TLS key file")
+	flag.StringVar(&opts.formatType, "events.format-type", "", "tail-events: event format type, either Event or MetricReport")
+	flag.BoolVar(&opts.resolve, "events.resolve", false, "listen-events, tail-events: add the resolution from message registries to the events")
+	flag.StringVar(&opts.outputFile, "events.output-file", "", "listen-events: file the events are appended to in JSON Lines format, default stdout")
+}
+
+// splitList returns the non-empty items of a comma-separated list.
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// eventChange is the outcome of an event service operation changing
+// subscriptions or submitting test events.
+type eventChange struct {
+	Host           string `yaml:"host" json:"host" xml:"host"`
+	Operation      string `yaml:"operation" json:"operation" xml:"operation"`
+	SubscriptionID string `yaml:"subscription_id" json:"subscription_id" xml:"subscription_id"`
+	EventType      string `yaml:"event_type" json:"event_type" xml:"event_type"`
+}
+
+// runEventOperation performs the event service operations.
+func runEventOperation(cli *client.Client, host string, operation string, opts *eventOptions) (*operationResult, error) {
+	switch operation {
+	case "get-event-service":
+		svc, err := cli.GetEventService()
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: svc,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Service Enabled: %t\n", svc.ServiceEnabled)
+				fmt.Fprintf(w, "Delivery Retry: %d attempts, %ds interval\n", svc.DeliveryRetryAttempts, svc.DeliveryRetryIntervalSeconds)
+				fmt.Fprintf(w, "Event Types: %s\n", strings.Join(svc.EventTypesForSubscription, ", "))
+				fmt.Fprintf(w, "Registry Prefixes: %s\n", strings.Join(svc.RegistryPrefixes, ", "))
+				fmt.Fprintf(w, "Server-Sent Events: %s\n", svc.ServerSentEventURI)
+			},
+		}, nil
+	case "list-event-subscriptions":
+		subscriptions, err := cli.ListEventSubscriptions()
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: subscriptions,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				for _, s := range subscriptions {
+					fmt.Fprintf(w, "Subscription: %s | Destination: %s | Protocol: %s | Event Types: %v | Context: %s\n",
+						s.ID, s.Destination, s.Protocol, s.EventTypes, s.Context)
+				}
+			},
+		}, nil
+	case "create-event-subscription":
+		subscription, err := cli.CreateEventSubscription(&client.EventSubscriptionRequest{
+			Destination:      opts.destination,
+			EventTypes:       splitList(opts.eventTypes),
+			MessageIDs:       splitList(opts.messageIDs),
+			RegistryPrefixes: splitList(opts.registryPrefixes),
+			ResourceTypes:    splitList(opts.resourceTypes),
+			Context:          opts.context,
+			Protocol:         opts.protocol,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: subscription,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Subscription %s created for %s\n", subscription.ID, subscription.Destination)
+			},
+		}, nil
+	case "delete-event-subscription":
+		if opts.subscriptionID == "" {
+			return nil, fmt.Errorf("the --events.subscription-id argument is required")
+		}
+		if err := cli.DeleteEventSubscription(opts.subscriptionID); err != nil {
+			return nil, err
+		}
+		change := &eventChange{Host: host, Operation: operation, SubscriptionID: opts.subscriptionID}
+		return &operationResult{
+			data: change,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Subscription %s deleted\n", change.SubscriptionID)
+			},
+		}, nil
+	case "submit-test-event":
+		if err := cli.SubmitTestEvent(opts.testEventType, opts.testMessageID); err != nil {
+			return nil, err
+		}
+		change := &eventChange{Host: host, Operation: operation, EventType: opts.testEventType}
+		return &operationResult{
+			data: change,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Test event %s submitted\n", change.EventType)
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("the --operation %s is supported by API, but not this utility", operation)
+}
+
+// runEventReceiver performs the listen-events operation. The events from
+// the hosts of the profiles and groups in the configuration file carry the
+// name of the profile.
+func runEventReceiver(cfg *client.Config, opts *eventOptions, registryOpts *registryOptions) error {
+	receiver := events.NewReceiver()
+	receiver.Address = opts.listenAddress
+	receiver.Path = opts.listenPath
+	receiver.CertFile = opts.tlsCertFile
+	receiver.KeyFile = opts.tlsKeyFile
+	if cfg != nil {
+		for name := range cfg.Profiles {
+			profile, err := cfg.GetProfile(name)
+			if err != nil {
+				return err
+			}
+			receiver.AddProfile(profile)
+		}
+		for name := range cfg.Groups {
+			profiles, err := cfg.GetGroupProfiles(name)
+			if err != nil {
+				return err
+			}
+			for _, profile := range profiles {
+				receiver.AddProfile(profile)
+			}
+		}
+	}
+	if opts.resolve {
+		// The receiver has no connection to the hosts, i.e. it resolves
+		// the events with the cached message registries.
+		registries, err := client.NewMessageRegistries(registryOpts.cacheDir)
+		if err != nil {
+			return err
+		}
+		receiver.AddHandler(events.HandlerFunc(func(event *client.Event) error {
+			registries.ResolveEvent(event)
+			return nil
+		}))
+	}
+	if opts.outputFile != "" {
+		h, err := events.NewFileHandler(opts.outputFile)
+		if err != nil {
+			return err
+		}
+		defer h.Close()
+		receiver.AddHandler(h)
+	} else {
+		receiver.AddHandler(events.NewJSONHandler(os.Stdout))
+	}
+	defer receiver.Close()
+	log.Infof("Receiving events on %s%s", opts.listenAddress, opts.listenPath)
+	return receiver.ListenAndServe()
+}
+
+// runEventTail performs the tail-events operation, i.e. it prints the
+// events of the Server-Sent Events stream until interrupted. The text
+// format prints a line per event record, while the other formats print
+// the events in JSON Lines format.
+func runEventTail(cli *client.Client, opts *eventOptions, registryOpts *registryOptions, format string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var registries *client.MessageRegistries
+	if opts.resolve {
+		var err error
+		registries, err = cli.GetMessageRegistries(registryOpts.cacheDir)
+		if err != nil {
+			return err
+		}
+	}
+	stream, err := cli.StreamEvents(ctx, &client.EventStreamFilter{
+		EventFormatType: opts.formatType,
+		MessageIDs:      splitList(opts.messageIDs),
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for event := range stream {
+		if registries != nil {
+			registries.ResolveEvent(event)
+		}
+		if format != "text" {
+			if err := enc.Encode(event); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, record := range event.Events {
+			fmt.Fprintf(os.Stdout, "%s | %s | %s | %s | %s\n",
+				record.EventTimestamp, event.Host, record.Severity, record.MessageID, record.Message)
+			if record.Resolution != "" {
+				fmt.Fprintf(os.Stdout, "  Resolution: %s\n", record.Resolution)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/firmware"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"sync"
+	"text/tabwriter"
+)
+
+// firmwareOptions holds the arguments of the firmware operations.
+type firmwareOptions struct {
+	installed        bool
+	catalogFile      string
+	model            string
+	imageURI         string
+	imageFile        string
+	transferProtocol string
+	targets          string
+	applyTime        string
+
+	// The catalog is loaded once and shared by the hosts of a fleet.
+	catalogOnce sync.Once
+	catalog     *firmware.Catalog
+	catalogErr  error
+}
+
+func (opts *firmwareOptions) bindFlags() {
+	flag.BoolVar(&opts.installed, "firmware.installed", false, "get-firmware: only the installed firmware, i.e. without the previous and the available firmware")
+	flag.StringVar(&opts.catalogFile, "firmware.catalog", "", "check-firmware: Dell Update Catalog, e.g. Catalog.xml, or YAML baseline")
+	flag.StringVar(&opts.model, "firmware.model", "", "check-firmware: server model, e.g. PowerEdge R640, instead of the model of the system")
+	flag.StringVar(&opts.imageURI, "firmware.image-uri", "", "firmware-update: URI of the image the service downloads, e.g. http://10.10.10.1/BIOS.EXE")
+	flag.StringVar(&opts.imageFile, "firmware.file", "", "firmware-update: image file uploaded to the service")
+	flag.StringVar(&opts.transferProtocol, "firmware.transfer-protocol", "", "firmware-update: transfer protocol of the image URI, e.g. HTTP, HTTPS, NFS, or CIFS")
+	flag.StringVar(&opts.targets, "firmware.targets", "", "firmware-update: comma-separated list of the resources updated with the image URI")
+	flag.StringVar(&opts.applyTime, "firmware.apply-time", "", "firmware-update: apply time of the update, i.e. Immediate or OnReset")
+}
+
+func (opts *firmwareOptions) loadCatalog() (*firmware.Catalog, error) {
+	opts.catalogOnce.Do(func() {
+		if opts.catalogFile == "" {
+			opts.catalogErr = fmt.Errorf("--firmware.catalog is empty")
+			return
+		}
+		opts.catalog, opts.catalogErr = firmware.LoadCatalog(opts.catalogFile)
+	})
+	return opts.catalog, opts.catalogErr
+}
+
+// runFirmwareOperation performs the firmware operations.
+func runFirmwareOperation(cli *client.Client, host string, operation, format string, opts *firmwareOptions) (*operationResult, error) {
+	switch operation {
+	case "check-firmware":
+		return runFirmwareCompliance(cli, host, format, opts)
+	case "firmware-update":
+		return runFirmwareUpdate(cli, host, opts)
+	}
+	inventory, err := cli.GetFirmwareInventory()
+	if err != nil {
+		return nil, err
+	}
+	if opts.installed {
+		installed := []*client.SoftwareInventory{}
+		for _, item := range inventory {
+			if item.InstallState == "Installed" {
+				installed = append(installed, item)
+			}
+		}
+		inventory = installed
+	}
+	return &operationResult{
+		data: inventory,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Firmware: %d components\n", len(inventory))
+			for _, item := range inventory {
+				updateable := "no"
+				if item.Updateable {
+					updateable = "yes"
+				}
+				fmt.Fprintf(w, "%s | %s | %s | %s | %s | %s | updateable: %s\n",
+					item.InstallState, item.ComponentID, item.FQDD, item.Name, item.Version, item.ReleaseDate, updateable)
+			}
+		},
+	}, nil
+}
+
+// runFirmwareCompliance performs the check-firmware operation, i.e. it
+// compares the installed firmware with a catalog. The operation exits with
+// code 1 when any firmware component is outdated.
+func runFirmwareCompliance(cli *client.Client, host, format string, opts *firmwareOptions) (*operationResult, error) {
+	catalog, err := opts.loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	model := opts.model
+	if model == "" {
+		systems, err := cli.GetComputerSystems()
+		if err != nil {
+			return nil, err
+		}
+		for _, cs := range systems {
+			if cs.Model != "" {
+				model = cs.Model
+				break
+			}
+		}
+		if model == "" {
+			return nil, fmt.Errorf("the model of the system is unknown, use --firmware.model")
+		}
+	}
+	inventory, err := cli.GetFirmwareInventory()
+	if err != nil {
+		return nil, err
+	}
+	report := firmware.CheckCompliance(catalog, model, inventory)
+	result := &operationResult{
+		data: report,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Model: %s\n", report.Model)
+			fmt.Fprintf(w, "Firmware: %d current, %d outdated, %d unknown\n",
+				report.Summary.Current, report.Summary.Outdated, report.Summary.Unknown)
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "STATUS\tCOMPONENT\tFQDD\tNAME\tINSTALLED\tTARGET\tPATH")
+			for _, entry := range report.Components {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", entry.Status, entry.ComponentID,
+					entry.FQDD, entry.Name, entry.InstalledVersion, entry.TargetVersion, entry.Path)
+			}
+			tw.Flush()
+		},
+	}
+	if format == "table" || format == "csv" {
+		// The tabular formats have a row per component.
+		result.data = report.Components
+	}
+	if !report.IsCompliant() {
+		result.exitCode = 1
+	}
+	return result, nil
+}
+
+// runFirmwareUpdate performs the firmware-update operation, i.e. it either
+// uploads an image file, or instructs the service to download an image.
+func runFirmwareUpdate(cli *client.Client, host string, opts *firmwareOptions) (*operationResult, error) {
+	var handle *client.TaskHandle
+	var err error
+	switch {
+	case opts.imageFile != "" && opts.imageURI != "":
+		return nil, fmt.Errorf("--firmware.file and --firmware.image-uri are mutually exclusive")
+	case opts.imageFile != "":
+		// The progress is logged in 10 percent steps.
+		var step int64 = -1
+		handle, err = cli.PushUpdate(opts.imageFile, opts.applyTime, func(sent, total int64) {
+			if total == 0 || sent*10/total == step {
+				return
+			}
+			step = sent * 10 / total
+			log.Infof("%s: uploaded %d%% of %s", host, step*10, opts.imageFile)
+		})
+	case opts.imageURI != "":
+		handle, err = cli.SimpleUpdate(opts.imageURI, opts.transferProtocol, splitList(opts.targets), opts.applyTime)
+	default:
+		return nil, fmt.Errorf("either --firmware.file or --firmware.image-uri is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &operationResult{
+		data: handle,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Task: %s\n", handle.TaskURI)
+			if handle.JobID != "" {
+				fmt.Fprintf(w, "Job: %s\n", handle.JobID)
+			}
+		},
+	}, nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fleetOptions holds the arguments of running an operation across hosts.
+type fleetOptions struct {
+	inventoryFile string
+	groups        string
+	workers       int
+	timeout       time.Duration
+}
+
+func (opts *fleetOptions) bindFlags() {
+	flag.StringVar(&opts.inventoryFile, "inventory", "", "file with hostnames, ip addresses, or CIDR ranges to run the operation against")
+	flag.StringVar(&opts.groups, "group", "", "comma-separated host groups from the configuration file")
+	flag.IntVar(&opts.workers, "workers", 10, "number of hosts processed concurrently")
+	flag.DurationVar(&opts.timeout, "timeout", 5*time.Minute, "timeout of the operation per host")
+}
+
+func (opts *fleetOptions) enabled() bool {
+	return opts.inventoryFile != "" || opts.groups != ""
+}
+
+// fleetTarget is a host the operation runs against.
+type fleetTarget struct {
+	host      string
+	profile   string
+	newClient func() (*client.Client, error)
+}
+
+// newHostTargets returns the fleet targets of hosts sharing the settings
+// of a client.
+func newHostTargets(cli *client.Client, hosts []string) []*fleetTarget {
+	targets := []*fleetTarget{}
+	for _, host := range hosts {
+		host := host
+		targets = append(targets, &fleetTarget{
+			host: host,
+			newClient: func() (*client.Client, error) {
+				hostCli := cli.Clone()
+				if err := hostCli.SetHost(host); err != nil {
+					return nil, err
+				}
+				return hostCli, nil
+			},
+		})
+	}
+	return targets
+}
+
+// newFleetTargets returns the fleet targets of the host, the hosts of the
+// inventory file, and the hosts of the groups.
+func newFleetTargets(cli *client.Client, host string, cfg *client.Config, overrides, fallback *client.HostProfile, opts *fleetOptions) ([]*fleetTarget, error) {
+	hosts := newInventory()
+	if host != "" {
+		hosts.addHost(host)
+	}
+	if opts.inventoryFile != "" {
+		if err := hosts.addFile(opts.inventoryFile); err != nil {
+			return nil, fmt.Errorf("--inventory error: %s", err)
+		}
+	}
+	targets := newHostTargets(cli, hosts.hosts)
+	for _, group := range strings.Split(opts.groups, ",") {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+		groupTargets, err := newGroupTargets(cfg, group, overrides, fallback)
+		if err != nil {
+			return nil, fmt.Errorf("--group error: %s", err)
+		}
+		targets = append(targets, groupTargets...)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("the inventory has no hosts")
+	}
+	return targets, nil
+}
+
+// fleetResult is the result of an operation performed against a host.
+type fleetResult struct {
+	Host     string      `yaml:"host" json:"host" xml:"host"`
+	Profile  string      `yaml:"profile,omitempty" json:"profile,omitempty" xml:"profile,omitempty"`
+	Success  bool        `yaml:"success" json:"success" xml:"success"`
+	Error    string      `yaml:"error,omitempty" json:"error,omitempty" xml:"error,omitempty"`
+	Duration string      `yaml:"duration" json:"duration" xml:"duration"`
+	ExitCode int         `yaml:"exit_code" json:"exit_code" xml:"exit_code"`
+	Result   interface{} `yaml:"result,omitempty" json:"result,omitempty" xml:"result,omitempty"`
+	text     func(io.Writer)
+}
+
+// fleetSummary is the summary of an operation performed against hosts.
+type fleetSummary struct {
+	Hosts       int      `yaml:"hosts" json:"hosts" xml:"hosts"`
+	Succeeded   int      `yaml:"succeeded" json:"succeeded" xml:"succeeded"`
+	Failed      int      `yaml:"failed" json:"failed" xml:"failed"`
+	FailedHosts []string `yaml:"failed_hosts" json:"failed_hosts" xml:"failed_hosts"`
+}
+
+// fleetReport is the aggregated output of an operation performed against
+// hosts.
+type fleetReport struct {
+	Summary *fleetSummary  `yaml:"summary" json:"summary" xml:"summary"`
+	Results []*fleetResult `yaml:"results" json:"results" xml:"results"`
+}
+
+// runFleetTask runs a task against a host, but no longer than the timeout.
+// The API calls of the task are cancelled when the timeout expires, and the
+// task returns before the worker picks the next host.
+func runFleetTask(timeout time.Duration, task func(ctx context.Context) (*operationResult, error)) (*operationResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	result, err := task(ctx)
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+	return result, err
+}
+
+// runFleet performs an operation against hosts using a pool of workers
+// and returns the worst exit code of the hosts, e.g. 1 when the operation
+// failed on any host, or 2 when a check found a critical state.
+func runFleet(targets []*fleetTarget, opts *operationOptions, fleetOpts *fleetOptions, ow *outputWriter) int {
+	workers := fleetOpts.workers
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]*fleetResult, len(targets))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				target := targets[j]
+				start := time.Now()
+				result, err := runFleetTask(fleetOpts.timeout, func(ctx context.Context) (*operationResult, error) {
+					hostCli, err := target.newClient()
+					if err != nil {
+						return nil, err
+					}
+					hostCli = hostCli.Clone()
+					if err := hostCli.SetContext(ctx); err != nil {
+						return nil, err
+					}
+					return runOperation(hostCli, target.host, opts)
+				})
+				entry := &fleetResult{
+					Host:     target.host,
+					Profile:  target.profile,
+					Success:  err == nil,
+					Duration: time.Since(start).Round(time.Millisecond).String(),
+				}
+				if err != nil {
+					entry.Error = strings.SplitN(err.Error(), "\n", 2)[0]
+					entry.ExitCode = 1
+					log.Debugf("%s: %s", target.host, err)
+				} else {
+					entry.Result = result.data
+					entry.ExitCode = result.exitCode
+					entry.text = result.text
+				}
+				results[j] = entry
+			}
+		}()
+	}
+	for i := range targets {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	summary := &fleetSummary{
+		Hosts:       len(targets),
+		FailedHosts: []string{},
+	}
+	for _, result := range results {
+		if result.Success {
+			summary.Succeeded++
+			continue
+		}
+		summary.Failed++
+		summary.FailedHosts = append(summary.FailedHosts, result.Host)
+	}
+
+	var err error
+	switch ow.format {
+	case "table", "csv":
+		err = ow.write(results, nil)
+		printFleetSummary(os.Stderr, summary, results)
+	default:
+		err = ow.write(&fleetReport{Summary: summary, Results: results}, func(w io.Writer) {
+			for _, result := range results {
+				fmt.Fprintf(w, "=== %s ===\n", result.Host)
+				if !result.Success {
+					fmt.Fprintf(w, "Error: %s\n", result.Error)
+					continue
+				}
+				result.text(w)
+			}
+			printFleetSummary(w, summary, results)
+		})
+	}
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+	exitCode := 0
+	for _, result := range results {
+		if result.ExitCode > exitCode {
+			exitCode = result.ExitCode
+		}
+	}
+	return exitCode
+}
+
+func printFleetSummary(w io.Writer, summary *fleetSummary, results []*fleetResult) {
+	fmt.Fprintf(w, "--- Summary ---\n")
+	fmt.Fprintf(w, "Hosts: %d, Succeeded: %d, Failed: %d\n", summary.Hosts, summary.Succeeded, summary.Failed)
+	for _, result := range results {
+		if !result.Success {
+			fmt.Fprintf(w, "Failed: %s: %s\n", result.Host, result.Error)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRunFleet(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	// The host does not respond until the request is cancelled.
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	defer hanging.Close()
+	u, _ := url.Parse(hanging.URL)
+	hangingPort, _ := strconv.Atoi(u.Port())
+
+	newTarget := func(host, hostname string, port int, protocol string) *fleetTarget {
+		return &fleetTarget{
+			host: host,
+			newClient: func() (*client.Client, error) {
+				cli := client.NewClient()
+				cli.SetHost(hostname)
+				cli.SetPort(port)
+				cli.SetProtocol(protocol)
+				cli.SetUsername("admin")
+				cli.SetPassword("secret")
+				return cli, nil
+			},
+		}
+	}
+	idrac1 := newTarget("idrac-1", server.NonTLS.Hostname, server.NonTLS.Port, server.NonTLS.Protocol)
+	idrac2 := newTarget("idrac-2", server.NonTLS.Hostname, server.NonTLS.Port, server.NonTLS.Protocol)
+	idrac3 := newTarget("idrac-3", u.Hostname(), hangingPort, u.Scheme)
+	broken := &fleetTarget{
+		host: "idrac-4",
+		newClient: func() (*client.Client, error) {
+			return nil, fmt.Errorf("no credentials")
+		},
+	}
+
+	getInfo := &operationOptions{operation: "get-info", format: "json"}
+	// The health check without components is UNKNOWN, i.e. exit code 3.
+	checkHealth := &operationOptions{operation: "check-health", format: "json", healthCheck: &healthCheckOptions{}}
+
+	testFailed := 0
+	for i, test := range []struct {
+		targets        []*fleetTarget
+		opts           *operationOptions
+		timeout        time.Duration
+		expExitCode    int
+		expFailedHosts []string
+		expErrors      map[string]string
+	}{
+		{
+			targets:        []*fleetTarget{idrac1, idrac2},
+			opts:           getInfo,
+			expExitCode:    0,
+			expFailedHosts: []string{},
+		},
+		{
+			targets:        []*fleetTarget{idrac1, broken, idrac2},
+			opts:           getInfo,
+			expExitCode:    1,
+			expFailedHosts: []string{"idrac-4"},
+			expErrors:      map[string]string{"idrac-4": "no credentials"},
+		},
+		{
+			// The worst exit code of the hosts wins.
+			targets:        []*fleetTarget{broken, idrac1},
+			opts:           checkHealth,
+			expExitCode:    3,
+			expFailedHosts: []string{"idrac-4"},
+		},
+		{
+			// The host exceeding the timeout fails, and the other hosts
+			// are not affected.
+			targets:        []*fleetTarget{idrac3, idrac1},
+			opts:           getInfo,
+			timeout:        100 * time.Millisecond,
+			expExitCode:    1,
+			expFailedHosts: []string{"idrac-3"},
+			expErrors:      map[string]string{"idrac-3": "timed out after 100ms"},
+		},
+	} {
+		fleetOpts := &fleetOptions{workers: 2, timeout: time.Minute}
+		if test.timeout > 0 {
+			fleetOpts.timeout = test.timeout
+		}
+		var b bytes.Buffer
+		ow, err := newOutputWriter(&b, &outputOptions{format: "json"})
+		if err != nil {
+			t.Fatalf("Test %d: expected success, but got error: %s", i, err)
+		}
+		start := time.Now()
+		exitCode := runFleet(test.targets, test.opts, fleetOpts, ow)
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Logf("FAIL: Test %d: expected the hosts to complete within the timeout, but took %s", i, elapsed)
+			testFailed++
+		}
+		if exitCode != test.expExitCode {
+			t.Logf("FAIL: Test %d: expected exit code %d, but got %d", i, test.expExitCode, exitCode)
+			testFailed++
+		}
+		report := &fleetReport{}
+		if err := json.Unmarshal(b.Bytes(), report); err != nil {
+			t.Fatalf("Test %d: expected fleet report, but got error: %s, output: %s", i, err, b.String())
+		}
+		if report.Summary.Hosts != len(test.targets) || report.Summary.Failed != len(test.expFailedHosts) ||
+			report.Summary.Succeeded != len(test.targets)-len(test.expFailedHosts) {
+			t.Logf("FAIL: Test %d: unexpected summary: %+v", i, *report.Summary)
+			testFailed++
+		}
+		if !reflect.DeepEqual(report.Summary.FailedHosts, test.expFailedHosts) {
+			t.Logf("FAIL: Test %d: expected failed hosts %v, but got %v", i, test.expFailedHosts, report.Summary.FailedHosts)
+			testFailed++
+		}
+		// The results follow the order of the targets.
+		for j, result := range report.Results {
+			if result.Host != test.targets[j].host {
+				t.Logf("FAIL: Test %d: expected result %d of %s, but got %s", i, j, test.targets[j].host, result.Host)
+				testFailed++
+			}
+			if expErr, exists := test.expErrors[result.Host]; exists && result.Error != expErr {
+				t.Logf("FAIL: Test %d: %s: expected error %q, but got %q", i, result.Host, expErr, result.Error)
+				testFailed++
+			}
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// forwardOptions holds the arguments of the forward-logs operation.
+type forwardOptions struct {
+	address   string
+	protocol  string
+	caFile    string
+	insecure  bool
+	facility  string
+	logs      string
+	interval  time.Duration
+	backfill  time.Duration
+	stateFile string
+	once      bool
+}
+
+func (opts *forwardOptions) bindFlags() {
+	flag.StringVar(&opts.address, "forward.address", "", "forward-logs: syslog server address, e.g. 127.0.0.1:514")
+	flag.StringVar(&opts.protocol, "forward.protocol", "udp", "forward-logs: syslog transport, i.e. udp, tcp, or tls")
+	flag.StringVar(&opts.caFile, "forward.tls-ca", "", "forward-logs: CA certificates of the syslog server, PEM encoded")
+	flag.BoolVar(&opts.insecure, "forward.tls-insecure", false, "forward-logs: skip the verification of the syslog server certificate")
+	flag.StringVar(&opts.facility, "forward.facility", "local0", "forward-logs: syslog facility")
+	flag.StringVar(&opts.logs, "forward.logs", "Sel,Lclog", "forward-logs: comma-separated list of the forwarded log services")
+	flag.DurationVar(&opts.interval, "forward.interval", time.Minute, "forward-logs: polling interval")
+	flag.DurationVar(&opts.backfill, "forward.backfill", 0, "forward-logs: forward the entries created within the duration before the first poll of a host")
+	flag.StringVar(&opts.stateFile, "forward.state-file", "log-forward-state.json", "forward-logs: file with the last forwarded entry of each host")
+	flag.BoolVar(&opts.once, "forward.once", false, "forward-logs: poll the hosts once and exit")
+}
+
+// forwardCursor is the last forwarded entry of a log service.
+type forwardCursor struct {
+	LastID      string    `json:"last_id"`
+	LastCreated time.Time `json:"last_created"`
+}
+
+// isNew returns true when the entry was created after the last forwarded
+// entry. The entries created at the same time, and the entries without a
+// creation time, are ordered by their ids.
+func (c *forwardCursor) isNew(entry *client.LogEntry) bool {
+	if entry.Created.IsZero() {
+		return compareEntryIDs(entry.ID, c.LastID) > 0
+	}
+	if entry.Created.After(c.LastCreated) {
+		return true
+	}
+	if entry.Created.Before(c.LastCreated) {
+		return false
+	}
+	return compareEntryIDs(entry.ID, c.LastID) > 0
+}
+
+// compareEntryIDs compares the ids of log entries numerically when both
+// ids are numbers, and lexically otherwise.
+func compareEntryIDs(a, b string) int {
+	i, errA := strconv.ParseInt(a, 10, 64)
+	j, errB := strconv.ParseInt(b, 10, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case i < j:
+			return -1
+		case i > j:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// forwardState is the state of the forward-logs operation, i.e. the last
+// forwarded entry of each log service of each host.
+type forwardState struct {
+	mu    sync.Mutex
+	path  string
+	Hosts map[string]map[string]*forwardCursor `json:"hosts"`
+}
+
+// loadForwardState returns the state stored in a file. When the file does
+// not exist, the state is empty.
+func loadForwardState(fp string) (*forwardState, error) {
+	state := &forwardState{
+		path:  fp,
+		Hosts: make(map[string]map[string]*forwardCursor),
+	}
+	data, err := ioutil.ReadFile(fp)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("state file %s: %s", fp, err)
+	}
+	if state.Hosts == nil {
+		state.Hosts = make(map[string]map[string]*forwardCursor)
+	}
+	return state, nil
+}
+
+func (s *forwardState) get(host, logID string) *forwardCursor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cursor, exists := s.Hosts[host][logID]; exists {
+		c := *cursor
+		return &c
+	}
+	return nil
+}
+
+func (s *forwardState) set(host, logID string, cursor *forwardCursor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.Hosts[host]; !exists {
+		s.Hosts[host] = make(map[string]*forwardCursor)
+	}
+	s.Hosts[host][logID] = cursor
+}
+
+// save writes the state to a temporary file and then renames it, so that
+// the state file is never partially written.
+func (s *forwardState) save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// logForwarder polls the log services of hosts and forwards the new entries
+// to a syslog server.
+type logForwarder struct {
+	opts        *forwardOptions
+	facility    int
+	logIDs      []string
+	state       *forwardState
+	writer      *syslogWriter
+	mu          sync.Mutex
+	clients     map[string]*client.Client
+	serviceTags map[string]string
+}
+
+// hostClient returns the client of a host bound to the context. The client
+// and the service tag of the host are initialized once.
+func (f *logForwarder) hostClient(ctx context.Context, target *fleetTarget) (*client.Client, string, error) {
+	f.mu.Lock()
+	cli, exists := f.clients[target.host]
+	serviceTag := f.serviceTags[target.host]
+	f.mu.Unlock()
+	if !exists {
+		var err error
+		if cli, err = target.newClient(); err != nil {
+			return nil, "", err
+		}
+	}
+	hostCli := cli.Clone()
+	if err := hostCli.SetContext(ctx); err != nil {
+		return nil, "", err
+	}
+	if exists {
+		return hostCli, serviceTag, nil
+	}
+	info, err := hostCli.GetInfo()
+	if err != nil {
+		return nil, "", err
+	}
+	f.mu.Lock()
+	f.clients[target.host] = cli
+	f.serviceTags[target.host] = info.ServiceTag
+	f.mu.Unlock()
+	return hostCli, info.ServiceTag, nil
+}
+
+// forwardLog forwards the new entries of a log service of a host, oldest
+// first, and returns the number of the forwarded entries.
+func (f *logForwarder) forwardLog(cli *client.Client, host, serviceTag, logID string) (int, error) {
+	svc, err := cli.GetLogService(client.ManagerResource, logID)
+	if err != nil {
+		return 0, err
+	}
+	cursor := f.state.get(host, svc.ID)
+	if cursor == nil {
+		cursor = &forwardCursor{LastCreated: time.Now().Add(-f.opts.backfill).UTC()}
+		f.state.set(host, svc.ID, cursor)
+	}
+	entries, err := cli.GetLogEntries(svc, cursor.LastCreated, "")
+	if err != nil {
+		return 0, err
+	}
+	newEntries := []*client.LogEntry{}
+	for _, entry := range entries {
+		if cursor.isNew(entry) {
+			newEntries = append(newEntries, entry)
+		}
+	}
+	sort.SliceStable(newEntries, func(i, j int) bool {
+		if !newEntries[i].Created.Equal(newEntries[j].Created) {
+			return newEntries[i].Created.Before(newEntries[j].Created)
+		}
+		return compareEntryIDs(newEntries[i].ID, newEntries[j].ID) < 0
+	})
+	for i, entry := range newEntries {
+		msg := &syslogMessage{
+			facility:  f.facility,
+			severity:  syslogSeverity(entry.Severity),
+			timestamp: entry.Created,
+			hostname:  host,
+			appName:   "idrac",
+			msgID:     svc.ID,
+			params: []syslogParam{
+				{"host", host},
+				{"serviceTag", serviceTag},
+				{"log", svc.ID},
+				{"entryId", entry.ID},
+				{"messageId", entry.MessageID},
+				{"severity", entry.Severity},
+			},
+			message: entry.Message,
+		}
+		if err := f.writer.Write(msg); err != nil {
+			return i, fmt.Errorf("syslog error: %s", err)
+		}
+		next := &forwardCursor{
+			LastID:      entry.ID,
+			LastCreated: entry.Created,
+		}
+		if entry.Created.IsZero() {
+			// The cursor keeps the creation time of the last entry having
+			// one, otherwise the next poll would forward all entries.
+			next.LastCreated = cursor.LastCreated
+		}
+		cursor = next
+		f.state.set(host, svc.ID, cursor)
+	}
+	return len(newEntries), nil
+}
+
+// poll forwards the new entries of the hosts using a pool of workers and
+// returns the number of the forwarded entries. The polling of a host takes
+// no longer than the timeout.
+func (f *logForwarder) poll(targets []*fleetTarget, workers int, timeout time.Duration) int {
+	if workers < 1 {
+		workers = 1
+	}
+	var total int
+	var totalMu sync.Mutex
+	queue := make(chan *fleetTarget)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range queue {
+				_, err := runFleetTask(timeout, func(ctx context.Context) (*operationResult, error) {
+					cli, serviceTag, err := f.hostClient(ctx, target)
+					if err != nil {
+						return nil, err
+					}
+					for _, logID := range f.logIDs {
+						n, err := f.forwardLog(cli, target.host, serviceTag, logID)
+						if err != nil && ctx.Err() == nil {
+							log.Errorf("%s: %s log: %s", target.host, logID, err)
+						}
+						if n > 0 {
+							log.Debugf("%s: forwarded %d %s log entries", target.host, n, logID)
+						}
+						totalMu.Lock()
+						total += n
+						totalMu.Unlock()
+						if ctx.Err() != nil {
+							break
+						}
+					}
+					return nil, nil
+				})
+				if err != nil {
+					log.Errorf("%s: %s", target.host, err)
+				}
+			}
+		}()
+	}
+	for _, target := range targets {
+		queue <- target
+	}
+	close(queue)
+	wg.Wait()
+	return total
+}
+
+// runLogForwarder polls the log services of the hosts at the interval and
+// forwards the new entries to a syslog server, until interrupted.
+func runLogForwarder(targets []*fleetTarget, opts *forwardOptions, fleetOpts *fleetOptions) error {
+	facility, exists := syslogFacilities[strings.ToLower(opts.facility)]
+	if !exists {
+		return fmt.Errorf("unsupported syslog facility %s", opts.facility)
+	}
+	logIDs := splitList(opts.logs)
+	if len(logIDs) == 0 {
+		return fmt.Errorf("no log services to forward")
+	}
+	if opts.interval <= 0 {
+		return fmt.Errorf("--forward.interval must be positive")
+	}
+	writer, err := newSyslogWriter(opts.protocol, opts.address, opts.caFile, opts.insecure)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+	state, err := loadForwardState(opts.stateFile)
+	if err != nil {
+		return err
+	}
+	f := &logForwarder{
+		opts:        opts,
+		facility:    facility,
+		logIDs:      logIDs,
+		state:       state,
+		writer:      writer,
+		clients:     make(map[string]*client.Client),
+		serviceTags: make(map[string]string),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	for {
+		n := f.poll(targets, fleetOpts.workers, fleetOpts.timeout)
+		if err := state.save(); err != nil {
+			return fmt.Errorf("state file %s: %s", opts.stateFile, err)
+		}
+		log.Infof("forwarded %d log entries from %d hosts to %s", n, len(targets), opts.address)
+		if opts.once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
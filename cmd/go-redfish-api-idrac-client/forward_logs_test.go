@@ -0,0 +1,251 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForwardCursor(t *testing.T) {
+	created, _ := time.Parse(time.RFC3339, "2020-11-12T14:25:31-06:00")
+	cursor := &forwardCursor{LastID: "9", LastCreated: created}
+	testFailed := 0
+	for i, test := range []struct {
+		entry *client.LogEntry
+		exp   bool
+	}{
+		{entry: &client.LogEntry{ID: "8", Created: created.Add(time.Second)}, exp: true},
+		{entry: &client.LogEntry{ID: "10", Created: created.Add(-time.Second)}, exp: false},
+		// The entries created at the same time are ordered by their ids,
+		// numerically when both ids are numbers.
+		{entry: &client.LogEntry{ID: "10", Created: created}, exp: true},
+		{entry: &client.LogEntry{ID: "9", Created: created}, exp: false},
+		{entry: &client.LogEntry{ID: "8", Created: created}, exp: false},
+		{entry: &client.LogEntry{ID: "10", Created: created.UTC()}, exp: true},
+		// The entries without a creation time are ordered by their ids.
+		{entry: &client.LogEntry{ID: "10"}, exp: true},
+		{entry: &client.LogEntry{ID: "9"}, exp: false},
+	} {
+		if isNew := cursor.isNew(test.entry); isNew != test.exp {
+			t.Logf("FAIL: Test %d: entry %s: expected %t, but got %t", i, test.entry.ID, test.exp, isNew)
+			testFailed++
+		}
+	}
+	for i, test := range []struct {
+		a   string
+		b   string
+		exp int
+	}{
+		{a: "10", b: "9", exp: 1},
+		{a: "9", b: "10", exp: -1},
+		{a: "10", b: "10", exp: 0},
+		{a: "b", b: "a", exp: 1},
+		{a: "10", b: "9a", exp: -1},
+	} {
+		if n := compareEntryIDs(test.a, test.b); n != test.exp {
+			t.Logf("FAIL: Test %d: comparing %s with %s: expected %d, but got %d", i, test.a, test.b, test.exp, n)
+			testFailed++
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestForwardState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "forward-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fp := filepath.Join(dir, "state.json")
+
+	state, err := loadForwardState(fp)
+	if err != nil {
+		t.Fatalf("expected success for absent state file, but got error: %s", err)
+	}
+	if cursor := state.get("10.10.10.10", "Sel"); cursor != nil {
+		t.Fatalf("expected no cursor, but got %+v", *cursor)
+	}
+	created, _ := time.Parse(time.RFC3339, "2020-11-12T14:25:31-06:00")
+	state.set("10.10.10.10", "Sel", &forwardCursor{LastID: "3", LastCreated: created})
+	state.set("10.10.10.10", "Lclog", &forwardCursor{LastID: "120", LastCreated: created.Add(time.Hour)})
+	state.set("10.10.10.11", "Sel", &forwardCursor{LastID: "1", LastCreated: created.Add(-time.Hour)})
+	if err := state.save(); err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if info, err := os.Stat(fp); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected state file with 0600 permissions, got %v, error: %v", info, err)
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("expected no temporary files, but got %d files", len(files))
+	}
+
+	loaded, err := loadForwardState(fp)
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	for host, logs := range state.Hosts {
+		for logID, cursor := range logs {
+			c := loaded.get(host, logID)
+			if c == nil || c.LastID != cursor.LastID || !c.LastCreated.Equal(cursor.LastCreated) {
+				t.Fatalf("%s %s: expected cursor %+v, but got %+v", host, logID, *cursor, c)
+			}
+		}
+	}
+
+	// The cursor returned by the state is a copy.
+	loaded.get("10.10.10.10", "Sel").LastID = "4"
+	if c := loaded.get("10.10.10.10", "Sel"); c.LastID != "3" {
+		t.Fatalf("expected unchanged cursor, but got %+v", *c)
+	}
+
+	if err := ioutil.WriteFile(fp, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadForwardState(fp); err == nil {
+		t.Fatalf("expected failure due to invalid state file, but got non-error response")
+	}
+}
+
+func TestForwardLogs(t *testing.T) {
+	// The log returns the entries 1 to 3, and then the entries 3 to 5. The
+	// entry 4 was created at the same time as the entry 3.
+	server, err := NewMockTestServer(map[string]string{
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries": "log_entry_collection_sel_1.json,log_entry_collection_sel_3.json",
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := client.NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	listener := newSyslogListener(t, "tcp")
+	defer listener.close()
+
+	dir, err := ioutil.TempDir("", "forward-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	opts := &forwardOptions{
+		backfill:  time.Since(time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)),
+		stateFile: filepath.Join(dir, "state.json"),
+	}
+
+	newForwarder := func() *logForwarder {
+		state, err := loadForwardState(opts.stateFile)
+		if err != nil {
+			t.Fatalf("expected success, but got error: %s", err)
+		}
+		writer, err := newSyslogWriter("tcp", listener.address, "", false)
+		if err != nil {
+			t.Fatalf("expected success, but got error: %s", err)
+		}
+		return &logForwarder{
+			opts:     opts,
+			facility: syslogFacilities["local0"],
+			state:    state,
+			writer:   writer,
+		}
+	}
+
+	testFailed := 0
+	for i, test := range []struct {
+		entries []string
+	}{
+		{entries: []string{"1", "2", "3"}},
+		// The forwarder resumes after the last forwarded entry.
+		{entries: []string{"4", "5"}},
+		{entries: []string{}},
+	} {
+		f := newForwarder()
+		n, err := f.forwardLog(cli, "idrac-1", "ABC1234", "Sel")
+		if err != nil {
+			t.Fatalf("Test %d: expected success, but got error: %s", i, err)
+		}
+		f.writer.Close()
+		if err := f.state.save(); err != nil {
+			t.Fatalf("Test %d: expected success, but got error: %s", i, err)
+		}
+		entries := []string{}
+		for j := 0; j < n; j++ {
+			msg := listener.receive(t)
+			if !strings.Contains(msg, ` host="idrac-1" serviceTag="ABC1234" log="Sel" `) {
+				t.Logf("FAIL: Test %d: unexpected message %q", i, msg)
+				testFailed++
+			}
+			for _, field := range strings.Fields(msg) {
+				if strings.HasPrefix(field, "entryId=") {
+					entries = append(entries, strings.Trim(strings.TrimPrefix(field, "entryId="), `"`))
+				}
+			}
+		}
+		if !reflect.DeepEqual(entries, test.entries) {
+			t.Logf("FAIL: Test %d: expected entries %v, but got %v", i, test.entries, entries)
+			testFailed++
+		}
+	}
+	select {
+	case msg := <-listener.messages:
+		t.Fatalf("unexpected message %q", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestForwardLogsTimeout(t *testing.T) {
+	// The host does not respond until the request is cancelled.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	cli := client.NewClient()
+	cli.SetHost(u.Hostname())
+	cli.SetPort(port)
+	cli.SetProtocol(u.Scheme)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	f := &logForwarder{
+		opts:        &forwardOptions{},
+		logIDs:      []string{"Sel"},
+		state:       &forwardState{Hosts: make(map[string]map[string]*forwardCursor)},
+		clients:     make(map[string]*client.Client),
+		serviceTags: make(map[string]string),
+	}
+	start := time.Now()
+	if n := f.poll(newHostTargets(cli, []string{u.Hostname()}), 1, 100*time.Millisecond); n != 0 {
+		t.Fatalf("expected no forwarded entries, but got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the poll to time out, but it took %s", elapsed)
+	}
+	if len(f.clients) != 0 {
+		t.Fatalf("expected no initialized clients, but got %d", len(f.clients))
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// maxInventoryRangeSize is the maximum number of addresses a single CIDR
+// range in an inventory may expand to.
+const maxInventoryRangeSize = 65536
+
+// inventory is an ordered list of unique hosts.
+type inventory struct {
+	hosts []string
+	seen  map[string]bool
+}
+
+func newInventory() *inventory {
+	return &inventory{
+		seen: make(map[string]bool),
+	}
+}
+
+func (inv *inventory) addHost(host string) {
+	if inv.seen[host] {
+		return
+	}
+	inv.seen[host] = true
+	inv.hosts = append(inv.hosts, host)
+}
+
+// add adds an inventory entry, i.e. a hostname, an ip address, or a CIDR
+// range. The network and broadcast addresses of IPv4 ranges are skipped.
+func (inv *inventory) add(entry string) error {
+	if !strings.Contains(entry, "/") {
+		inv.addHost(entry)
+		return nil
+	}
+	ip, ipNet, err := net.ParseCIDR(entry)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR range %q: %s", entry, err)
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones > 16 {
+		return fmt.Errorf("CIDR range %q exceeds %d addresses", entry, maxInventoryRangeSize)
+	}
+	isIPv4 := ip.To4() != nil
+	size := 1 << uint(bits-ones)
+	addr := ipNet.IP.Mask(ipNet.Mask)
+	for i := 0; i < size; i++ {
+		if isIPv4 && size > 2 && (i == 0 || i == size-1) {
+			addr = nextIP(addr)
+			continue
+		}
+		inv.addHost(addr.String())
+		addr = nextIP(addr)
+	}
+	return nil
+}
+
+// addFile adds the entries of an inventory file. The entries are separated
+// by whitespace or commas. The text following # is a comment.
+func (inv *inventory) addFile(fp string) error {
+	f, err := os.Open(fp)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		for _, entry := range strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			if err := inv.add(entry); err != nil {
+				return fmt.Errorf("%s:%d: %s", fp, lineNumber, err)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInventory(t *testing.T) {
+	testFailed := 0
+	for i, test := range []struct {
+		entries   []string
+		expHosts  []string
+		expCount  int
+		shouldErr bool
+	}{
+		{entries: []string{"idrac-1.example.com", "10.0.0.1"}, expHosts: []string{"idrac-1.example.com", "10.0.0.1"}},
+		// The network and broadcast addresses of IPv4 ranges are skipped.
+		{entries: []string{"10.0.0.0/30"}, expHosts: []string{"10.0.0.1", "10.0.0.2"}},
+		{entries: []string{"10.0.0.1/30"}, expHosts: []string{"10.0.0.1", "10.0.0.2"}},
+		{entries: []string{"10.0.0.0/31"}, expHosts: []string{"10.0.0.0", "10.0.0.1"}},
+		{entries: []string{"10.0.0.5/32"}, expHosts: []string{"10.0.0.5"}},
+		{entries: []string{"10.0.0.255/29"}, expHosts: []string{"10.0.0.249", "10.0.0.250", "10.0.0.251", "10.0.0.252", "10.0.0.253", "10.0.0.254"}},
+		{entries: []string{"2001:db8::/126"}, expHosts: []string{"2001:db8::", "2001:db8::1", "2001:db8::2", "2001:db8::3"}},
+		// The hosts are unique.
+		{entries: []string{"10.0.0.2", "10.0.0.0/30", "10.0.0.2"}, expHosts: []string{"10.0.0.2", "10.0.0.1"}},
+		// The ranges are limited to 65536 addresses.
+		{entries: []string{"10.0.0.0/16"}, expCount: 65534},
+		{entries: []string{"10.0.0.0/15"}, shouldErr: true},
+		{entries: []string{"2001:db8::/64"}, shouldErr: true},
+		{entries: []string{"10.0.0.0/33"}, shouldErr: true},
+		{entries: []string{"idrac-1.example.com/24"}, shouldErr: true},
+	} {
+		inv := newInventory()
+		var err error
+		for _, entry := range test.entries {
+			if err = inv.add(entry); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: entries %v, expected to pass, but threw error: %v", i, test.entries, err)
+				testFailed++
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: entries %v, expected to throw error, but passed", i, test.entries)
+			testFailed++
+			continue
+		}
+		if test.expHosts != nil && !reflect.DeepEqual(inv.hosts, test.expHosts) {
+			t.Logf("FAIL: Test %d: entries %v, expected hosts %v, but got %v", i, test.entries, test.expHosts, inv.hosts)
+			testFailed++
+		}
+		if test.expCount > 0 && len(inv.hosts) != test.expCount {
+			t.Logf("FAIL: Test %d: entries %v, expected %d hosts, but got %d", i, test.entries, test.expCount, len(inv.hosts))
+			testFailed++
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestInventoryFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inventory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testFailed := 0
+	for i, test := range []struct {
+		content   string
+		expHosts  []string
+		expErr    string
+		shouldErr bool
+	}{
+		{
+			// The entries are separated by whitespace or commas, and the
+			// text following # is a comment.
+			content: "# rack 1\n" +
+				"10.0.0.1, 10.0.0.2  # top of rack\n" +
+				"\n" +
+				"   \n" +
+				"idrac-1.example.com\t10.0.1.0/30\n" +
+				"10.0.0.1,,\n" +
+				"#10.0.0.3\n",
+			expHosts: []string{"10.0.0.1", "10.0.0.2", "idrac-1.example.com", "10.0.1.1", "10.0.1.2"},
+		},
+		{content: "", expHosts: nil},
+		{content: "10.0.0.1\n\n10.0.0.0/8\n", expErr: ":3: ", shouldErr: true},
+	} {
+		fp := filepath.Join(dir, "hosts.txt")
+		if err := ioutil.WriteFile(fp, []byte(test.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		inv := newInventory()
+		if err := inv.addFile(fp); err != nil {
+			if !test.shouldErr || !strings.Contains(err.Error(), test.expErr) {
+				t.Logf("FAIL: Test %d: unexpected error: %v", i, err)
+				testFailed++
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: expected to throw error, but passed", i)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(inv.hosts, test.expHosts) {
+			t.Logf("FAIL: Test %d: expected hosts %v, but got %v", i, test.expHosts, inv.hosts)
+			testFailed++
+		}
+	}
+	if err := newInventory().addFile(filepath.Join(dir, "absent.txt")); err == nil {
+		t.Logf("FAIL: expected failure due to absent file, but got non-error response")
+		testFailed++
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
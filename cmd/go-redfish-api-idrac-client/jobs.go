@@ -0,0 +1,121 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// jobOptions holds the arguments of the job and task operations.
+type jobOptions struct {
+	id      string
+	pending bool
+	timeout time.Duration
+}
+
+func (opts *jobOptions) bindFlags() {
+	flag.StringVar(&opts.id, "jobs.id", "", "wait-job, delete-job: job id, e.g. JID_467762674724, or JID_CLEARALL to delete all the jobs")
+	flag.BoolVar(&opts.pending, "jobs.pending", false, "list-jobs: only the pending jobs, i.e. the job queue")
+	flag.DurationVar(&opts.timeout, "jobs.timeout", time.Hour, "wait-job: the maximum time to wait for the job")
+}
+
+// runJobOperation performs the job and task operations.
+func runJobOperation(cli *client.Client, host string, operation string, opts *jobOptions) (*operationResult, error) {
+	switch operation {
+	case "list-tasks":
+		tasks, err := cli.ListTasks()
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: tasks,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Tasks: %d\n", len(tasks))
+				tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "ID\tSTATE\tSTATUS\tPERCENT\tNAME")
+				for _, task := range tasks {
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", task.ID, task.TaskState, task.TaskStatus, task.PercentComplete, task.Name)
+				}
+				tw.Flush()
+			},
+		}, nil
+	case "wait-job":
+		return runWaitJob(cli, host, opts)
+	case "delete-job":
+		if err := cli.DeleteJob(opts.id); err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: map[string]string{"deleted": opts.id},
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Deleted job: %s\n", opts.id)
+			},
+		}, nil
+	}
+	var jobs []*client.Job
+	var err error
+	if opts.pending {
+		jobs, err = cli.GetJobQueue()
+	} else {
+		jobs, err = cli.ListJobs()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &operationResult{
+		data: jobs,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Jobs: %d\n", len(jobs))
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tTYPE\tSTATE\tPERCENT\tNAME\tMESSAGE")
+			for _, job := range jobs {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", job.ID, job.JobType, job.JobState, job.PercentComplete, job.Name, job.Message)
+			}
+			tw.Flush()
+		},
+	}, nil
+}
+
+// runWaitJob performs the wait-job operation, i.e. it follows the task of
+// a job until the job is done. The operation exits with code 1 when the job
+// failed.
+func runWaitJob(cli *client.Client, host string, opts *jobOptions) (*operationResult, error) {
+	if opts.id == "" {
+		return nil, fmt.Errorf("--jobs.id is empty")
+	}
+	ctx, cancel := context.WithTimeout(cli.Context(), opts.timeout)
+	defer cancel()
+	task, err := cli.WaitForTask(ctx, &client.TaskHandle{JobID: opts.id}, func(task *client.Task) {
+		log.Infof("%s: job %s is %s, %d%% complete", host, opts.id, task.TaskState, task.PercentComplete)
+	})
+	if task == nil {
+		return nil, err
+	}
+	result := &operationResult{
+		data: task,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Job: %s\n", task.ID)
+			fmt.Fprintf(w, "State: %s\n", task.TaskState)
+			fmt.Fprintf(w, "Status: %s\n", task.TaskStatus)
+			for _, msg := range task.Messages {
+				fmt.Fprintf(w, "Message: %s %s\n", msg.MessageID, msg.Message)
+			}
+		},
+	}
+	if err != nil {
+		log.Errorf("%s: %s", host, err)
+		result.exitCode = 1
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io"
+	"time"
+)
+
+// logOptions holds the arguments of the log service operations.
+type logOptions struct {
+	resource string
+	since    string
+	severity string
+}
+
+func (opts *logOptions) bindFlags() {
+	flag.StringVar(&opts.resource, "logs.resource", client.ManagerResource, "get-sel, get-lclog, clear-sel: manager or system with the log services")
+	flag.StringVar(&opts.since, "logs.since", "", "get-sel, get-lclog: entries created since the time, e.g. 24h or 2020-11-12T00:00:00Z")
+	flag.StringVar(&opts.severity, "logs.severity", "", "get-sel, get-lclog: entries with the severity or higher, i.e. OK, Warning, Critical")
+}
+
+// parseSince returns the time of the --logs.since argument, i.e. either
+// the duration before now, or RFC 3339 timestamp.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --logs.since value %q, expecting duration, e.g. 24h, or RFC 3339 timestamp", s)
+	}
+	return t, nil
+}
+
+// runLogOperation performs the log service operations.
+func runLogOperation(cli *client.Client, host string, operation string, opts *logOptions) (*operationResult, error) {
+	logServiceID := "Sel"
+	if operation == "get-lclog" {
+		logServiceID = "Lclog"
+	}
+	svc, err := cli.GetLogService(opts.resource, logServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if operation == "clear-sel" {
+		if err := cli.ClearLog(svc); err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: svc,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Log %s cleared\n", svc.Name)
+			},
+		}, nil
+	}
+
+	since, err := parseSince(opts.since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	entries, err := cli.GetLogEntries(svc, since, opts.severity)
+	if err != nil {
+		return nil, err
+	}
+	return &operationResult{
+		data: entries,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Log: %s (%d entries)\n", svc.Name, len(entries))
+			for _, entry := range entries {
+				fmt.Fprintf(w, "%s | %s | %s | %s\n",
+					entry.Created.Format(time.RFC3339), entry.Severity, entry.MessageID, entry.Message)
+			}
+		},
+	}, nil
+}
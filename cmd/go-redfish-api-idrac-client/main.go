@@ -5,7 +5,6 @@ package main
 import (
 	"flag"
 	"fmt"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
 	"github.com/greenpau/versioned"
 	log "github.com/sirupsen/logrus"
@@ -54,6 +53,26 @@ func main() {
 	var configFile string
 	var port int
 	var validateServerCert bool
+	var profileName string
+	healthCheckOpts := &healthCheckOptions{}
+	metricsOpts := &metricsOptions{}
+	outputOpts := &outputOptions{}
+	fleetOpts := &fleetOptions{}
+	rotateOpts := &rotatePasswordOptions{}
+	accountOpts := &accountOptions{}
+	directoryOpts := &directoryOptions{}
+	eventOpts := &eventOptions{}
+	registryOpts := &registryOptions{}
+	logOpts := &logOptions{}
+	forwardOpts := &forwardOptions{}
+	firmwareOpts := &firmwareOptions{}
+	jobOpts := &jobOptions{}
+	scpOpts := &scpOptions{}
+	planOpts := &planOptions{}
+	driftOpts := &driftOptions{}
+	attrsOpts := &attrsOptions{}
+	networkOpts := &networkOptions{}
+	ethernetOpts := &ethernetOptions{}
 
 	flag.StringVar(&configFile, "config", "redfish.yaml", "configuration file")
 	flag.StringVar(&host, "host", "", "target hostname or ip address")
@@ -64,6 +83,26 @@ func main() {
 	flag.StringVar(&authPass, "password", "", "password")
 	flag.StringVar(&apiOperation, "operation", "", "operation")
 	flag.StringVar(&apiResource, "resource", "", "resource")
+	flag.StringVar(&profileName, "profile", "", "host profile from the configuration file")
+	healthCheckOpts.bindFlags()
+	metricsOpts.bindFlags()
+	outputOpts.bindFlags()
+	fleetOpts.bindFlags()
+	rotateOpts.bindFlags()
+	accountOpts.bindFlags()
+	directoryOpts.bindFlags()
+	eventOpts.bindFlags()
+	registryOpts.bindFlags()
+	logOpts.bindFlags()
+	forwardOpts.bindFlags()
+	firmwareOpts.bindFlags()
+	jobOpts.bindFlags()
+	scpOpts.bindFlags()
+	planOpts.bindFlags()
+	driftOpts.bindFlags()
+	attrsOpts.bindFlags()
+	networkOpts.bindFlags()
+	ethernetOpts.bindFlags()
 
 	flag.StringVar(&logLevel, "log.level", "info", "logging severity level")
 	flag.BoolVar(&isShowVersion, "version", false, "version information")
@@ -83,12 +122,43 @@ func main() {
 		fmt.Fprintf(os.Stdout, "%s\n", app.Banner())
 		os.Exit(0)
 	}
+	if apiOperation == "check-health" {
+		// Monitoring plugins must exit with UNKNOWN on errors.
+		log.StandardLogger().ExitFunc = func(int) { os.Exit(checkStateUnknown) }
+	}
 	if level, err := log.ParseLevel(logLevel); err == nil {
 		log.SetLevel(level)
 	} else {
 		log.Errorf(err.Error())
 		os.Exit(1)
 	}
+	output, err := newOutputWriter(os.Stdout, outputOpts)
+	if err != nil {
+		log.Fatalf("--format error: %s", err)
+	}
+
+	// The settings passed via command line arguments take precedence
+	// over host profiles.
+	overrides := &client.HostProfile{
+		Credentials: &client.CredentialSource{},
+		TLS:         &client.TLSSettings{},
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "host":
+			overrides.Host = host
+		case "port":
+			overrides.Port = port
+		case "proto":
+			overrides.Protocol = proto
+		case "username":
+			overrides.Credentials.Username = authUser
+		case "password":
+			overrides.Credentials.Password = authPass
+		case "validate-server-cert":
+			overrides.TLS.ValidateServerCert = &validateServerCert
+		}
+	})
 
 	// Determine configuration file name and extension
 	if configFile == "" {
@@ -101,7 +171,11 @@ func main() {
 	configName := strings.TrimSuffix(configFile, configFileExt)
 
 	// Define configuration parsing settings
-	viper.SetConfigName(configName)
+	if filepath.Base(configFile) != configFile {
+		viper.SetConfigFile(configFile)
+	} else {
+		viper.SetConfigName(configName)
+	}
 	viper.SetEnvPrefix("IDRAC_API")
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AddConfigPath("$HOME/.redfish")
@@ -114,7 +188,7 @@ func main() {
 	if host == "" {
 		if v := viper.Get("host"); v != nil {
 			host = viper.Get("host").(string)
-			log.Debugf("Setting host '%s' via IDRAC_API_HOST environment variable")
+			log.Debugf("Setting host '%s' via IDRAC_API_HOST environment variable", host)
 		}
 	}
 
@@ -133,7 +207,12 @@ func main() {
 	}
 
 	// Read configuration file
+	var hostConfig *client.Config
 	if err := viper.ReadInConfig(); err == nil {
+		hostConfig, err = client.LoadConfig(viper.ConfigFileUsed())
+		if err != nil {
+			log.Fatalf("configuration file error %s", err)
+		}
 		if authUser == "" {
 			if v := viper.Get("username"); v != nil {
 				authUser = v.(string)
@@ -152,25 +231,71 @@ func main() {
 		}
 	}
 
-	// Configure the API client
-	if err := cli.SetHost(host); err != nil {
-		log.Fatalf("--host error: %s", err)
+	if (profileName != "" || fleetOpts.groups != "") && hostConfig == nil {
+		log.Fatalf("the --profile and --group arguments require configuration file %s", configFile)
 	}
-	if err := cli.SetPort(port); err != nil {
-		log.Fatalf("--port error: %s", err)
+	fallback := &client.HostProfile{
+		Port:     port,
+		Protocol: proto,
+		Credentials: &client.CredentialSource{
+			Username: authUser,
+			Password: authPass,
+		},
 	}
-	if err := cli.SetProtocol(proto); err != nil {
-		log.Fatalf("--proto error: %s", err)
-	}
-	if err := cli.SetUsername(authUser); err != nil {
-		log.Fatalf("--username error: %s", err)
-	}
-	if err := cli.SetPassword(authPass); err != nil {
-		log.Fatalf("--password error: %s", err)
+
+	// The event receiver accepts events from hosts, i.e. it does not
+	// connect to them.
+	if apiOperation == "listen-events" {
+		if err := runEventReceiver(hostConfig, eventOpts, registryOpts); err != nil {
+			log.Fatalf("%s", err)
+		}
+		return
 	}
-	if validateServerCert {
-		if err := cli.SetValidateServerCertificate(); err != nil {
-			log.Fatalf("--validate-server-cert error: %s", err)
+
+	// Configure the API client
+	if profileName != "" {
+		profile, err := hostConfig.GetProfile(profileName)
+		if err != nil {
+			log.Fatalf("--profile error: %s", err)
+		}
+		cli, err = newProfileClient(profile, overrides, fallback)
+		if err != nil {
+			log.Fatalf("--profile error: %s", err)
+		}
+		if host == "" {
+			host = profile.Host
+		}
+	} else {
+		// The exporter accepts targets via target query parameter, while
+		// the inventory provides hosts for fleet execution.
+		if host != "" || (apiOperation != "serve-metrics" && !fleetOpts.enabled()) {
+			if err := cli.SetHost(host); err != nil {
+				log.Fatalf("--host error: %s", err)
+			}
+		}
+		if err := cli.SetPort(port); err != nil {
+			log.Fatalf("--port error: %s", err)
+		}
+		if err := cli.SetProtocol(proto); err != nil {
+			log.Fatalf("--proto error: %s", err)
+		}
+		// The hosts of groups, and the exporter targets of the configuration,
+		// may get credentials from their profiles.
+		groupsOnly := (fleetOpts.groups != "" || apiOperation == "serve-metrics") && host == "" && fleetOpts.inventoryFile == ""
+		if authUser != "" || !groupsOnly {
+			if err := cli.SetUsername(authUser); err != nil {
+				log.Fatalf("--username error: %s", err)
+			}
+		}
+		if authPass != "" || !groupsOnly {
+			if err := cli.SetPassword(authPass); err != nil {
+				log.Fatalf("--password error: %s", err)
+			}
+		}
+		if validateServerCert {
+			if err := cli.SetValidateServerCertificate(); err != nil {
+				log.Fatalf("--validate-server-cert error: %s", err)
+			}
 		}
 	}
 
@@ -188,58 +313,127 @@ func main() {
 	log.Debugf("Certificate validation: %t", validateServerCert)
 	log.Debugf("Username: %s", authUser)
 
-	timerStartTime := time.Now()
+	opts := &operationOptions{
+		operation:   apiOperation,
+		resource:    apiResource,
+		format:      output.format,
+		healthCheck: healthCheckOpts,
+		rotate:      rotateOpts,
+		account:     accountOpts,
+		directory:   directoryOpts,
+		events:      eventOpts,
+		registry:    registryOpts,
+		logs:        logOpts,
+		firmware:    firmwareOpts,
+		jobs:        jobOpts,
+		scp:         scpOpts,
+		plan:        planOpts,
+		drift:       driftOpts,
+		attrs:       attrsOpts,
+		network:     networkOpts,
+		ethernet:    ethernetOpts,
+	}
 
 	if apiOperation != "" {
 		if _, exists := supportedOperations[apiOperation]; !exists {
 			log.Fatalf("the --operation %s is unsupported", apiOperation)
 		}
-		switch apiOperation {
-		case "get-info":
-			info, err := cli.GetInfo()
+	}
+
+	if apiOperation == "rotate-password" {
+		if err := rotateOpts.init(); err != nil {
+			log.Fatalf("%s", err)
+		}
+	}
+	if apiOperation == "apply-directory" {
+		if err := directoryOpts.init(); err != nil {
+			log.Fatalf("%s", err)
+		}
+	}
+
+	if apiOperation == "set-ethernet-interface" {
+		if err := ethernetOpts.init(fleetOpts.enabled()); err != nil {
+			log.Fatalf("%s", err)
+		}
+	}
+
+	timerStartTime := time.Now()
+
+	if apiOperation == "serve-metrics" {
+		targets, err := newConfigTargets(hostConfig, overrides, fallback)
+		if err != nil {
+			log.Fatalf("%s", err)
+		}
+		if host != "" || fleetOpts.enabled() {
+			hostTargets, err := newFleetTargets(cli, host, hostConfig, overrides, fallback, fleetOpts)
 			if err != nil {
 				log.Fatalf("%s", err)
 			}
-			fmt.Fprintf(os.Stdout, "Host: %s\n", host)
-			fmt.Fprintf(os.Stdout, "Product: %s\n", info.Product)
-			fmt.Fprintf(os.Stdout, "Service Tag: %s\n", info.ServiceTag)
-			fmt.Fprintf(os.Stdout, "Manager MAC Address: %s\n", info.ManagerMACAddress)
-			fmt.Fprintf(os.Stdout, "Redfish API Version: %s\n", info.RedfishVersion)
-		case "get-systems":
-			computerSystems, err := cli.GetComputerSystems()
-			if err != nil {
+			targets = append(hostTargets, targets...)
+		}
+		if err := runMetricsServer(targets, metricsOpts); err != nil {
+			log.Fatalf("%s", err)
+		}
+		return
+	}
+
+	if apiOperation == "forward-logs" && !fleetOpts.enabled() {
+		targets := []*fleetTarget{{
+			host:    host,
+			profile: profileName,
+			newClient: func() (*client.Client, error) {
+				return cli, nil
+			},
+		}}
+		if err := runLogForwarder(targets, forwardOpts, fleetOpts); err != nil {
+			log.Fatalf("%s", err)
+		}
+		return
+	}
+
+	if apiOperation == "tail-events" {
+		if err := runEventTail(cli, eventOpts, registryOpts, output.format); err != nil {
+			log.Fatalf("%s", err)
+		}
+		return
+	}
+
+	if fleetOpts.enabled() {
+		targets, err := newFleetTargets(cli, host, hostConfig, overrides, fallback, fleetOpts)
+		if err != nil {
+			log.Fatalf("%s", err)
+		}
+		if apiOperation == "forward-logs" {
+			if err := runLogForwarder(targets, forwardOpts, fleetOpts); err != nil {
 				log.Fatalf("%s", err)
 			}
-			fmt.Fprintf(os.Stdout, "Number of Computer Systems: %d\n", len(computerSystems))
-			fmt.Fprintf(os.Stdout, "---------------------------------\n")
-			for _, cs := range computerSystems {
-				if cs.Manufacturer != "" {
-					fmt.Fprintf(os.Stdout, "System: %s | Manufacturer: %s\n", cs.ID, cs.Manufacturer)
-				}
-				if cs.Model != "" {
-					fmt.Fprintf(os.Stdout, "System: %s | Model: %s\n", cs.ID, cs.Model)
-				}
-				if cs.SKU != "" {
-					fmt.Fprintf(os.Stdout, "System: %s | SKU: %s\n", cs.ID, cs.SKU)
-				}
-				if cs.PartNumber != "" {
-					fmt.Fprintf(os.Stdout, "System: %s | SKU: %s\n", cs.ID, cs.PartNumber)
-				}
-				if cs.BiosVersion != "" {
-					fmt.Fprintf(os.Stdout, "System: %s | BIOS Version: %s\n", cs.ID, cs.BiosVersion)
-				}
-				spew.Dump(cs)
+			return
+		}
+		if apiOperation == "export-scp" {
+			if err := scpOpts.init(len(targets)); err != nil {
+				log.Fatalf("%s", err)
 			}
-		default:
-			log.Fatalf("the --operation %s is supported by API, but not this utility", apiOperation)
 		}
-	} else {
-		res, err := cli.GetResource(apiResource)
-		if err != nil {
-			log.Fatalf("%s", err)
+		if apiOperation == "snapshot-config" {
+			if err := driftOpts.init(len(targets)); err != nil {
+				log.Fatalf("%s", err)
+			}
 		}
-		fmt.Fprintf(os.Stdout, "%s\n", res)
+		exitCode := runFleet(targets, opts, fleetOpts, output)
+		log.Debugf("took %s", time.Since(timerStartTime))
+		os.Exit(exitCode)
+	}
+
+	result, err := runOperation(cli, host, opts)
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+	if err := output.write(result.data, result.text); err != nil {
+		log.Fatalf("%s", err)
 	}
 
 	log.Debugf("took %s", time.Since(timerStartTime))
+	if result.exitCode != 0 {
+		os.Exit(result.exitCode)
+	}
 }
@@ -0,0 +1,292 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metricsOptions holds the arguments of the serve-metrics operation.
+type metricsOptions struct {
+	listenAddress string
+	metricsPath   string
+	chassisID     string
+}
+
+func (opts *metricsOptions) bindFlags() {
+	flag.StringVar(&opts.listenAddress, "metrics.listen", ":9348", "serve-metrics: address to listen on")
+	flag.StringVar(&opts.metricsPath, "metrics.path", "/metrics", "serve-metrics: path under which to expose metrics")
+	flag.StringVar(&opts.chassisID, "metrics.chassis", "System.Embedded.1", "serve-metrics: chassis providing thermal and power data")
+}
+
+// healthValues maps Redfish health to the values of idrac_health metric.
+var healthValues = map[string]float64{
+	"OK":       0,
+	"Warning":  1,
+	"Critical": 2,
+}
+
+type metricSample struct {
+	labels []string
+	value  float64
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string
+	samples []*metricSample
+}
+
+// metricsBuffer collects metric families and renders them in Prometheus
+// text exposition format.
+type metricsBuffer struct {
+	families []*metricFamily
+	index    map[string]*metricFamily
+}
+
+func newMetricsBuffer() *metricsBuffer {
+	return &metricsBuffer{
+		index: make(map[string]*metricFamily),
+	}
+}
+
+// add adds a sample to a metric family. The labels are name and value pairs.
+func (mb *metricsBuffer) add(name, kind, help string, value float64, labels ...string) {
+	family, exists := mb.index[name]
+	if !exists {
+		family = &metricFamily{name: name, kind: kind, help: help}
+		mb.index[name] = family
+		mb.families = append(mb.families, family)
+	}
+	family.samples = append(family.samples, &metricSample{labels: labels, value: value})
+}
+
+func (mb *metricsBuffer) addGauge(name, help string, value float64, labels ...string) {
+	mb.add(name, "gauge", help, value, labels...)
+}
+
+func (mb *metricsBuffer) addHealth(component, id string, status client.HealthStatus) {
+	if status.State == "Absent" || status.Health == "" {
+		return
+	}
+	value, exists := healthValues[status.Health]
+	if !exists {
+		value = 3
+	}
+	mb.addGauge("idrac_health", "Health of a component, 0 is OK, 1 is Warning, 2 is Critical, 3 is unknown.",
+		value, "component", component, "id", id)
+}
+
+var metricLabelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func (mb *metricsBuffer) String() string {
+	var b bytes.Buffer
+	for _, family := range mb.families {
+		fmt.Fprintf(&b, "# HELP %s %s\n", family.name, family.help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", family.name, family.kind)
+		for _, sample := range family.samples {
+			b.WriteString(family.name)
+			if len(sample.labels) > 0 {
+				pairs := []string{}
+				for i := 0; i+1 < len(sample.labels); i += 2 {
+					pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", sample.labels[i], metricLabelValueReplacer.Replace(sample.labels[i+1])))
+				}
+				b.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+			b.WriteString(" " + strconv.FormatFloat(sample.value, 'g', -1, 64) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// metricsExporter scrapes iDRAC targets on each request to metrics path.
+// The targets are the configured hosts and profiles, i.e. the exporter
+// does not send the credentials to arbitrary hosts.
+type metricsExporter struct {
+	opts          *metricsOptions
+	mu            sync.Mutex
+	scrapes       map[string]float64
+	errors        map[string]float64
+	targets       map[string]*fleetTarget
+	defaultTarget string
+}
+
+// newMetricsExporter returns an exporter of the targets. A target is
+// scraped by either its host or its profile name. The first target is the
+// default one.
+func newMetricsExporter(targets []*fleetTarget, opts *metricsOptions) *metricsExporter {
+	e := &metricsExporter{
+		opts:    opts,
+		scrapes: make(map[string]float64),
+		errors:  make(map[string]float64),
+		targets: make(map[string]*fleetTarget),
+	}
+	for _, target := range targets {
+		for _, name := range []string{target.host, target.profile} {
+			if _, exists := e.targets[name]; name == "" || exists {
+				continue
+			}
+			e.targets[name] = target
+		}
+	}
+	if len(targets) > 0 {
+		e.defaultTarget = targets[0].host
+	}
+	return e
+}
+
+// scrape collects the metrics of a target. It returns the first error
+// encountered, but keeps the metrics collected prior to the error.
+func (e *metricsExporter) scrape(cli *client.Client, mb *metricsBuffer) error {
+	computerSystems, err := cli.GetComputerSystems()
+	if err != nil {
+		return err
+	}
+	for _, cs := range computerSystems {
+		powerOn := 0.0
+		if cs.PowerState == "On" {
+			powerOn = 1
+		}
+		mb.addGauge("idrac_system_power_on", "Whether the power state of a system is On.",
+			powerOn, "system", cs.ID, "state", cs.PowerState)
+		mb.addGauge("idrac_system_info", "Information about a system.", 1,
+			"system", cs.ID, "model", cs.Model, "serial_number", cs.SerialNumber,
+			"service_tag", cs.SKU, "bios_version", cs.BiosVersion)
+		mb.addHealth("system", cs.ID, cs.Status)
+		mb.addHealth("processors", cs.ID, cs.ProcessorStatus)
+		mb.addHealth("memory", cs.ID, cs.MemoryStatus)
+	}
+
+	thermal, err := cli.GetThermal(e.opts.chassisID)
+	if err != nil {
+		return err
+	}
+	for _, sensor := range thermal.Temperatures {
+		mb.addHealth("temperature", sensor.Name, sensor.Status)
+		if sensor.Status.State == "Absent" {
+			continue
+		}
+		mb.addGauge("idrac_temperature_celsius", "Temperature reading of a sensor in degrees Celsius.",
+			sensor.ReadingCelsius, "sensor", sensor.Name, "context", sensor.PhysicalContext)
+	}
+	for _, fan := range thermal.Fans {
+		mb.addHealth("fan", fan.Name, fan.Status)
+		if fan.Status.State == "Absent" {
+			continue
+		}
+		mb.addGauge("idrac_fan_speed_rpm", "Speed of a fan in revolutions per minute.",
+			fan.Reading, "fan", fan.Name)
+	}
+
+	power, err := cli.GetPower(e.opts.chassisID)
+	if err != nil {
+		return err
+	}
+	for _, pc := range power.PowerControl {
+		mb.addGauge("idrac_power_consumed_watts", "Power consumption of a chassis in watts.",
+			pc.PowerConsumedWatts, "control", pc.Name)
+		mb.addGauge("idrac_power_capacity_watts", "Power capacity of a chassis in watts.",
+			pc.PowerCapacityWatts, "control", pc.Name)
+	}
+	for _, psu := range power.PowerSupplies {
+		mb.addHealth("power_supply", psu.MemberID, psu.Status)
+		mb.addGauge("idrac_power_supply_input_watts", "Input power of a power supply in watts.",
+			psu.PowerInputWatts, "power_supply", psu.MemberID)
+	}
+
+	for _, cs := range computerSystems {
+		ports, err := cli.GetNetworkPorts(cs.ID)
+		if err != nil {
+			return err
+		}
+		for _, port := range ports {
+			mb.addHealth("network_port", port.ID, port.Status)
+			linkUp := 0.0
+			if port.LinkStatus == "Up" {
+				linkUp = 1
+			}
+			mb.addGauge("idrac_network_port_link_up", "Whether the link of a network port is up.",
+				linkUp, "system", cs.ID, "port", port.ID)
+			mb.addGauge("idrac_network_port_speed_mbps", "Current link speed of a network port in Mbps.",
+				float64(port.CurrentLinkSpeedMbps), "system", cs.ID, "port", port.ID)
+		}
+	}
+	return nil
+}
+
+func (e *metricsExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		target = e.defaultTarget
+	}
+	if target == "" {
+		http.Error(w, "the target parameter is missing", http.StatusBadRequest)
+		return
+	}
+	entry, exists := e.targets[target]
+	if !exists {
+		http.Error(w, fmt.Sprintf("the target %s is not configured", target), http.StatusForbidden)
+		return
+	}
+	cli, err := entry.newClient()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	mb := newMetricsBuffer()
+	start := time.Now()
+	err = e.scrape(cli, mb)
+	duration := time.Since(start).Seconds()
+
+	e.mu.Lock()
+	e.scrapes[target]++
+	if err != nil {
+		e.errors[target]++
+	}
+	scrapes, errors := e.scrapes[target], e.errors[target]
+	e.mu.Unlock()
+
+	up := 1.0
+	if err != nil {
+		log.Errorf("failed scraping %s: %s", target, err)
+		up = 0
+	}
+	mb.addGauge("idrac_up", "Whether the last scrape of the target was successful.", up)
+	mb.addGauge("idrac_scrape_duration_seconds", "Duration of the scrape of the target in seconds.", duration)
+	mb.add("idrac_scrapes_total", "counter", "Total number of scrapes of the target.", scrapes)
+	mb.add("idrac_scrape_errors_total", "counter", "Total number of failed scrapes of the target.", errors)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprint(w, mb.String())
+}
+
+// runMetricsServer performs the serve-metrics operation.
+func runMetricsServer(targets []*fleetTarget, opts *metricsOptions) error {
+	if len(targets) == 0 {
+		return fmt.Errorf("serve-metrics requires --host, --inventory, --group, or profiles in the configuration file")
+	}
+	exporter := newMetricsExporter(targets, opts)
+	mux := http.NewServeMux()
+	mux.Handle(opts.metricsPath, exporter)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "<html><head><title>%s</title></head><body><h1>%s</h1>", app.Name, app.Description)
+		fmt.Fprintf(w, "<p><a href=\"%s?target=\">Metrics</a> (set target to iDRAC hostname or profile)</p></body></html>\n", opts.metricsPath)
+	})
+	log.Infof("Serving metrics on %s%s", opts.listenAddress, opts.metricsPath)
+	return http.ListenAndServe(opts.listenAddress, mux)
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsBuffer(t *testing.T) {
+	mb := newMetricsBuffer()
+	mb.addGauge("idrac_temperature_celsius", "Temperature reading of a sensor in degrees Celsius.",
+		25.5, "sensor", `System Board "Inlet" Temp`, "context", `C:\Intake`)
+	mb.addHealth("fan", "Fan 1", client.HealthStatus{Health: "OK", State: "Enabled"})
+	mb.addHealth("fan", "Fan 2", client.HealthStatus{Health: "Critical", State: "Enabled"})
+	mb.addHealth("fan", "Fan 3", client.HealthStatus{Health: "OK", State: "Absent"})
+	mb.addHealth("fan", "Fan 4", client.HealthStatus{Health: "Degraded", State: "Enabled"})
+	mb.addGauge("idrac_temperature_celsius", "Temperature reading of a sensor in degrees Celsius.",
+		1e-3, "sensor", "Line\nBreak", "context", "")
+	mb.add("idrac_scrapes_total", "counter", "Total number of scrapes of the target.", 3)
+
+	exp := "# HELP idrac_temperature_celsius Temperature reading of a sensor in degrees Celsius.\n" +
+		"# TYPE idrac_temperature_celsius gauge\n" +
+		`idrac_temperature_celsius{sensor="System Board \"Inlet\" Temp",context="C:\\Intake"} 25.5` + "\n" +
+		`idrac_temperature_celsius{sensor="Line\nBreak",context=""} 0.001` + "\n" +
+		"# HELP idrac_health Health of a component, 0 is OK, 1 is Warning, 2 is Critical, 3 is unknown.\n" +
+		"# TYPE idrac_health gauge\n" +
+		`idrac_health{component="fan",id="Fan 1"} 0` + "\n" +
+		`idrac_health{component="fan",id="Fan 2"} 2` + "\n" +
+		`idrac_health{component="fan",id="Fan 4"} 3` + "\n" +
+		"# HELP idrac_scrapes_total Total number of scrapes of the target.\n" +
+		"# TYPE idrac_scrapes_total counter\n" +
+		"idrac_scrapes_total 3\n"
+	if s := mb.String(); s != exp {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", exp, s)
+	}
+}
+
+func TestMetricsExporter(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	newTarget := func(host, profile, password string) *fleetTarget {
+		return &fleetTarget{
+			host:    host,
+			profile: profile,
+			newClient: func() (*client.Client, error) {
+				cli := client.NewClient()
+				cli.SetHost(server.NonTLS.Hostname)
+				cli.SetPort(server.NonTLS.Port)
+				cli.SetProtocol(server.NonTLS.Protocol)
+				cli.SetUsername("admin")
+				cli.SetPassword(password)
+				return cli, nil
+			},
+		}
+	}
+	// The db target has invalid credentials.
+	exporter := newMetricsExporter([]*fleetTarget{
+		newTarget("10.10.10.10", "web", "secret"),
+		newTarget("10.10.10.11", "db", "invalid"),
+	}, &metricsOptions{chassisID: "System.Embedded.1"})
+
+	testFailed := 0
+	for i, test := range []struct {
+		query      string
+		expStatus  int
+		expMetrics []string
+	}{
+		{
+			// The first target is the default one.
+			query:      "",
+			expStatus:  http.StatusOK,
+			expMetrics: []string{"idrac_up 1", "idrac_scrapes_total 1", "idrac_scrape_errors_total 0", "idrac_system_power_on{"},
+		},
+		{
+			// The profile name is a separate target of the same host.
+			query:      "?target=web",
+			expStatus:  http.StatusOK,
+			expMetrics: []string{"idrac_up 1", "idrac_scrapes_total 1"},
+		},
+		{
+			query:      "?target=10.10.10.10",
+			expStatus:  http.StatusOK,
+			expMetrics: []string{"idrac_up 1", "idrac_scrapes_total 2"},
+		},
+		{
+			query:      "?target=db",
+			expStatus:  http.StatusOK,
+			expMetrics: []string{"idrac_up 0", "idrac_scrapes_total 1", "idrac_scrape_errors_total 1"},
+		},
+		{
+			// The exporter does not scrape the hosts absent from the
+			// configuration.
+			query:     "?target=10.10.10.12",
+			expStatus: http.StatusForbidden,
+		},
+	} {
+		w := httptest.NewRecorder()
+		exporter.ServeHTTP(w, httptest.NewRequest("GET", "/metrics"+test.query, nil))
+		resp := w.Result()
+		body, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode != test.expStatus {
+			t.Logf("FAIL: Test %d: expected status %d, but got %d: %s", i, test.expStatus, resp.StatusCode, body)
+			testFailed++
+			continue
+		}
+		for _, metric := range test.expMetrics {
+			if !strings.Contains(string(body), "\n"+metric) {
+				t.Logf("FAIL: Test %d: expected metric %q, but got:\n%s", i, metric, body)
+				testFailed++
+			}
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+
+	w := httptest.NewRecorder()
+	newMetricsExporter(nil, &metricsOptions{}).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d without targets, but got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// networkOptions holds the arguments of the network protocol operations.
+type networkOptions struct {
+	managerID  string
+	configFile string
+	policyFile string
+
+	// The settings are loaded once and shared by the hosts of a fleet.
+	loadOnce sync.Once
+	config   *client.ManagerNetworkProtocol
+	policy   *client.ManagerNetworkProtocol
+	loadErr  error
+}
+
+func (opts *networkOptions) bindFlags() {
+	flag.StringVar(&opts.managerID, "network.manager", "iDRAC.Embedded.1", "get-network-protocol, set-network-protocol, check-network-protocol: manager id")
+	flag.StringVar(&opts.configFile, "network.config", "", "set-network-protocol: YAML file with the network protocol changes")
+	flag.StringVar(&opts.policyFile, "network.policy", "", "check-network-protocol: YAML file with the network protocol policy, default IPMI and SSDP disabled and NTP enabled")
+}
+
+// load loads the network protocol changes and the policy.
+func (opts *networkOptions) load(operation string) error {
+	opts.loadOnce.Do(func() {
+		if operation == "set-network-protocol" {
+			if opts.configFile == "" {
+				opts.loadErr = fmt.Errorf("--network.config is empty")
+				return
+			}
+			opts.config, opts.loadErr = loadNetworkProtocol(opts.configFile)
+			return
+		}
+		if opts.policyFile == "" {
+			opts.policy = client.DefaultNetworkProtocolPolicy()
+			return
+		}
+		opts.policy, opts.loadErr = loadNetworkProtocol(opts.policyFile)
+	})
+	return opts.loadErr
+}
+
+// loadNetworkProtocol returns the network protocol settings of a YAML file.
+func loadNetworkProtocol(fp string) (*client.ManagerNetworkProtocol, error) {
+	b, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	protocol := &client.ManagerNetworkProtocol{}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(protocol); err != nil {
+		return nil, fmt.Errorf("network protocol settings %s: %s", fp, err)
+	}
+	return protocol, nil
+}
+
+// networkProtocolReport is the outcome of the check-network-protocol
+// operation.
+type networkProtocolReport struct {
+	Host       string                             `yaml:"host" json:"host" xml:"host"`
+	Manager    string                             `yaml:"manager" json:"manager" xml:"manager"`
+	Violations []*client.NetworkProtocolViolation `yaml:"violations" json:"violations" xml:"violations"`
+}
+
+// runNetworkProtocolOperation performs the get-network-protocol,
+// set-network-protocol, and check-network-protocol operations. The
+// check-network-protocol operation exits with code 2 when the settings
+// violate the policy.
+func runNetworkProtocolOperation(cli *client.Client, host string, operation, format string, opts *networkOptions) (*operationResult, error) {
+	if operation != "get-network-protocol" {
+		if err := opts.load(operation); err != nil {
+			return nil, err
+		}
+	}
+	if operation == "set-network-protocol" {
+		if err := cli.SetManagerNetworkProtocol(opts.managerID, opts.config); err != nil {
+			return nil, err
+		}
+	}
+	protocol, err := cli.GetManagerNetworkProtocol(opts.managerID)
+	if err != nil {
+		return nil, err
+	}
+	if operation != "check-network-protocol" {
+		return &operationResult{
+			data: protocol,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				writeNetworkProtocol(w, protocol)
+			},
+		}, nil
+	}
+
+	report := &networkProtocolReport{
+		Host:       host,
+		Manager:    opts.managerID,
+		Violations: client.CheckNetworkProtocol(protocol, opts.policy),
+	}
+	result := &operationResult{
+		data: report,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			if len(report.Violations) == 0 {
+				fmt.Fprintf(w, "Compliant, the network services match the policy\n")
+				return
+			}
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "PROTOCOL\tFIELD\tCURRENT\tEXPECTED")
+			for _, violation := range report.Violations {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", violation.Protocol, violation.Field, violation.Current, violation.Expected)
+			}
+			tw.Flush()
+		},
+	}
+	if format == "table" || format == "csv" {
+		// The tabular formats have a row per violation.
+		result.data = report.Violations
+	}
+	if len(report.Violations) > 0 {
+		result.exitCode = 2
+	}
+	return result, nil
+}
+
+// writeNetworkProtocol writes the network services in the text format.
+func writeNetworkProtocol(w io.Writer, protocol *client.ManagerNetworkProtocol) {
+	fmt.Fprintf(w, "Host Name: %s\n", protocol.HostName)
+	fmt.Fprintf(w, "FQDN: %s\n", protocol.FQDN)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROTOCOL\tENABLED\tPORT\tSETTINGS")
+	row := func(name string, enabled *bool, port *int, settings ...string) {
+		e, p := "", ""
+		if enabled != nil {
+			e = fmt.Sprintf("%t", *enabled)
+		}
+		if port != nil {
+			p = fmt.Sprintf("%d", *port)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, e, p, strings.Join(settings, ", "))
+	}
+	for _, entry := range []struct {
+		name    string
+		service *client.NetworkProtocolService
+	}{
+		{"HTTP", protocol.HTTP},
+		{"HTTPS", protocol.HTTPS},
+		{"SSH", protocol.SSH},
+		{"IPMI", protocol.IPMI},
+		{"VirtualMedia", protocol.VirtualMedia},
+		{"KVMIP", protocol.KVMIP},
+		{"Telnet", protocol.Telnet},
+	} {
+		if entry.service != nil {
+			row(entry.name, entry.service.ProtocolEnabled, entry.service.Port)
+		}
+	}
+	if protocol.SNMP != nil {
+		settings := []string{}
+		for version, enabled := range map[string]*bool{"v1": protocol.SNMP.EnableSNMPv1, "v2c": protocol.SNMP.EnableSNMPv2c, "v3": protocol.SNMP.EnableSNMPv3} {
+			if enabled != nil && *enabled {
+				settings = append(settings, version)
+			}
+		}
+		sort.Strings(settings)
+		row("SNMP", protocol.SNMP.ProtocolEnabled, protocol.SNMP.Port, settings...)
+	}
+	if protocol.NTP != nil {
+		servers := []string{}
+		for _, server := range protocol.NTP.NTPServers {
+			if server != "" {
+				servers = append(servers, server)
+			}
+		}
+		row("NTP", protocol.NTP.ProtocolEnabled, nil, servers...)
+	}
+	if protocol.SSDP != nil {
+		row("SSDP", protocol.SSDP.ProtocolEnabled, protocol.SSDP.Port)
+	}
+	tw.Flush()
+}
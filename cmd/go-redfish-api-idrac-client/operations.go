@@ -0,0 +1,148 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io"
+)
+
+// operationOptions holds the arguments of the operations performed
+// against a host.
+type operationOptions struct {
+	operation   string
+	resource    string
+	format      string
+	healthCheck *healthCheckOptions
+	rotate      *rotatePasswordOptions
+	account     *accountOptions
+	directory   *directoryOptions
+	events      *eventOptions
+	registry    *registryOptions
+	logs        *logOptions
+	firmware    *firmwareOptions
+	jobs        *jobOptions
+	scp         *scpOptions
+	plan        *planOptions
+	drift       *driftOptions
+	attrs       *attrsOptions
+	network     *networkOptions
+	ethernet    *ethernetOptions
+}
+
+// operationResult is the output of an operation performed against a host.
+type operationResult struct {
+	// data is the value rendered by machine-readable output formats.
+	data interface{}
+	// text renders the output in the text format.
+	text func(io.Writer)
+	// exitCode is the exit code of the operation, e.g. check-health state.
+	exitCode int
+}
+
+// runOperation performs an operation, or fetches a resource, using the
+// client configured for a host.
+func runOperation(cli *client.Client, host string, opts *operationOptions) (*operationResult, error) {
+	if opts.resource != "" {
+		res, err := cli.GetResource(opts.resource)
+		if err != nil {
+			return nil, err
+		}
+		var data interface{}
+		if opts.format != "text" {
+			if err := json.Unmarshal(res.Raw, &data); err != nil {
+				return nil, err
+			}
+		}
+		return &operationResult{
+			data: data,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "%s\n", res)
+			},
+		}, nil
+	}
+
+	switch opts.operation {
+	case "get-info":
+		info, err := cli.GetInfo()
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: info,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				fmt.Fprintf(w, "Product: %s\n", info.Product)
+				fmt.Fprintf(w, "Service Tag: %s\n", info.ServiceTag)
+				fmt.Fprintf(w, "Manager MAC Address: %s\n", info.ManagerMACAddress)
+				fmt.Fprintf(w, "Redfish API Version: %s\n", info.RedfishVersion)
+			},
+		}, nil
+	case "get-systems":
+		computerSystems, err := cli.GetComputerSystems()
+		if err != nil {
+			return nil, err
+		}
+		return &operationResult{
+			data: computerSystems,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Number of Computer Systems: %d\n", len(computerSystems))
+				fmt.Fprintf(w, "---------------------------------\n")
+				for _, cs := range computerSystems {
+					if cs.Manufacturer != "" {
+						fmt.Fprintf(w, "System: %s | Manufacturer: %s\n", cs.ID, cs.Manufacturer)
+					}
+					if cs.Model != "" {
+						fmt.Fprintf(w, "System: %s | Model: %s\n", cs.ID, cs.Model)
+					}
+					if cs.SKU != "" {
+						fmt.Fprintf(w, "System: %s | SKU: %s\n", cs.ID, cs.SKU)
+					}
+					if cs.PartNumber != "" {
+						fmt.Fprintf(w, "System: %s | SKU: %s\n", cs.ID, cs.PartNumber)
+					}
+					if cs.BiosVersion != "" {
+						fmt.Fprintf(w, "System: %s | BIOS Version: %s\n", cs.ID, cs.BiosVersion)
+					}
+					spew.Fdump(w, cs)
+				}
+			},
+		}, nil
+	case "check-health":
+		return runHealthCheck(cli, opts.healthCheck), nil
+	case "rotate-password":
+		return runPasswordRotation(cli, host, opts.rotate)
+	case "list-accounts", "list-roles", "get-account-policy", "set-account-policy",
+		"create-account", "update-account", "disable-account", "delete-account":
+		return runAccountOperation(cli, host, opts.operation, opts.account)
+	case "get-directory", "apply-directory":
+		return runDirectoryOperation(cli, host, opts.operation, opts.directory)
+	case "get-event-service", "list-event-subscriptions", "create-event-subscription",
+		"delete-event-subscription", "submit-test-event":
+		return runEventOperation(cli, host, opts.operation, opts.events)
+	case "get-sel", "get-lclog", "clear-sel":
+		return runLogOperation(cli, host, opts.operation, opts.logs)
+	case "get-firmware", "check-firmware", "firmware-update":
+		return runFirmwareOperation(cli, host, opts.operation, opts.format, opts.firmware)
+	case "list-jobs", "wait-job", "delete-job", "list-tasks":
+		return runJobOperation(cli, host, opts.operation, opts.jobs)
+	case "export-scp", "import-scp":
+		return runSCPOperation(cli, host, opts.operation, opts.format, opts.scp)
+	case "plan", "apply":
+		return runPlanOperation(cli, host, opts.operation, opts.format, opts.plan)
+	case "snapshot-config", "drift":
+		return runDriftOperation(cli, host, opts.operation, opts.format, opts.drift)
+	case "get-attributes", "set-attributes":
+		return runAttributesOperation(cli, host, opts.operation, opts.format, opts.attrs)
+	case "get-network-protocol", "set-network-protocol", "check-network-protocol":
+		return runNetworkProtocolOperation(cli, host, opts.operation, opts.format, opts.network)
+	case "get-ethernet-interfaces", "set-ethernet-interface":
+		return runEthernetOperation(cli, host, opts.operation, opts.ethernet)
+	case "resolve-message":
+		return runResolveMessage(cli, host, opts.registry)
+	}
+	return nil, fmt.Errorf("the --operation %s is supported by API, but not this utility", opts.operation)
+}
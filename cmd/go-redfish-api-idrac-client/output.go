@@ -0,0 +1,428 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+)
+
+var outputFormats = []string{"text", "json", "yaml", "xml", "table", "csv"}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// outputOptions holds the arguments controlling the output of operations.
+type outputOptions struct {
+	format  string
+	columns string
+}
+
+func (opts *outputOptions) bindFlags() {
+	flag.StringVar(&opts.format, "format", "text", "output format: "+strings.Join(outputFormats, ", "))
+	flag.StringVar(&opts.columns, "columns", "", "comma-separated columns of table and csv output, e.g. id,model,status.health")
+}
+
+// outputWriter renders the results of operations in the requested format.
+type outputWriter struct {
+	w       io.Writer
+	format  string
+	columns []string
+}
+
+func newOutputWriter(w io.Writer, opts *outputOptions) (*outputWriter, error) {
+	ow := &outputWriter{
+		w:      w,
+		format: opts.format,
+	}
+	found := false
+	for _, f := range outputFormats {
+		if f == opts.format {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unsupported output format %q, supported: %s", opts.format, strings.Join(outputFormats, ", "))
+	}
+	for _, column := range strings.Split(opts.columns, ",") {
+		column = strings.TrimSpace(column)
+		if column != "" {
+			ow.columns = append(ow.columns, column)
+		}
+	}
+	if len(ow.columns) > 0 && ow.format != "table" && ow.format != "csv" {
+		return nil, fmt.Errorf("the --columns argument applies to table and csv formats only")
+	}
+	return ow, nil
+}
+
+// write renders the value. The text function renders the value in the
+// human-readable text format of an operation.
+func (ow *outputWriter) write(v interface{}, text func(io.Writer)) error {
+	switch ow.format {
+	case "text":
+		text(ow.w)
+		return nil
+	case "json":
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(ow.w, "%s\n", b)
+		return err
+	case "yaml":
+		enc := yaml.NewEncoder(ow.w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "xml":
+		if _, err := io.WriteString(ow.w, xml.Header); err != nil {
+			return err
+		}
+		enc := xml.NewEncoder(ow.w)
+		enc.Indent("", "  ")
+		root := xml.StartElement{Name: xml.Name{Local: "results"}}
+		if err := enc.EncodeToken(root); err != nil {
+			return err
+		}
+		if err := encodeXMLValue(enc, xml.StartElement{Name: xml.Name{Local: "item"}}, reflect.ValueOf(v)); err != nil {
+			return err
+		}
+		if err := enc.EncodeToken(root.End()); err != nil {
+			return err
+		}
+		if err := enc.Flush(); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(ow.w)
+		return err
+	}
+
+	columns, rows, err := ow.tabulate(v)
+	if err != nil {
+		return err
+	}
+	if ow.format == "csv" {
+		cw := csv.NewWriter(ow.w)
+		cw.Write(columns)
+		cw.WriteAll(rows)
+		return cw.Error()
+	}
+	tw := tabwriter.NewWriter(ow.w, 0, 0, 2, ' ', 0)
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, strings.ToUpper(column))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// tabulate converts a value, or a slice of values, to rows of columns.
+// Nested fields are flattened into dotted column names, e.g. status.health.
+func (ow *outputWriter) tabulate(v interface{}) ([]string, [][]string, error) {
+	records := []map[string]string{}
+	columns := []string{}
+	seen := make(map[string]bool)
+
+	addRecord := func(item reflect.Value) {
+		record := make(map[string]string)
+		keys := []string{}
+		flattenValue("", item, record, &keys)
+		for _, k := range keys {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+		records = append(records, record)
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			addRecord(rv.Index(i))
+		}
+	} else {
+		addRecord(rv)
+	}
+
+	if len(ow.columns) > 0 {
+		selected := []string{}
+		for _, column := range ow.columns {
+			matched := false
+			for _, k := range columns {
+				if k == column || strings.HasPrefix(k, column+".") {
+					selected = append(selected, k)
+					matched = true
+				}
+			}
+			if !matched {
+				return nil, nil, fmt.Errorf("column %q not found, available columns: %s", column, strings.Join(columns, ", "))
+			}
+		}
+		columns = selected
+	}
+
+	rows := [][]string{}
+	for _, record := range records {
+		row := []string{}
+		for _, column := range columns {
+			row = append(row, record[column])
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows, nil
+}
+
+// fieldName returns the name of a struct field in the output, i.e. the
+// name in json tag.
+func fieldName(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	if tag == "" {
+		return field.Name
+	}
+	return tag
+}
+
+func flattenValue(prefix string, v reflect.Value, record map[string]string, keys *[]string) {
+	set := func(s string) {
+		if _, exists := record[prefix]; !exists {
+			*keys = append(*keys, prefix)
+		}
+		record[prefix] = s
+	}
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+
+	if !v.IsValid() {
+		set("")
+		return
+	}
+	if v.Type().Implements(textMarshalerType) && (v.Kind() != reflect.Ptr || !v.IsNil()) {
+		b, _ := v.Interface().(encoding.TextMarshaler).MarshalText()
+		set(string(b))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			set("")
+			return
+		}
+		flattenValue(prefix, v.Elem(), record, keys)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := fieldName(field)
+			if name == "-" {
+				continue
+			}
+			flattenValue(join(name), v.Field(i), record, keys)
+		}
+	case reflect.Map:
+		mapKeys := []string{}
+		values := make(map[string]reflect.Value)
+		for _, k := range v.MapKeys() {
+			ks := fmt.Sprint(k.Interface())
+			mapKeys = append(mapKeys, ks)
+			values[ks] = v.MapIndex(k)
+		}
+		sort.Strings(mapKeys)
+		for _, k := range mapKeys {
+			flattenValue(join(k), values[k], record, keys)
+		}
+	case reflect.Slice, reflect.Array:
+		items := []string{}
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+				if item.IsNil() {
+					break
+				}
+				item = item.Elem()
+			}
+			switch item.Kind() {
+			case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+				b, _ := json.Marshal(v.Interface())
+				set(string(b))
+				return
+			}
+			items = append(items, scalarString(item))
+		}
+		set(strings.Join(items, ","))
+	default:
+		set(scalarString(v))
+	}
+}
+
+func scalarString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// xmlName returns a valid XML name of a field or a key, i.e. without the
+// leading characters a name cannot start with, and with the other invalid
+// characters replaced with underscores, e.g. odata.id for @odata.id.
+func xmlName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+			b.WriteRune(r)
+		case b.Len() == 0:
+		case r == '.' || r == '-' || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// encodeXMLValue encodes a value as an element. The struct fields follow
+// their xml tags, the slices repeat the element, and the maps, which
+// encoding/xml does not support, have an entry element per key, e.g.
+// <entry key="IPMILan.1.Enable">Disabled</entry>. The nil values are
+// omitted.
+func encodeXMLValue(enc *xml.Encoder, start xml.StartElement, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Implements(textMarshalerType) {
+			break
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		return enc.EncodeElement(string(b), start)
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return enc.EncodeElement(fmt.Sprintf("%s", v.Interface()), start)
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeXMLValue(enc, start, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		mapKeys := []string{}
+		values := make(map[string]reflect.Value)
+		for _, k := range v.MapKeys() {
+			ks := fmt.Sprint(k.Interface())
+			mapKeys = append(mapKeys, ks)
+			values[ks] = v.MapIndex(k)
+		}
+		sort.Strings(mapKeys)
+		for _, k := range mapKeys {
+			entry := xml.StartElement{
+				Name: xml.Name{Local: "entry"},
+				Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: k}},
+			}
+			if err := encodeXMLValue(enc, entry, values[k]); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	case reflect.Struct:
+		fields := []reflect.Value{}
+		names := []string{}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			tag := strings.Split(field.Tag.Get("xml"), ",")
+			if tag[0] == "-" {
+				continue
+			}
+			name := tag[0]
+			if name == "" {
+				name = field.Name
+			}
+			omitEmpty, attr := false, false
+			for _, option := range tag[1:] {
+				switch option {
+				case "omitempty":
+					omitEmpty = true
+				case "attr":
+					attr = true
+				}
+			}
+			if omitEmpty && v.Field(i).IsZero() {
+				continue
+			}
+			if attr {
+				start.Attr = append(start.Attr, xml.Attr{
+					Name:  xml.Name{Local: xmlName(name)},
+					Value: scalarString(v.Field(i)),
+				})
+				continue
+			}
+			fields = append(fields, v.Field(i))
+			names = append(names, xmlName(name))
+		}
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for i, field := range fields {
+			if err := encodeXMLValue(enc, xml.StartElement{Name: xml.Name{Local: names[i]}}, field); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	}
+	return enc.EncodeElement(scalarString(v), start)
+}
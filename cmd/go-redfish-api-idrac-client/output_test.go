@@ -0,0 +1,275 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type outputTestStatus struct {
+	Health string `json:"health" xml:"health"`
+	State  string `json:"state" xml:"state"`
+}
+
+type outputTestItem struct {
+	ID         string                  `json:"id" xml:"id"`
+	OData      *client.ODataAnnotation `json:"odata" xml:"odata"`
+	Speed      float64                 `json:"speed" xml:"speed"`
+	Enabled    bool                    `json:"enabled" xml:"enabled"`
+	Created    time.Time               `json:"created" xml:"created"`
+	Status     outputTestStatus        `json:"status" xml:"status"`
+	Addresses  []string                `json:"addresses" xml:"addresses"`
+	Ports      []*outputTestStatus     `json:"ports" xml:"ports"`
+	Attributes map[string]interface{}  `json:"attributes" xml:"attributes"`
+	Count      uint64                  `json:"Members@odata.count" xml:"Members@odata.count"`
+	Error      string                  `json:"error,omitempty" xml:"error,omitempty"`
+	secret     string
+}
+
+func newOutputTestItems() []*outputTestItem {
+	created, _ := time.Parse(time.RFC3339, "2020-11-12T14:25:31-06:00")
+	return []*outputTestItem{
+		{
+			ID:        "NIC.1",
+			OData:     client.NewODataAnnotation("/redfish/v1/NIC.1", "#Port.v1_0_0.Port", ""),
+			Speed:     2.5,
+			Enabled:   true,
+			Created:   created,
+			Status:    outputTestStatus{Health: "OK", State: "Enabled"},
+			Addresses: []string{"10.0.0.1", "10.0.0.2"},
+			Ports:     []*outputTestStatus{{Health: "OK", State: "Enabled"}},
+			Attributes: map[string]interface{}{
+				"b": 1,
+				"a": "x",
+			},
+			Count:  1,
+			secret: "hidden",
+		},
+		{
+			ID:    "NIC.2",
+			Speed: 1e10,
+			Attributes: map[string]interface{}{
+				"c": nil,
+			},
+			Error: "unreachable",
+		},
+	}
+}
+
+func TestTabulate(t *testing.T) {
+	testFailed := 0
+	for i, test := range []struct {
+		columns    []string
+		input      interface{}
+		expColumns []string
+		expRows    [][]string
+		shouldErr  bool
+	}{
+		{
+			// The columns follow the fields of the first records, and the
+			// nil nested fields are single columns.
+			input: newOutputTestItems(),
+			expColumns: []string{
+				"id", "odata.@odata.id", "odata.@odata.type", "odata.@odata.context",
+				"speed", "enabled", "created", "status.health", "status.state",
+				"addresses", "ports", "attributes.a", "attributes.b", "Members@odata.count",
+				"error", "odata", "attributes.c",
+			},
+			expRows: [][]string{
+				{
+					"NIC.1", "/redfish/v1/NIC.1", "#Port.v1_0_0.Port", "",
+					"2.5", "true", "2020-11-12T14:25:31-06:00", "OK", "Enabled",
+					"10.0.0.1,10.0.0.2", `[{"health":"OK","state":"Enabled"}]`, "x", "1", "1",
+					"", "", "",
+				},
+				{
+					"NIC.2", "", "", "",
+					"10000000000", "false", "0001-01-01T00:00:00Z", "", "",
+					"", "", "", "", "0",
+					"unreachable", "", "",
+				},
+			},
+		},
+		{
+			// A column name without a nested field selects all its
+			// nested fields.
+			columns:    []string{"id", "status", "attributes"},
+			input:      newOutputTestItems(),
+			expColumns: []string{"id", "status.health", "status.state", "attributes.a", "attributes.b", "attributes.c"},
+			expRows: [][]string{
+				{"NIC.1", "OK", "Enabled", "x", "1", ""},
+				{"NIC.2", "", "", "", "", ""},
+			},
+		},
+		{
+			// A single value is a single row.
+			columns:    []string{"health"},
+			input:      &outputTestStatus{Health: "Warning"},
+			expColumns: []string{"health"},
+			expRows:    [][]string{{"Warning"}},
+		},
+		{
+			columns:   []string{"model"},
+			input:     newOutputTestItems(),
+			shouldErr: true,
+		},
+	} {
+		ow := &outputWriter{format: "table", columns: test.columns}
+		columns, rows, err := ow.tabulate(test.input)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: expected to pass, but threw error: %v", i, err)
+				testFailed++
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: expected to throw error, but passed", i)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(columns, test.expColumns) {
+			t.Logf("FAIL: Test %d: expected columns %q, but got %q", i, test.expColumns, columns)
+			testFailed++
+		}
+		if !reflect.DeepEqual(rows, test.expRows) {
+			t.Logf("FAIL: Test %d: expected rows %q, but got %q", i, test.expRows, rows)
+			testFailed++
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestOutputWriter(t *testing.T) {
+	text := func(w io.Writer) { io.WriteString(w, "text output\n") }
+	testFailed := 0
+	for i, test := range []struct {
+		format  string
+		columns string
+		input   interface{}
+		exp     string
+	}{
+		{
+			format: "text",
+			input:  newOutputTestItems(),
+			exp:    "text output\n",
+		},
+		{
+			format:  "csv",
+			columns: "id, status.health,error",
+			input:   newOutputTestItems(),
+			exp:     "id,status.health,error\nNIC.1,OK,\nNIC.2,,unreachable\n",
+		},
+		{
+			format:  "table",
+			columns: "id,speed",
+			input:   newOutputTestItems(),
+			exp:     "ID     SPEED\nNIC.1  2.5\nNIC.2  10000000000\n",
+		},
+		{
+			format: "json",
+			input:  &outputTestStatus{Health: "OK", State: "Enabled"},
+			exp:    "{\n  \"health\": \"OK\",\n  \"state\": \"Enabled\"\n}\n",
+		},
+		{
+			format: "yaml",
+			input:  map[string]interface{}{"b": []int{1}, "a": "x"},
+			exp:    "a: x\nb:\n  - 1\n",
+		},
+		{
+			// The maps are entries with keys, and the names invalid in
+			// XML are converted, e.g. @odata.id.
+			format: "xml",
+			input:  newOutputTestItems()[1:],
+			exp: xml.Header +
+				"<results>\n" +
+				"  <item>\n" +
+				"    <id>NIC.2</id>\n" +
+				"    <speed>10000000000</speed>\n" +
+				"    <enabled>false</enabled>\n" +
+				"    <created>0001-01-01T00:00:00Z</created>\n" +
+				"    <status>\n" +
+				"      <health></health>\n" +
+				"      <state></state>\n" +
+				"    </status>\n" +
+				"    <attributes></attributes>\n" +
+				"    <Members_odata.count>0</Members_odata.count>\n" +
+				"    <error>unreachable</error>\n" +
+				"  </item>\n" +
+				"</results>\n",
+		},
+		{
+			format: "xml",
+			input: map[string]interface{}{
+				"@odata.id": "/redfish/v1/Systems",
+				"Members": []interface{}{
+					map[string]interface{}{"@odata.id": "/redfish/v1/Systems/System.Embedded.1"},
+					map[string]interface{}{"@odata.id": "/redfish/v1/Systems/System.Embedded.2"},
+				},
+				"Status": &client.ODataAnnotation{ID: "/redfish/v1/Systems", Type: "#Systems"},
+			},
+			exp: xml.Header +
+				"<results>\n" +
+				"  <item>\n" +
+				"    <entry key=\"@odata.id\">/redfish/v1/Systems</entry>\n" +
+				"    <entry key=\"Members\">\n" +
+				"      <entry key=\"@odata.id\">/redfish/v1/Systems/System.Embedded.1</entry>\n" +
+				"    </entry>\n" +
+				"    <entry key=\"Members\">\n" +
+				"      <entry key=\"@odata.id\">/redfish/v1/Systems/System.Embedded.2</entry>\n" +
+				"    </entry>\n" +
+				"    <entry key=\"Status\">\n" +
+				"      <odata.id>/redfish/v1/Systems</odata.id>\n" +
+				"      <odata.type>#Systems</odata.type>\n" +
+				"      <odata.context></odata.context>\n" +
+				"    </entry>\n" +
+				"  </item>\n" +
+				"</results>\n",
+		},
+	} {
+		var b bytes.Buffer
+		ow, err := newOutputWriter(&b, &outputOptions{format: test.format, columns: test.columns})
+		if err != nil {
+			t.Fatalf("Test %d: expected success, but got error: %s", i, err)
+		}
+		if err := ow.write(test.input, text); err != nil {
+			t.Logf("FAIL: Test %d: expected success, but got error: %s", i, err)
+			testFailed++
+			continue
+		}
+		if b.String() != test.exp {
+			t.Logf("FAIL: Test %d: expected:\n%s\nbut got:\n%s", i, test.exp, b.String())
+			testFailed++
+			continue
+		}
+		if test.format == "xml" {
+			dec := xml.NewDecoder(&b)
+			for {
+				if _, err := dec.Token(); err != nil {
+					if err != io.EOF {
+						t.Logf("FAIL: Test %d: invalid xml: %s", i, err)
+						testFailed++
+					}
+					break
+				}
+			}
+		}
+	}
+
+	for _, opts := range []*outputOptions{
+		{format: "html"},
+		{format: "json", columns: "id"},
+	} {
+		if _, err := newOutputWriter(&bytes.Buffer{}, opts); err == nil {
+			t.Fatalf("expected failure for %+v, but got non-error response", *opts)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/plan"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// planOptions holds the arguments of the plan and apply operations.
+type planOptions struct {
+	configFile string
+	timeout    time.Duration
+
+	// The desired state is loaded once and shared by the hosts of a fleet.
+	docOnce sync.Once
+	doc     *plan.Document
+	docErr  error
+}
+
+func (opts *planOptions) bindFlags() {
+	flag.StringVar(&opts.configFile, "plan.config", "", "plan, apply: YAML file with the desired BIOS, iDRAC, boot, NIC, and account settings")
+	flag.DurationVar(&opts.timeout, "plan.timeout", time.Hour, "apply: the maximum time to wait for the configuration jobs applied immediately")
+}
+
+func (opts *planOptions) loadDocument() (*plan.Document, error) {
+	opts.docOnce.Do(func() {
+		if opts.configFile == "" {
+			opts.docErr = fmt.Errorf("--plan.config is empty")
+			return
+		}
+		opts.doc, opts.docErr = plan.LoadDocument(opts.configFile)
+	})
+	return opts.doc, opts.docErr
+}
+
+// applyReport is the outcome of the apply operation, i.e. the planned
+// changes, the results of applying them, and the changes remaining after
+// the apply.
+type applyReport struct {
+	Host      string         `yaml:"host" json:"host" xml:"host"`
+	Changes   []*plan.Change `yaml:"changes" json:"changes" xml:"changes"`
+	Results   []*plan.Result `yaml:"results" json:"results" xml:"results"`
+	Remaining []*plan.Change `yaml:"remaining" json:"remaining" xml:"remaining"`
+	Verified  bool           `yaml:"verified" json:"verified" xml:"verified"`
+}
+
+// runPlanOperation performs the plan and apply operations. The plan
+// operation exits with code 2 when the host differs from the desired
+// state. The apply operation exits with code 1 when a change failed, or
+// the host still differs from the desired state after the apply, except
+// for the changes pending the next reboot.
+func runPlanOperation(cli *client.Client, host string, operation, format string, opts *planOptions) (*operationResult, error) {
+	doc, err := opts.loadDocument()
+	if err != nil {
+		return nil, err
+	}
+	p, err := plan.NewPlan(cli, host, doc)
+	if err != nil {
+		return nil, err
+	}
+	if operation == "plan" {
+		result := &operationResult{
+			data: p,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Host: %s\n", host)
+				writeChanges(w, p.Changes)
+			},
+		}
+		if format == "table" || format == "csv" {
+			// The tabular formats have a row per change.
+			result.data = p.Changes
+		}
+		if p.HasChanges() {
+			result.exitCode = 2
+		}
+		return result, nil
+	}
+
+	report := &applyReport{
+		Host:      host,
+		Changes:   p.Changes,
+		Results:   []*plan.Result{},
+		Remaining: []*plan.Change{},
+	}
+	result := &operationResult{
+		data: report,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			writeChanges(w, report.Changes)
+			if len(report.Results) > 0 {
+				tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "RESOURCE\tTARGET\tAPPLY TIME\tCHANGES\tJOB\tSTATUS\tMESSAGE")
+				for _, entry := range report.Results {
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", entry.Resource, entry.Target,
+						entry.ApplyTime, entry.Changes, entry.JobID, entry.Status, entry.Message)
+				}
+				tw.Flush()
+			}
+			if report.Verified {
+				fmt.Fprintf(w, "Verified, the host matches the desired state, except for the changes pending the next reboot\n")
+				return
+			}
+			fmt.Fprintf(w, "Not verified, %d changes remaining\n", len(report.Remaining))
+			writeChanges(w, report.Remaining)
+		},
+	}
+	if format == "table" || format == "csv" {
+		// The tabular formats have a row per result.
+		result.data = report.Results
+	}
+	if !p.HasChanges() {
+		report.Verified = true
+		return result, nil
+	}
+	ctx, cancel := context.WithTimeout(cli.Context(), opts.timeout)
+	defer cancel()
+	report.Results, err = p.Apply(ctx, cli)
+	if err != nil {
+		log.Errorf("%s: %s", host, err)
+		result.exitCode = 1
+		return result, nil
+	}
+	verified, err := plan.NewPlan(cli, host, doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed verifying the desired state: %s", err)
+	}
+	report.Remaining = verified.Remaining(p)
+	report.Verified = len(report.Remaining) == 0
+	if !report.Verified {
+		result.exitCode = 1
+	}
+	return result, nil
+}
+
+// writeChanges writes the changes of a plan in the text format.
+func writeChanges(w io.Writer, changes []*plan.Change) {
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "No changes, the host matches the desired state\n")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESOURCE\tTARGET\tNAME\tCURRENT\tDESIRED\tAPPLY TIME\tPENDING")
+	for _, change := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%t\n", change.Resource, change.Target, change.Name,
+			change.Current, change.Desired, change.ApplyTime, change.Pending)
+	}
+	tw.Flush()
+}
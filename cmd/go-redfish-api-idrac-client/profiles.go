@@ -0,0 +1,98 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"sort"
+)
+
+// newProfileClient returns a client for a profile. The settings passed via
+// command line arguments take precedence over the profile, while the
+// fallback settings, e.g. environment variables, apply when neither sets
+// them.
+func newProfileClient(profile, overrides, fallback *client.HostProfile) (*client.Client, error) {
+	resolved := overrides.Merge(profile).Merge(fallback)
+	if overrides.Credentials != nil && overrides.Credentials.Password != "" {
+		// The password passed via command line arguments applies to all hosts.
+		resolved.Credentials.Hosts = nil
+	}
+	resolved.Name = profile.Name
+	resolved.Group = profile.Group
+	return client.NewClientFromProfile(resolved)
+}
+
+// newGroupTargets returns the fleet targets of the profiles of a group.
+// The CIDR ranges listed in a group expand into a target per address.
+func newGroupTargets(cfg *client.Config, group string, overrides, fallback *client.HostProfile) ([]*fleetTarget, error) {
+	profiles, err := cfg.GetGroupProfiles(group)
+	if err != nil {
+		return nil, err
+	}
+	return newProfileTargets(profiles, overrides, fallback)
+}
+
+// newConfigTargets returns the fleet targets of the profiles and the groups
+// of the configuration.
+func newConfigTargets(cfg *client.Config, overrides, fallback *client.HostProfile) ([]*fleetTarget, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	names := []string{}
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	profiles := []*client.HostProfile{}
+	for _, name := range names {
+		profile, err := cfg.GetProfile(name)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	targets, err := newProfileTargets(profiles, overrides, fallback)
+	if err != nil {
+		return nil, err
+	}
+	groups := []string{}
+	for group := range cfg.Groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		groupTargets, err := newGroupTargets(cfg, group, overrides, fallback)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, groupTargets...)
+	}
+	return targets, nil
+}
+
+// newProfileTargets returns the fleet targets of profiles. The CIDR ranges
+// of the profiles expand into a target per address.
+func newProfileTargets(profiles []*client.HostProfile, overrides, fallback *client.HostProfile) ([]*fleetTarget, error) {
+	targets := []*fleetTarget{}
+	for _, profile := range profiles {
+		hosts := newInventory()
+		if err := hosts.add(profile.Host); err != nil {
+			return nil, err
+		}
+		for _, host := range hosts.hosts {
+			hostProfile := profile.Merge(nil)
+			hostProfile.Host = host
+			if len(hosts.hosts) > 1 {
+				hostProfile.Name = host
+			}
+			targets = append(targets, &fleetTarget{
+				host:    host,
+				profile: hostProfile.Name,
+				newClient: func() (*client.Client, error) {
+					return newProfileClient(hostProfile, overrides, fallback)
+				},
+			})
+		}
+	}
+	return targets, nil
+}
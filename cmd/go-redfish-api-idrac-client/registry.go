@@ -0,0 +1,47 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io"
+)
+
+// registryOptions holds the arguments of the message registry operations.
+type registryOptions struct {
+	cacheDir    string
+	messageID   string
+	messageArgs string
+}
+
+func (opts *registryOptions) bindFlags() {
+	flag.StringVar(&opts.cacheDir, "registry.cache-dir", client.DefaultMessageRegistryCacheDir(), "directory caching message registries, empty disables caching")
+	flag.StringVar(&opts.messageID, "registry.message-id", "", "resolve-message: message id, e.g. IDRAC.2.1.SYS403 or SYS403")
+	flag.StringVar(&opts.messageArgs, "registry.message-args", "", "resolve-message: comma-separated message arguments")
+}
+
+// runResolveMessage performs the resolve-message operation.
+func runResolveMessage(cli *client.Client, host string, opts *registryOptions) (*operationResult, error) {
+	if opts.messageID == "" {
+		return nil, fmt.Errorf("the --registry.message-id argument is required")
+	}
+	registries, err := cli.GetMessageRegistries(opts.cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	m, err := registries.Resolve(opts.messageID, splitList(opts.messageArgs))
+	if err != nil {
+		return nil, err
+	}
+	return &operationResult{
+		data: m,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Message ID: %s\n", m.MessageID)
+			fmt.Fprintf(w, "Message: %s\n", m.Message)
+			fmt.Fprintf(w, "Severity: %s\n", m.Severity)
+			fmt.Fprintf(w, "Resolution: %s\n", m.Resolution)
+		},
+	}, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+)
+
+// rotatePasswordOptions holds the arguments of the rotate-password
+// operation. The passwords come from credential sources, i.e. never from
+// command line arguments.
+type rotatePasswordOptions struct {
+	account            string
+	newPasswordEnv     string
+	newPasswordFile    string
+	newPasswordCommand string
+	oldPasswordEnv     string
+	oldPasswordFile    string
+	oldPasswordCommand string
+	auditLogFile       string
+	newPassword        client.CredentialProvider
+	oldPassword        client.CredentialProvider
+	auditLog           *rotationAuditLog
+}
+
+func (opts *rotatePasswordOptions) bindFlags() {
+	flag.StringVar(&opts.account, "rotate.account", "", "rotate-password: username of the account to rotate")
+	flag.StringVar(&opts.newPasswordEnv, "rotate.new-password-env", "", "rotate-password: environment variable holding the new password")
+	flag.StringVar(&opts.newPasswordFile, "rotate.new-password-file", "", "rotate-password: file holding the new password")
+	flag.StringVar(&opts.newPasswordCommand, "rotate.new-password-command", "", "rotate-password: command printing the new password of the host in IDRAC_API_HOST")
+	flag.StringVar(&opts.oldPasswordEnv, "rotate.old-password-env", "", "rotate-password: environment variable holding the old password, used for rollback")
+	flag.StringVar(&opts.oldPasswordFile, "rotate.old-password-file", "", "rotate-password: file holding the old password, used for rollback")
+	flag.StringVar(&opts.oldPasswordCommand, "rotate.old-password-command", "", "rotate-password: command printing the old password, used for rollback")
+	flag.StringVar(&opts.auditLogFile, "rotate.audit-log", "password-rotation-audit.jsonl", "rotate-password: file the audit records are appended to")
+}
+
+// init validates the arguments and opens the audit log.
+func (opts *rotatePasswordOptions) init() error {
+	if opts.account == "" {
+		return fmt.Errorf("the --rotate.account argument is required")
+	}
+	var err error
+	opts.newPassword, err = newPasswordProvider(opts.newPasswordEnv, opts.newPasswordFile, opts.newPasswordCommand)
+	if err != nil {
+		return fmt.Errorf("new password: %s", err)
+	}
+	if opts.newPassword == nil {
+		return fmt.Errorf("one of --rotate.new-password-env, --rotate.new-password-file, --rotate.new-password-command arguments is required")
+	}
+	opts.oldPassword, err = newPasswordProvider(opts.oldPasswordEnv, opts.oldPasswordFile, opts.oldPasswordCommand)
+	if err != nil {
+		return fmt.Errorf("old password: %s", err)
+	}
+	opts.auditLog, err = openRotationAuditLog(opts.auditLogFile)
+	return err
+}
+
+// rotationAuditRecord is the audit record of the rotation of a password
+// on a host. It holds no secrets.
+type rotationAuditRecord struct {
+	Time       string `yaml:"time" json:"time" xml:"time"`
+	Operator   string `yaml:"operator" json:"operator" xml:"operator"`
+	Host       string `yaml:"host" json:"host" xml:"host"`
+	UserName   string `yaml:"user_name" json:"user_name" xml:"user_name"`
+	AccountID  string `yaml:"account_id" json:"account_id" xml:"account_id"`
+	Changed    bool   `yaml:"changed" json:"changed" xml:"changed"`
+	Verified   bool   `yaml:"verified" json:"verified" xml:"verified"`
+	RolledBack bool   `yaml:"rolled_back" json:"rolled_back" xml:"rolled_back"`
+	Success    bool   `yaml:"success" json:"success" xml:"success"`
+	Error      string `yaml:"error,omitempty" json:"error,omitempty" xml:"error,omitempty"`
+}
+
+// rotationAuditLog appends audit records to a file in JSON Lines format.
+type rotationAuditLog struct {
+	mu       sync.Mutex
+	w        io.Writer
+	operator string
+}
+
+func openRotationAuditLog(fp string) (*rotationAuditLog, error) {
+	l := &rotationAuditLog{w: ioutil.Discard}
+	if u, err := user.Current(); err == nil {
+		l.operator = u.Username
+	}
+	if fp == "" {
+		return l, nil
+	}
+	f, err := os.OpenFile(fp, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("audit log: %s", err)
+	}
+	l.w = f
+	return l, nil
+}
+
+func (l *rotationAuditLog) write(record *rotationAuditRecord) error {
+	record.Operator = l.operator
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = fmt.Fprintf(l.w, "%s\n", b)
+	return err
+}
+
+// runPasswordRotation performs the rotate-password operation.
+func runPasswordRotation(cli *client.Client, host string, opts *rotatePasswordOptions) (*operationResult, error) {
+	record := &rotationAuditRecord{
+		Time:     time.Now().UTC().Format(time.RFC3339),
+		Host:     host,
+		UserName: opts.account,
+	}
+	rotation, err := rotateHostPassword(cli, host, opts)
+	if rotation != nil {
+		record.AccountID = rotation.AccountID
+		record.Changed = rotation.Changed
+		record.Verified = rotation.Verified
+		record.RolledBack = rotation.RolledBack
+	}
+	record.Success = err == nil
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if auditErr := opts.auditLog.write(record); auditErr != nil {
+		return nil, fmt.Errorf("audit log: %s; rotation: %+v", auditErr, *record)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &operationResult{
+		data: record,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", record.Host)
+			fmt.Fprintf(w, "Account: %s (ID %s)\n", record.UserName, record.AccountID)
+			fmt.Fprintf(w, "Password: changed and verified\n")
+		},
+	}, nil
+}
+
+func rotateHostPassword(cli *client.Client, host string, opts *rotatePasswordOptions) (*client.PasswordRotation, error) {
+	creds, err := opts.newPassword.GetCredentials(host)
+	if err != nil {
+		return nil, fmt.Errorf("new password: %s", err)
+	}
+	var oldPassword string
+	if opts.oldPassword != nil {
+		oldCreds, err := opts.oldPassword.GetCredentials(host)
+		if err != nil {
+			return nil, fmt.Errorf("old password: %s", err)
+		}
+		oldPassword = oldCreds.Password
+	}
+	return cli.RotateAccountPassword(opts.account, oldPassword, creds.Password)
+}
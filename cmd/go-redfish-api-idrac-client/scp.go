@@ -0,0 +1,141 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"io/ioutil"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// scpOptions holds the arguments of Server Configuration Profile operations.
+type scpOptions struct {
+	targets        string
+	format         string
+	exportUse      string
+	file           string
+	shutdownType   string
+	hostPowerState string
+	preview        bool
+	timeout        time.Duration
+}
+
+func (opts *scpOptions) bindFlags() {
+	flag.StringVar(&opts.targets, "scp.targets", "ALL", "export-scp: comma-separated components of the profile, e.g. IDRAC,BIOS,NIC,RAID")
+	flag.StringVar(&opts.format, "scp.format", "XML", "export-scp: format of the profile, either XML or JSON")
+	flag.StringVar(&opts.exportUse, "scp.export-use", "Default", "export-scp: export use, i.e. Default, Clone, or Replace")
+	flag.StringVar(&opts.file, "scp.file", "", "export-scp, import-scp: profile file, {host} is replaced with the host, default stdout for export-scp")
+	flag.StringVar(&opts.shutdownType, "scp.shutdown-type", "Graceful", "import-scp: shutdown of the host, i.e. Graceful, Forced, or NoReboot")
+	flag.StringVar(&opts.hostPowerState, "scp.host-power-state", "On", "import-scp: power state of the host after the import, i.e. On or Off")
+	flag.BoolVar(&opts.preview, "scp.preview", false, "import-scp: report the changes the import would apply without applying them")
+	flag.DurationVar(&opts.timeout, "scp.timeout", 30*time.Minute, "export-scp, import-scp: the maximum time to wait for the job")
+}
+
+// init validates the profile file of the export-scp operation against
+// several hosts, i.e. each host requires its own file.
+func (opts *scpOptions) init(targets int) error {
+	if targets < 2 || strings.Contains(opts.file, "{host}") {
+		return nil
+	}
+	if opts.file == "" {
+		return fmt.Errorf("--scp.file is empty, the profiles of %d hosts require {host} in the file path", targets)
+	}
+	return fmt.Errorf("--scp.file %s is the same for every host, use {host} with --inventory or --group", opts.file)
+}
+
+// profileFile returns the profile file of a host.
+func (opts *scpOptions) profileFile(host string) string {
+	return strings.ReplaceAll(opts.file, "{host}", host)
+}
+
+// runSCPOperation performs the export-scp and import-scp operations.
+func runSCPOperation(cli *client.Client, host string, operation, format string, opts *scpOptions) (*operationResult, error) {
+	ctx, cancel := context.WithTimeout(cli.Context(), opts.timeout)
+	defer cancel()
+	if operation == "import-scp" {
+		return runSCPImport(ctx, cli, host, format, opts)
+	}
+	profile, err := cli.ExportSCP(ctx, splitList(opts.targets), opts.format, opts.exportUse)
+	if err != nil {
+		return nil, err
+	}
+	if opts.file == "" {
+		return &operationResult{
+			data: string(profile),
+			text: func(w io.Writer) {
+				w.Write(profile)
+			},
+		}, nil
+	}
+	fp := opts.profileFile(host)
+	if err := ioutil.WriteFile(fp, profile, 0600); err != nil {
+		return nil, err
+	}
+	return &operationResult{
+		data: map[string]interface{}{
+			"file": fp,
+			"size": len(profile),
+		},
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Profile: %s, %d bytes\n", fp, len(profile))
+		},
+	}, nil
+}
+
+// runSCPImport imports, or previews the import of, a profile. The operation
+// exits with code 1 when the import failed.
+func runSCPImport(ctx context.Context, cli *client.Client, host, format string, opts *scpOptions) (*operationResult, error) {
+	if opts.file == "" {
+		return nil, fmt.Errorf("--scp.file is empty")
+	}
+	profile, err := ioutil.ReadFile(opts.profileFile(host))
+	if err != nil {
+		return nil, err
+	}
+	var result *client.SCPImportResult
+	if opts.preview {
+		result, err = cli.PreviewSCP(ctx, profile)
+	} else {
+		result, err = cli.ImportSCP(ctx, profile, opts.shutdownType, opts.hostPowerState)
+	}
+	if result == nil {
+		return nil, err
+	}
+	output := &operationResult{
+		data: result,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "Host: %s\n", host)
+			fmt.Fprintf(w, "Job: %s\n", result.JobID)
+			fmt.Fprintf(w, "State: %s\n", result.TaskState)
+			fmt.Fprintf(w, "Message: %s\n", result.Message)
+			if result.Preview {
+				fmt.Fprintf(w, "Changes (preview): %d\n", len(result.Changes))
+			} else {
+				fmt.Fprintf(w, "Changes: %d\n", len(result.Changes))
+			}
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "FQDD\tNAME\tOLD\tNEW\tSTATUS")
+			for _, change := range result.Changes {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", change.FQDD, change.Name, change.OldValue, change.NewValue, change.Status)
+			}
+			tw.Flush()
+		},
+	}
+	if format == "table" || format == "csv" {
+		// The tabular formats have a row per change.
+		output.data = result.Changes
+	}
+	if err != nil {
+		log.Errorf("%s: %s", host, err)
+		output.exitCode = 1
+	}
+	return output, nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslogEnterpriseID is the private enterprise number of Dell, used in the
+// structured data identifiers of the forwarded messages.
+const syslogEnterpriseID = 674
+
+// syslogFacilities maps the names of syslog facilities to their codes.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"authpriv": 10,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// syslogSeverity returns the syslog severity of a Redfish severity, i.e.
+// Critical is crit, Warning is warning, and anything else is info.
+func syslogSeverity(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return 2
+	case "warning":
+		return 4
+	}
+	return 6
+}
+
+// syslogParam is a parameter of the structured data element of a syslog
+// message.
+type syslogParam struct {
+	name  string
+	value string
+}
+
+// syslogMessage is RFC 5424 syslog message.
+type syslogMessage struct {
+	facility  int
+	severity  int
+	timestamp time.Time
+	hostname  string
+	appName   string
+	msgID     string
+	params    []syslogParam
+	message   string
+}
+
+// syslogHeaderValue returns the value of a header field, i.e. the NILVALUE
+// when the value is empty, and the value without spaces otherwise.
+func syslogHeaderValue(s string, maxLen int) string {
+	s = strings.Join(strings.Fields(s), "_")
+	if s == "" {
+		return "-"
+	}
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	return s
+}
+
+// String returns the message formatted per RFC 5424.
+func (m *syslogMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>1 ", m.facility*8+m.severity)
+	if m.timestamp.IsZero() {
+		b.WriteString("-")
+	} else {
+		b.WriteString(m.timestamp.Format(time.RFC3339Nano))
+	}
+	fmt.Fprintf(&b, " %s %s - %s ",
+		syslogHeaderValue(m.hostname, 255),
+		syslogHeaderValue(m.appName, 48),
+		syslogHeaderValue(m.msgID, 32),
+	)
+	if len(m.params) == 0 {
+		b.WriteString("-")
+	} else {
+		fmt.Fprintf(&b, "[idrac@%d", syslogEnterpriseID)
+		for _, p := range m.params {
+			fmt.Fprintf(&b, " %s=\"%s\"", p.name, escapeSyslogParam(p.value))
+		}
+		b.WriteString("]")
+	}
+	if m.message != "" {
+		b.WriteString(" ")
+		b.WriteString(m.message)
+	}
+	return b.String()
+}
+
+// escapeSyslogParam escapes the characters a structured data parameter
+// value must not contain unescaped, i.e. double quote, backslash, and
+// closing bracket.
+func escapeSyslogParam(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
+
+// syslogWriter sends syslog messages to a remote syslog server over UDP,
+// TCP, or TLS. The messages sent over TCP and TLS are framed using octet
+// counting per RFC 6587.
+type syslogWriter struct {
+	mu        sync.Mutex
+	protocol  string
+	address   string
+	tlsConfig *tls.Config
+	conn      net.Conn
+}
+
+// newSyslogWriter returns an instance of syslogWriter. The connection is
+// established when the first message is sent.
+func newSyslogWriter(protocol, address, caFile string, insecure bool) (*syslogWriter, error) {
+	if address == "" {
+		return nil, fmt.Errorf("syslog server address is empty")
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, fmt.Errorf("syslog server address %s is invalid: %s", address, err)
+	}
+	w := &syslogWriter{
+		protocol: strings.ToLower(protocol),
+		address:  address,
+	}
+	switch w.protocol {
+	case "udp", "tcp":
+	case "tls":
+		w.tlsConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: insecure,
+		}
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			w.tlsConfig.ServerName = host
+		}
+		if caFile != "" {
+			data, err := ioutil.ReadFile(caFile)
+			if err != nil {
+				return nil, err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("no certificates found in %s", caFile)
+			}
+			w.tlsConfig.RootCAs = pool
+		}
+	default:
+		return nil, fmt.Errorf("unsupported syslog protocol %s, expecting udp, tcp, or tls", protocol)
+	}
+	return w, nil
+}
+
+func (w *syslogWriter) connect() error {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	var err error
+	if w.protocol == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", w.address, w.tlsConfig)
+	} else {
+		conn, err = dialer.Dial(w.protocol, w.address)
+	}
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+func (w *syslogWriter) send(msg string) error {
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return err
+		}
+	}
+	if w.protocol != "udp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	w.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := w.conn.Write([]byte(msg)); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
+
+// Write sends a message to the syslog server. When the server closed the
+// connection, the writer reconnects and sends the message again.
+func (w *syslogWriter) Write(m *syslogMessage) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	msg := m.String()
+	if err := w.send(msg); err != nil {
+		if w.protocol == "udp" {
+			return err
+		}
+		return w.send(msg)
+	}
+	return nil
+}
+
+// Close closes the connection to the syslog server.
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
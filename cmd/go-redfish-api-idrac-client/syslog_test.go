@@ -0,0 +1,193 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// syslogListener is a syslog server receiving the messages sent over UDP,
+// or over TCP with octet counting framing.
+type syslogListener struct {
+	address  string
+	messages chan string
+	close    func()
+}
+
+func newSyslogListener(t *testing.T, protocol string) *syslogListener {
+	l := &syslogListener{messages: make(chan string, 100)}
+	if protocol == "udp" {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to start syslog listener: %s", err)
+		}
+		l.address = conn.LocalAddr().String()
+		l.close = func() { conn.Close() }
+		go func() {
+			buf := make([]byte, 65536)
+			for {
+				n, _, err := conn.ReadFrom(buf)
+				if err != nil {
+					return
+				}
+				l.messages <- string(buf[:n])
+			}
+		}()
+		return l
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start syslog listener: %s", err)
+	}
+	l.address = listener.Addr().String()
+	l.close = func() { listener.Close() }
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					size, err := r.ReadString(' ')
+					if err != nil {
+						return
+					}
+					n, err := strconv.Atoi(strings.TrimSuffix(size, " "))
+					if err != nil {
+						l.messages <- "invalid frame: " + size
+						return
+					}
+					msg := make([]byte, n)
+					if _, err := io.ReadFull(r, msg); err != nil {
+						return
+					}
+					l.messages <- string(msg)
+				}
+			}(conn)
+		}
+	}()
+	return l
+}
+
+// receive returns the next message received by the listener.
+func (l *syslogListener) receive(t *testing.T) string {
+	select {
+	case msg := <-l.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no syslog message received from %s", l.address)
+	}
+	return ""
+}
+
+func TestSyslogMessage(t *testing.T) {
+	created, _ := time.Parse(time.RFC3339, "2020-11-12T14:25:31-06:00")
+	testFailed := 0
+	for i, test := range []struct {
+		msg *syslogMessage
+		exp string
+	}{
+		{
+			msg: &syslogMessage{
+				facility:  16,
+				severity:  syslogSeverity("Warning"),
+				timestamp: created,
+				hostname:  "10.10.10.10",
+				appName:   "idrac",
+				msgID:     "Sel",
+				params: []syslogParam{
+					{"entryId", "3"},
+					{"messageId", "TMP0118"},
+				},
+				message: "The system inlet temperature is less than the lower warning threshold.",
+			},
+			exp: `<132>1 2020-11-12T14:25:31-06:00 10.10.10.10 idrac - Sel [idrac@674 entryId="3" messageId="TMP0118"] ` +
+				`The system inlet temperature is less than the lower warning threshold.`,
+		},
+		{
+			// The structured data parameter values escape double quotes,
+			// backslashes, and closing brackets.
+			msg: &syslogMessage{
+				facility:  23,
+				severity:  syslogSeverity("Critical"),
+				timestamp: created.Add(500 * time.Millisecond),
+				hostname:  "idrac-1",
+				appName:   "idrac",
+				msgID:     "Lclog",
+				params: []syslogParam{
+					{"message", `Disk "0:1" at C:\slot [1]`},
+				},
+				message: `Disk "0:1" at C:\slot [1]`,
+			},
+			exp: `<186>1 2020-11-12T14:25:31.5-06:00 idrac-1 idrac - Lclog [idrac@674 message="Disk \"0:1\" at C:\\slot [1\]"] ` +
+				`Disk "0:1" at C:\slot [1]`,
+		},
+		{
+			// The empty header fields and structured data are NILVALUE,
+			// and the spaces in the header fields are replaced.
+			msg: &syslogMessage{
+				facility: 1,
+				severity: syslogSeverity("OK"),
+				hostname: "idrac 1",
+				appName:  strings.Repeat("a", 50),
+			},
+			exp: "<14>1 - idrac_1 " + strings.Repeat("a", 48) + " - - -",
+		},
+	} {
+		if s := test.msg.String(); s != test.exp {
+			t.Logf("FAIL: Test %d: expected %q, but got %q", i, test.exp, s)
+			testFailed++
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestSyslogWriter(t *testing.T) {
+	msgs := []*syslogMessage{
+		{facility: 16, severity: 6, hostname: "idrac-1", appName: "idrac", msgID: "Sel", message: "first"},
+		{facility: 16, severity: 2, hostname: "idrac-1", appName: "idrac", msgID: "Sel", message: "second message\nwith two lines"},
+	}
+	for _, protocol := range []string{"udp", "tcp"} {
+		listener := newSyslogListener(t, protocol)
+		writer, err := newSyslogWriter(protocol, listener.address, "", false)
+		if err != nil {
+			t.Fatalf("%s: expected success, but got error: %s", protocol, err)
+		}
+		for _, msg := range msgs {
+			if err := writer.Write(msg); err != nil {
+				t.Fatalf("%s: expected success, but got error: %s", protocol, err)
+			}
+		}
+		for _, msg := range msgs {
+			if received := listener.receive(t); received != msg.String() {
+				t.Fatalf("%s: expected %q, but got %q", protocol, msg.String(), received)
+			}
+		}
+		writer.Close()
+		listener.close()
+	}
+
+	for _, test := range []struct {
+		protocol string
+		address  string
+	}{
+		{protocol: "udp", address: ""},
+		{protocol: "udp", address: "127.0.0.1"},
+		{protocol: "http", address: "127.0.0.1:514"},
+	} {
+		if _, err := newSyslogWriter(test.protocol, test.address, "", false); err == nil {
+			t.Fatalf("expected failure for %s://%s, but got non-error response", test.protocol, test.address)
+		}
+	}
+}
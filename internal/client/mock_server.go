@@ -2,6 +2,7 @@ package internal
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"io/ioutil"
@@ -11,6 +12,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // MockTestServerInstance is an instance of a mock web server.
@@ -23,9 +25,52 @@ type MockTestServerInstance struct {
 }
 
 // MockTestServer is a mock web server. The server supports both HTTPS and HTTP.
+// The endpoints prefixed with a method, e.g. "POST /redfish/v1/...", serve the
+// requests with the method. The POST requests responding with a resource
+// return 201 Created and the Location of the resource, and the PATCH requests
+// responding with a job return 202 Accepted and the Location of the job. The
+// PATCH requests to GET endpoints succeed, and the PATCH requests changing
+// the Password of an account change the password the server accepts for the
+// UserName of the account. The multipart uploads without a file fail. The GET
+// endpoints with comma-separated responses serve the responses in turn. The
+// event stream, i.e. /redfish/v1/SSE, sends one event per connection.
 type MockTestServer struct {
 	NonTLS *MockTestServerInstance
 	TLS    *MockTestServerInstance
+	mu     sync.Mutex
+	users  map[string]string
+	served map[string]int
+}
+
+// nextResponse returns the response file of an endpoint. The endpoints with
+// comma-separated response files serve the files in turn, and then keep
+// serving the last file, e.g. the states of a task.
+func (srv *MockTestServer) nextResponse(endpoint, respFileNames string) string {
+	names := strings.Split(respFileNames, ",")
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	i := srv.served[endpoint]
+	if i < len(names)-1 {
+		srv.served[endpoint] = i + 1
+	}
+	if i >= len(names) {
+		i = len(names) - 1
+	}
+	return names[i]
+}
+
+// SetUser sets the password the server accepts for a user.
+func (srv *MockTestServer) SetUser(username, password string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.users[username] = password
+}
+
+func (srv *MockTestServer) isAuthorized(username, password string) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	expected, exists := srv.users[username]
+	return exists && expected == password
 }
 
 // Close closes running instances of MockTestServerInstance, if any.
@@ -45,6 +90,10 @@ func NewMockTestServer(pathMap map[string]string, tlsEnabled bool) (*MockTestSer
 	mts := &MockTestServer{
 		NonTLS: &MockTestServerInstance{},
 		TLS:    &MockTestServerInstance{},
+		users: map[string]string{
+			"admin": "secret",
+		},
+		served: make(map[string]int),
 	}
 	serverEndpoints := map[string]string{
 		"/redfish/v1/":                           "root_1.json",
@@ -69,8 +118,81 @@ func NewMockTestServer(pathMap map[string]string, tlsEnabled bool) (*MockTestSer
 		"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Slot.2/NetworkDeviceFunctions/NIC.Slot.2-1-1":             "network_device_function_slot_2_1_1.json",
 		"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Slot.2/NetworkDeviceFunctions/NIC.Slot.2-2-1":             "network_device_function_slot_2_2_1.json",
 		"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Slot.2/NetworkPorts/":                                     "network_port_collection_slot_2.json",
-		"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Integrated.1/NetworkPorts/NIC.Slot.2-1":                   "network_port_slot_2_1.json",
-		"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Integrated.1/NetworkPorts/NIC.Slot.2-2":                   "network_port_slot_2_2.json",
+		"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Slot.2/NetworkPorts/NIC.Slot.2-1":                         "network_port_slot_2_1.json",
+		"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Slot.2/NetworkPorts/NIC.Slot.2-2":                         "network_port_slot_2_2.json",
+		"/redfish/v1/Chassis/System.Embedded.1/Thermal/":                                                                     "thermal_1.json",
+		"/redfish/v1/Chassis/System.Embedded.1/Power/":                                                                       "power_1.json",
+		"/redfish/v1/AccountService/":                                                                                        "account_service_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/Attributes":                                                                   "manager_attributes_1.json",
+		"/redfish/v1/AccountService/Roles/":                                                                                  "role_collection_1.json",
+		"/redfish/v1/AccountService/Roles/Administrator":                                                                     "role_administrator.json",
+		"/redfish/v1/AccountService/Roles/Operator":                                                                          "role_operator.json",
+		"/redfish/v1/AccountService/Roles/ReadOnly":                                                                          "role_readonly.json",
+		"/redfish/v1/AccountService/Accounts/":                                                                               "account_collection_1.json",
+		"/redfish/v1/AccountService/Accounts/1":                                                                              "account_1.json",
+		"/redfish/v1/AccountService/Accounts/2":                                                                              "account_2.json",
+		"/redfish/v1/AccountService/Accounts/3":                                                                              "account_3.json",
+		"/redfish/v1/AccountService/Accounts/4":                                                                              "account_4.json",
+		"/redfish/v1/EventService/":                                                                                          "event_service_1.json",
+		"/redfish/v1/EventService/Subscriptions/":                                                                            "event_subscription_collection_1.json",
+		"/redfish/v1/EventService/Subscriptions/c1a71140-ba1d-11e9-842f-d094662a05e6":                                        "event_subscription_1.json",
+		"POST /redfish/v1/EventService/Subscriptions/":                                                                       "event_subscription_1.json",
+		"DELETE /redfish/v1/EventService/Subscriptions/c1a71140-ba1d-11e9-842f-d094662a05e6":                                 "success_1.json",
+		"POST /redfish/v1/EventService/Actions/EventService.SubmitTestEvent":                                                 "success_1.json",
+		"/redfish/v1/Registries/":                                                                                            "registry_collection_1.json",
+		"/redfish/v1/Registries/BaseMessages":                                                                                "registry_file_base_1.json",
+		"/redfish/v1/Registries/Messages":                                                                                    "registry_file_idrac_1.json",
+		"/redfish/v1/Registries/BaseMessages/BaseRegistry.v1_0_0":                                                            "message_registry_base_1.json",
+		"/redfish/v1/Registries/Messages/EEMIRegistry.v1_5_0":                                                                "message_registry_idrac_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/":                                                                 "log_service_collection_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel":                                                              "log_service_sel_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Lclog":                                                            "log_service_lclog_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries/":                                                     "log_entry_collection_sel_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries?$skip=2":                                              "log_entry_collection_sel_2.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Lclog/Entries/":                                                   "log_entry_collection_lclog_1.json",
+		"POST /redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Actions/LogService.ClearLog":                             "success_1.json",
+		"/redfish/v1/UpdateService/":                                                                                         "update_service_1.json",
+		"/redfish/v1/UpdateService/FirmwareInventory/":                                                                       "software_inventory_collection_1.json",
+		"/redfish/v1/UpdateService/FirmwareInventory/Installed-25227-4.22.00.00":                                             "software_inventory_1.json",
+		"/redfish/v1/UpdateService/FirmwareInventory/Installed-159-2.8.2":                                                    "software_inventory_2.json",
+		"/redfish/v1/UpdateService/FirmwareInventory/Installed-101548-20.5.13":                                               "software_inventory_3.json",
+		"/redfish/v1/UpdateService/FirmwareInventory/Installed-104356-50.9.4-3025":                                           "software_inventory_4.json",
+		"/redfish/v1/UpdateService/FirmwareInventory/Installed-18765-20.08.10":                                               "software_inventory_5.json",
+		"/redfish/v1/UpdateService/FirmwareInventory/Previous-159-2.7.7":                                                     "software_inventory_6.json",
+		"/redfish/v1/UpdateService/FirmwareInventory/Available-104356-51.13.0-3485":                                          "software_inventory_7.json",
+		"POST /redfish/v1/UpdateService/FirmwareInventory":                                                                   "software_inventory_7.json",
+		"POST /redfish/v1/UpdateService/MultipartUpload":                                                                     "task_1.json",
+		"POST /redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate":                                                  "task_1.json",
+		"/redfish/v1/TaskService/Tasks/":                                                                                     "task_collection_1.json",
+		"/redfish/v1/TaskService/Tasks/JID_467762674724":                                                                     "task_1.json,task_2.json,task_3.json",
+		"/redfish/v1/TaskService/Tasks/JID_467762000001":                                                                     "task_4.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/":                                                                        "job_collection_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_467700000000":                                                        "job_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_467762000001":                                                        "job_2.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_467762674724":                                                        "job_3.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_467799000000":                                                        "job_4.json",
+		"POST /redfish/v1/Dell/Managers/iDRAC.Embedded.1/DellJobService/Actions/DellJobService.DeleteJobQueue":               "success_1.json",
+		"/redfish/v1/TaskService/Tasks/JID_467800000001":                                                                     "task_5.json,scp_1.xml",
+		"/redfish/v1/TaskService/Tasks/JID_467800000002":                                                                     "task_6.json,task_7.json",
+		"/redfish/v1/TaskService/Tasks/JID_467800000003":                                                                     "task_8.json",
+		"POST /redfish/v1/Managers/iDRAC.Embedded.1/Actions/Oem/EID_674_Manager.ExportSystemConfiguration":                   "task_5.json",
+		"POST /redfish/v1/Managers/iDRAC.Embedded.1/Actions/Oem/EID_674_Manager.ImportSystemConfiguration":                   "task_6.json",
+		"POST /redfish/v1/Managers/iDRAC.Embedded.1/Actions/Oem/EID_674_Manager.ImportSystemConfigurationPreview":            "task_8.json",
+		"/redfish/v1/Systems/System.Embedded.1/Bios":                                                                         "bios_1.json",
+		"/redfish/v1/Systems/System.Embedded.1/Bios/Settings":                                                                "bios_settings_1.json",
+		"PATCH /redfish/v1/Systems/System.Embedded.1/Bios/Settings":                                                          "task_9.json",
+		"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Integrated.1/NetworkDeviceFunctions/NIC.Integrated.1-1-1/Oem/Dell/DellNetworkAttributes/NIC.Integrated.1-1-1": "nic_attributes_1.json",
+		"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Integrated.1/NetworkDeviceFunctions/NIC.Integrated.1-1-1/Oem/Dell/DellNetworkAttributes/NIC.Integrated.1-1-1/Settings": "nic_settings_1.json",
+		"PATCH /redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Integrated.1/NetworkDeviceFunctions/NIC.Integrated.1-1-1/Oem/Dell/DellNetworkAttributes/NIC.Integrated.1-1-1/Settings": "task_10.json",
+		"/redfish/v1/TaskService/Tasks/JID_467799000000":                                                                     "task_9.json",
+		"/redfish/v1/TaskService/Tasks/JID_467799000001":                                                                     "task_10.json",
+		"/redfish/v1/Managers/System.Embedded.1/Attributes":                                                                  "system_attributes_1.json",
+		"/redfish/v1/Managers/LifecycleController.Embedded.1/Attributes":                                                     "lc_attributes_1.json",
+		"/redfish/v1/Registries/ManagerAttributeRegistry":                                                                    "registry_file_manager_attributes_1.json",
+		"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json":                               "attribute_registry_manager_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/NetworkProtocol":                                                              "manager_network_protocol_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/EthernetInterfaces/":                                                          "manager_ethernet_interface_collection_1.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/EthernetInterfaces/NIC.1":                                                     "manager_ethernet_interface_1.json",
 	}
 
 	if pathMap != nil {
@@ -93,7 +215,8 @@ func NewMockTestServer(pathMap map[string]string, tlsEnabled bool) (*MockTestSer
 			authHeader = strings.TrimLeft(authHeader, "Basic")
 			authHeader = strings.TrimSpace(authHeader)
 			if b, err := base64.StdEncoding.DecodeString(authHeader); err == nil {
-				if string(b) == "admin:secret" {
+				creds := strings.SplitN(string(b), ":", 2)
+				if len(creds) == 2 && mts.isAuthorized(creds[0], creds[1]) {
 					isAuthError = false
 				}
 			}
@@ -110,8 +233,94 @@ func NewMockTestServer(pathMap map[string]string, tlsEnabled bool) (*MockTestSer
 			return
 		}
 
+		lookupEndpoint := func(k string) (string, bool) {
+			v, exists := serverEndpoints[k]
+			if !exists {
+				// The members of collections reference resources without
+				// trailing slash.
+				if strings.HasSuffix(k, "/") {
+					v, exists = serverEndpoints[strings.TrimSuffix(k, "/")]
+				} else {
+					v, exists = serverEndpoints[k+"/"]
+				}
+			}
+			return v, exists
+		}
+
+		if req.URL.Path == "/redfish/v1/SSE" {
+			// The event stream sends one event per connection. The id of
+			// the event follows the Last-Event-ID of the request, and the
+			// Context of the event is the $filter of the request.
+			lastEventID, _ := strconv.Atoi(req.Header.Get("Last-Event-ID"))
+			eventID := strconv.Itoa(lastEventID + 1)
+			event := make(map[string]interface{})
+			fc, _ = ioutil.ReadFile(fmt.Sprintf("%s/event_1.json", dataDir))
+			json.Unmarshal(fc, &event)
+			event["Id"] = eventID
+			event["Context"] = req.URL.Query().Get("$filter")
+			fc, _ = json.Marshal(event)
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "retry: 10\n: keep-alive\nid: %s\ndata: %s\n\n", eventID, fc)
+			return
+		}
+
 		if req.Method != "GET" {
-			http.Error(w, "Bad Request, expecting GET", http.StatusBadRequest)
+			if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+				// The uploads must have a file.
+				if err := req.ParseMultipartForm(32 << 20); err != nil || len(req.MultipartForm.File) == 0 {
+					http.Error(w, "Bad Request, expecting multipart upload with a file", http.StatusBadRequest)
+					return
+				}
+			}
+			if respFileName, exists := lookupEndpoint(req.Method + " " + req.URL.Path); exists {
+				fp = fmt.Sprintf("%s/%s", dataDir, respFileName)
+				fc, err = ioutil.ReadFile(fp)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				// The POST requests creating resources respond with the
+				// resource and its location, while the PATCH requests of
+				// settings respond with the location of the configuration job.
+				resource := make(map[string]interface{})
+				json.Unmarshal(fc, &resource)
+				if location, ok := resource["@odata.id"].(string); ok {
+					w.Header().Set("Location", location)
+					switch req.Method {
+					case "POST":
+						w.WriteHeader(http.StatusCreated)
+					case "PATCH":
+						w.WriteHeader(http.StatusAccepted)
+					}
+				}
+				w.Write(fc)
+				return
+			}
+			respFileName, exists := lookupEndpoint(req.URL.Path)
+			if req.Method != "PATCH" || !exists {
+				http.Error(w, "Bad Request, expecting GET", http.StatusBadRequest)
+				return
+			}
+			body, _ := ioutil.ReadAll(req.Body)
+			patch := make(map[string]interface{})
+			if err := json.Unmarshal(body, &patch); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if password, ok := patch["Password"].(string); ok {
+				resource := make(map[string]interface{})
+				fc, _ = ioutil.ReadFile(fmt.Sprintf("%s/%s", dataDir, respFileName))
+				json.Unmarshal(fc, &resource)
+				if username, ok := resource["UserName"].(string); ok && username != "" {
+					mts.SetUser(username, password)
+				}
+			}
+			fc, err = ioutil.ReadFile(fmt.Sprintf("%s/success_1.json", dataDir))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Write(fc)
 			return
 		}
 
@@ -125,7 +334,13 @@ func NewMockTestServer(pathMap map[string]string, tlsEnabled bool) (*MockTestSer
 			return
 		}
 
-		respFileName, respFileExists := serverEndpoints[req.URL.Path]
+		respFileName, respFileExists := lookupEndpoint(req.URL.Path)
+		if req.URL.RawQuery != "" {
+			// The pages of collections have query strings, e.g. $skip=50.
+			if v, exists := serverEndpoints[req.URL.Path+"?"+req.URL.RawQuery]; exists {
+				respFileName, respFileExists = v, true
+			}
+		}
 		if !respFileExists {
 			fp = fmt.Sprintf("%s/not_found_error_1.json", dataDir)
 			fc, err = ioutil.ReadFile(fp)
@@ -137,6 +352,7 @@ func NewMockTestServer(pathMap map[string]string, tlsEnabled bool) (*MockTestSer
 			return
 		}
 
+		respFileName = mts.nextResponse(req.URL.Path, respFileName)
 		fp = fmt.Sprintf("%s/%s", dataDir, respFileName)
 		fc, err = ioutil.ReadFile(fp)
 		if err != nil {
@@ -0,0 +1,267 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type accountResponse struct {
+	ODataAnnotation
+	ID                     string `json:"Id"`
+	Name                   string
+	Description            string
+	UserName               string
+	RoleID                 string `json:"RoleId"`
+	Enabled                bool
+	Locked                 bool
+	PasswordChangeRequired bool
+	AccountTypes           []string
+	AccountTypesCounter    uint64 `json:"AccountTypes@odata.count"`
+	OEMAccountTypes        []string
+	Links                  struct {
+		Role ODataAnnotation
+	}
+}
+
+// Account represents an instance of Redfish ManagerAccount resource, i.e.
+// a user account of iDRAC.
+type Account struct {
+	ID                     string           `yaml:"id" json:"id" xml:"id"`
+	OData                  *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name                   string           `yaml:"name" json:"name" xml:"name"`
+	Description            string           `yaml:"description" json:"description" xml:"description"`
+	UserName               string           `yaml:"user_name" json:"user_name" xml:"user_name"`
+	RoleID                 string           `yaml:"role_id" json:"role_id" xml:"role_id"`
+	Enabled                bool             `yaml:"enabled" json:"enabled" xml:"enabled"`
+	Locked                 bool             `yaml:"locked" json:"locked" xml:"locked"`
+	PasswordChangeRequired bool             `yaml:"password_change_required" json:"password_change_required" xml:"password_change_required"`
+	AccountTypes           []string         `yaml:"account_types" json:"account_types" xml:"account_types"`
+	OEMAccountTypes        []string         `yaml:"oem_account_types" json:"oem_account_types" xml:"oem_account_types"`
+}
+
+// AccountChanges holds the changes of an account. The nil fields remain
+// unchanged. The account lock may only be removed, i.e. Locked set to false.
+type AccountChanges struct {
+	UserName *string `yaml:"user_name" json:"user_name" xml:"user_name"`
+	Password *string `yaml:"-" json:"-" xml:"-"`
+	RoleID   *string `yaml:"role_id" json:"role_id" xml:"role_id"`
+	Enabled  *bool   `yaml:"enabled" json:"enabled" xml:"enabled"`
+	Locked   *bool   `yaml:"locked" json:"locked" xml:"locked"`
+}
+
+// reservedAccountSlot is the account slot iDRAC reserves, i.e. it cannot
+// be configured.
+const reservedAccountSlot = "1"
+
+// GetAccount returns an instance of Redfish ManagerAccount resource, e.g. 2.
+func (cli *Client) GetAccount(accountID string) (*Account, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"AccountService/Accounts/"+accountID, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newAccountFromBytes(resp)
+}
+
+// getAccountSlots returns all members of the account collection. iDRAC has
+// a fixed number of account slots, and the empty slots have no UserName.
+func (cli *Client) getAccountSlots() ([]*Account, error) {
+	members, err := cli.getCollectionMembers(cli.rootPath + "AccountService/Accounts/")
+	if err != nil {
+		return nil, err
+	}
+	accounts := []*Account{}
+	for _, member := range members {
+		resp, err := cli.callAPI("GET", "", member, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		account, err := newAccountFromBytes(resp)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// ListAccounts returns the configured accounts, i.e. the account slots
+// with a UserName.
+func (cli *Client) ListAccounts() ([]*Account, error) {
+	slots, err := cli.getAccountSlots()
+	if err != nil {
+		return nil, err
+	}
+	accounts := []*Account{}
+	for _, account := range slots {
+		if account.UserName != "" {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
+}
+
+// GetAccountByUserName returns the account with the UserName.
+func (cli *Client) GetAccountByUserName(userName string) (*Account, error) {
+	if userName == "" {
+		return nil, fmt.Errorf("empty username")
+	}
+	accounts, err := cli.ListAccounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		if account.UserName == userName {
+			return account, nil
+		}
+	}
+	return nil, fmt.Errorf("account %s not found", userName)
+}
+
+// CreateAccount creates an enabled account with the role, e.g. Operator.
+// iDRAC has fixed account slots, i.e. the function configures the first
+// empty slot rather than adding a member to the collection.
+func (cli *Client) CreateAccount(userName, password, roleID string) (*Account, error) {
+	if userName == "" {
+		return nil, fmt.Errorf("empty username")
+	}
+	if password == "" {
+		return nil, fmt.Errorf("empty password")
+	}
+	if roleID == "" {
+		return nil, fmt.Errorf("empty role")
+	}
+	slots, err := cli.getAccountSlots()
+	if err != nil {
+		return nil, err
+	}
+	var slot *Account
+	for _, account := range slots {
+		if account.UserName == userName {
+			return nil, fmt.Errorf("account %s already exists in slot %s", userName, account.ID)
+		}
+		if slot == nil && account.UserName == "" && account.ID != reservedAccountSlot {
+			slot = account
+		}
+	}
+	if slot == nil {
+		return nil, fmt.Errorf("no empty account slots")
+	}
+	enabled := true
+	if err := cli.UpdateAccount(slot.ID, &AccountChanges{
+		UserName: &userName,
+		Password: &password,
+		RoleID:   &roleID,
+		Enabled:  &enabled,
+	}); err != nil {
+		return nil, err
+	}
+	return cli.GetAccount(slot.ID)
+}
+
+// UpdateAccount changes the settings of an account.
+func (cli *Client) UpdateAccount(accountID string, changes *AccountChanges) error {
+	if accountID == reservedAccountSlot {
+		return fmt.Errorf("account slot %s is reserved", accountID)
+	}
+	properties := make(map[string]interface{})
+	if changes.UserName != nil {
+		properties["UserName"] = *changes.UserName
+	}
+	if changes.Password != nil {
+		if *changes.Password == "" {
+			return fmt.Errorf("empty password")
+		}
+		properties["Password"] = *changes.Password
+	}
+	if changes.RoleID != nil {
+		properties["RoleId"] = *changes.RoleID
+	}
+	if changes.Enabled != nil {
+		properties["Enabled"] = *changes.Enabled
+	}
+	if changes.Locked != nil {
+		if *changes.Locked {
+			return fmt.Errorf("accounts can only be unlocked")
+		}
+		properties["Locked"] = false
+	}
+	if len(properties) == 0 {
+		return fmt.Errorf("no account changes")
+	}
+	_, err := cli.patchResource(cli.rootPath+"AccountService/Accounts/"+accountID, properties)
+	return err
+}
+
+// DisableAccount disables an account, while keeping its settings.
+func (cli *Client) DisableAccount(accountID string) error {
+	enabled := false
+	return cli.UpdateAccount(accountID, &AccountChanges{Enabled: &enabled})
+}
+
+// DeleteAccount deletes an account. iDRAC has fixed account slots, i.e.
+// the function disables the account and clears its slot.
+func (cli *Client) DeleteAccount(accountID string) error {
+	account, err := cli.GetAccount(accountID)
+	if err != nil {
+		return err
+	}
+	if account.UserName == "" {
+		return fmt.Errorf("account slot %s is empty", accountID)
+	}
+	userName := ""
+	roleID := "None"
+	enabled := false
+	return cli.UpdateAccount(accountID, &AccountChanges{
+		UserName: &userName,
+		RoleID:   &roleID,
+		Enabled:  &enabled,
+	})
+}
+
+// SetAccountPassword changes the password of an account.
+func (cli *Client) SetAccountPassword(accountID, password string) error {
+	return cli.UpdateAccount(accountID, &AccountChanges{Password: &password})
+}
+
+// newAccountFromString returns Account instance from an input string.
+func newAccountFromString(s string) (*Account, error) {
+	return newAccountFromBytes([]byte(s))
+}
+
+// newAccountFromBytes returns Account instance from an input byte array.
+func newAccountFromBytes(s []byte) (*Account, error) {
+	response := &accountResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the account is empty, server response: %s", string(s[:]))
+	}
+	a := &Account{
+		ID:                     response.ID,
+		Name:                   response.Name,
+		Description:            response.Description,
+		UserName:               response.UserName,
+		RoleID:                 response.RoleID,
+		Enabled:                response.Enabled,
+		Locked:                 response.Locked,
+		PasswordChangeRequired: response.PasswordChangeRequired,
+		AccountTypes:           response.AccountTypes,
+		OEMAccountTypes:        response.OEMAccountTypes,
+	}
+	a.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	if a.AccountTypes == nil {
+		a.AccountTypes = []string{}
+	}
+	if a.OEMAccountTypes == nil {
+		a.OEMAccountTypes = []string{}
+	}
+	return a, nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type accountServiceResponse struct {
+	ODataAnnotation
+	ID                              string `json:"Id"`
+	Name                            string
+	Description                     string
+	ServiceEnabled                  bool
+	LocalAccountAuth                string
+	AuthFailureLoggingThreshold     uint64
+	AccountLockoutThreshold         uint64
+	AccountLockoutDuration          uint64
+	AccountLockoutCounterResetAfter uint64
+	MinPasswordLength               uint64
+	MaxPasswordLength               uint64
+	Status                          HealthStatus
+}
+
+// AccountService represents an instance of Redfish AccountService resource,
+// i.e. the account lockout and password policy of iDRAC.
+type AccountService struct {
+	ID                              string           `yaml:"id" json:"id" xml:"id"`
+	OData                           *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name                            string           `yaml:"name" json:"name" xml:"name"`
+	Description                     string           `yaml:"description" json:"description" xml:"description"`
+	ServiceEnabled                  bool             `yaml:"service_enabled" json:"service_enabled" xml:"service_enabled"`
+	LocalAccountAuth                string           `yaml:"local_account_auth" json:"local_account_auth" xml:"local_account_auth"`
+	AuthFailureLoggingThreshold     uint64           `yaml:"auth_failure_logging_threshold" json:"auth_failure_logging_threshold" xml:"auth_failure_logging_threshold"`
+	AccountLockoutThreshold         uint64           `yaml:"account_lockout_threshold" json:"account_lockout_threshold" xml:"account_lockout_threshold"`
+	AccountLockoutDuration          uint64           `yaml:"account_lockout_duration" json:"account_lockout_duration" xml:"account_lockout_duration"`
+	AccountLockoutCounterResetAfter uint64           `yaml:"account_lockout_counter_reset_after" json:"account_lockout_counter_reset_after" xml:"account_lockout_counter_reset_after"`
+	MinPasswordLength               uint64           `yaml:"min_password_length" json:"min_password_length" xml:"min_password_length"`
+	MaxPasswordLength               uint64           `yaml:"max_password_length" json:"max_password_length" xml:"max_password_length"`
+	Status                          HealthStatus     `yaml:"status" json:"status" xml:"status"`
+}
+
+// AccountPolicy holds the changes of the account lockout and password
+// policy. The nil fields remain unchanged. The durations are in seconds.
+type AccountPolicy struct {
+	AuthFailureLoggingThreshold     *uint64 `yaml:"auth_failure_logging_threshold" json:"auth_failure_logging_threshold" xml:"auth_failure_logging_threshold"`
+	AccountLockoutThreshold         *uint64 `yaml:"account_lockout_threshold" json:"account_lockout_threshold" xml:"account_lockout_threshold"`
+	AccountLockoutDuration          *uint64 `yaml:"account_lockout_duration" json:"account_lockout_duration" xml:"account_lockout_duration"`
+	AccountLockoutCounterResetAfter *uint64 `yaml:"account_lockout_counter_reset_after" json:"account_lockout_counter_reset_after" xml:"account_lockout_counter_reset_after"`
+	MinPasswordLength               *uint64 `yaml:"min_password_length" json:"min_password_length" xml:"min_password_length"`
+	MaxPasswordLength               *uint64 `yaml:"max_password_length" json:"max_password_length" xml:"max_password_length"`
+}
+
+type roleResponse struct {
+	ODataAnnotation
+	ID                 string `json:"Id"`
+	Name               string
+	Description        string
+	RoleID             string `json:"RoleId"`
+	IsPredefined       bool
+	AssignedPrivileges []string
+	OemPrivileges      []string
+}
+
+// Role represents an instance of Redfish Role resource, i.e. the
+// privileges of the accounts with the role.
+type Role struct {
+	ID                 string           `yaml:"id" json:"id" xml:"id"`
+	OData              *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name               string           `yaml:"name" json:"name" xml:"name"`
+	Description        string           `yaml:"description" json:"description" xml:"description"`
+	IsPredefined       bool             `yaml:"is_predefined" json:"is_predefined" xml:"is_predefined"`
+	AssignedPrivileges []string         `yaml:"assigned_privileges" json:"assigned_privileges" xml:"assigned_privileges"`
+	OemPrivileges      []string         `yaml:"oem_privileges" json:"oem_privileges" xml:"oem_privileges"`
+}
+
+// GetAccountService returns an instance of Redfish AccountService resource.
+func (cli *Client) GetAccountService() (*AccountService, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"AccountService", []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newAccountServiceFromBytes(resp)
+}
+
+// UpdateAccountPolicy changes the account lockout and password policy.
+func (cli *Client) UpdateAccountPolicy(policy *AccountPolicy) error {
+	properties := make(map[string]interface{})
+	for k, v := range map[string]*uint64{
+		"AuthFailureLoggingThreshold":     policy.AuthFailureLoggingThreshold,
+		"AccountLockoutThreshold":         policy.AccountLockoutThreshold,
+		"AccountLockoutDuration":          policy.AccountLockoutDuration,
+		"AccountLockoutCounterResetAfter": policy.AccountLockoutCounterResetAfter,
+		"MinPasswordLength":               policy.MinPasswordLength,
+		"MaxPasswordLength":               policy.MaxPasswordLength,
+	} {
+		if v != nil {
+			properties[k] = *v
+		}
+	}
+	if len(properties) == 0 {
+		return fmt.Errorf("no account policy changes")
+	}
+	if policy.MinPasswordLength != nil && policy.MaxPasswordLength != nil &&
+		*policy.MinPasswordLength > *policy.MaxPasswordLength {
+		return fmt.Errorf("minimum password length %d exceeds maximum password length %d",
+			*policy.MinPasswordLength, *policy.MaxPasswordLength)
+	}
+	_, err := cli.patchResource(cli.rootPath+"AccountService", properties)
+	return err
+}
+
+// ListRoles returns the roles of the accounts.
+func (cli *Client) ListRoles() ([]*Role, error) {
+	members, err := cli.getCollectionMembers(cli.rootPath + "AccountService/Roles")
+	if err != nil {
+		return nil, err
+	}
+	roles := []*Role{}
+	for _, member := range members {
+		resp, err := cli.callAPI("GET", "", member, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		role, err := newRoleFromBytes(resp)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// newAccountServiceFromString returns AccountService instance from an input string.
+func newAccountServiceFromString(s string) (*AccountService, error) {
+	return newAccountServiceFromBytes([]byte(s))
+}
+
+// newAccountServiceFromBytes returns AccountService instance from an input byte array.
+func newAccountServiceFromBytes(s []byte) (*AccountService, error) {
+	response := &accountServiceResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the account service is empty, server response: %s", string(s[:]))
+	}
+	svc := &AccountService{
+		ID:                              response.ID,
+		Name:                            response.Name,
+		Description:                     response.Description,
+		ServiceEnabled:                  response.ServiceEnabled,
+		LocalAccountAuth:                response.LocalAccountAuth,
+		AuthFailureLoggingThreshold:     response.AuthFailureLoggingThreshold,
+		AccountLockoutThreshold:         response.AccountLockoutThreshold,
+		AccountLockoutDuration:          response.AccountLockoutDuration,
+		AccountLockoutCounterResetAfter: response.AccountLockoutCounterResetAfter,
+		MinPasswordLength:               response.MinPasswordLength,
+		MaxPasswordLength:               response.MaxPasswordLength,
+		Status:                          response.Status,
+	}
+	svc.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return svc, nil
+}
+
+// newRoleFromBytes returns Role instance from an input byte array.
+func newRoleFromBytes(s []byte) (*Role, error) {
+	response := &roleResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	role := &Role{
+		ID:                 response.ID,
+		Name:               response.Name,
+		Description:        response.Description,
+		IsPredefined:       response.IsPredefined,
+		AssignedPrivileges: response.AssignedPrivileges,
+		OemPrivileges:      response.OemPrivileges,
+	}
+	role.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	if role.AssignedPrivileges == nil {
+		role.AssignedPrivileges = []string{}
+	}
+	if role.OemPrivileges == nil {
+		role.OemPrivileges = []string{}
+	}
+	return role, nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"fmt"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseAccountServiceJsonOutput(t *testing.T) {
+	testFailed := 0
+	dataDir := "../../assets/responses"
+	for i, test := range []struct {
+		input      string
+		exp        *AccountService
+		shouldFail bool // Whether test should result in a failure
+		shouldErr  bool // Whether parsing of a response should result in error
+	}{
+		{
+			input: "account_service_1",
+			exp: &AccountService{
+				ID: "AccountService",
+				OData: NewODataAnnotation(
+					"/redfish/v1/AccountService",
+					"#AccountService.v1_5_0.AccountService",
+					"/redfish/v1/$metadata#AccountService.AccountService",
+				),
+				Name:                            "Account Service",
+				Description:                     "BMC User Accounts",
+				ServiceEnabled:                  true,
+				LocalAccountAuth:                "Fallback",
+				AuthFailureLoggingThreshold:     3,
+				AccountLockoutThreshold:         5,
+				AccountLockoutDuration:          300,
+				AccountLockoutCounterResetAfter: 300,
+				MinPasswordLength:               0,
+				MaxPasswordLength:               40,
+				Status: HealthStatus{
+					Health: "OK",
+					State:  "Enabled",
+				},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			input:      "root_2",
+			shouldFail: false,
+			shouldErr:  true,
+		},
+	} {
+		// Read response file
+		fp := fmt.Sprintf("%s/%s.json", dataDir, test.input)
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			t.Logf("FAIL: Test %d: failed reading '%s', error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+
+		// Parse API response
+		svc, err := newAccountServiceFromBytes(content)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input '%s', expected to pass, but threw error: %v", i, fp, err)
+				testFailed++
+			} else {
+				t.Logf("PASS: Test %d: input '%s', expected to throw error, threw error: %v", i, fp, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input '%s', expected to throw error, but passed: %v", i, fp, *svc)
+			testFailed++
+			continue
+		}
+
+		svcFromString, err := newAccountServiceFromString(string(content))
+		if err != nil {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(svcFromString, svc) {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (newAccountServiceFromString) vs. '%v' (newAccountServiceFromBytes)",
+				i, fp, *svcFromString, *svc)
+			testFailed++
+			continue
+		}
+
+		if !reflect.DeepEqual(svc, test.exp) && !test.shouldFail {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (actual) vs. '%v' (expected)",
+				i, fp, *svc, *test.exp)
+			testFailed++
+			continue
+		}
+
+		for _, resource := range []interface{}{svc, &AccountPolicy{}} {
+			complianceMessages, compliant := isStructCompliant(resource)
+			if !compliant {
+				testFailed++
+				for _, entry := range complianceMessages {
+					t.Logf("%s", entry)
+				}
+			}
+		}
+
+		t.Logf("PASS: Test %d: input '%s', expected to pass, passed", i, fp)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestAccountPolicyAndRoles(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	svc, err := cli.GetAccountService()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if svc.AccountLockoutThreshold != 5 {
+		t.Fatalf("client: unexpected account lockout threshold: %d", svc.AccountLockoutThreshold)
+	}
+
+	threshold := uint64(3)
+	minLength, maxLength := uint64(12), uint64(40)
+	if err := cli.UpdateAccountPolicy(&AccountPolicy{
+		AccountLockoutThreshold: &threshold,
+		MinPasswordLength:       &minLength,
+		MaxPasswordLength:       &maxLength,
+	}); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.UpdateAccountPolicy(&AccountPolicy{}); err == nil {
+		t.Fatalf("client: expected failure due to no changes, but got non-error response")
+	}
+	if err := cli.UpdateAccountPolicy(&AccountPolicy{MinPasswordLength: &maxLength, MaxPasswordLength: &minLength}); err == nil {
+		t.Fatalf("client: expected failure due to invalid password lengths, but got non-error response")
+	}
+
+	roles, err := cli.ListRoles()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(roles) != 3 || roles[0].ID != "Administrator" || len(roles[2].OemPrivileges) != 0 {
+		t.Fatalf("client: unexpected roles: %v", roles)
+	}
+	for _, role := range roles {
+		complianceMessages, compliant := isStructCompliant(role)
+		if !compliant {
+			t.Fatalf("client: role is not compliant: %v", complianceMessages)
+		}
+	}
+}
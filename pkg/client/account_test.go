@@ -0,0 +1,202 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"fmt"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseAccountJsonOutput(t *testing.T) {
+	testFailed := 0
+	dataDir := "../../assets/responses"
+	for i, test := range []struct {
+		input      string
+		exp        *Account
+		shouldFail bool // Whether test should result in a failure
+		shouldErr  bool // Whether parsing of a response should result in error
+	}{
+		{
+			input: "account_2",
+			exp: &Account{
+				ID: "2",
+				OData: NewODataAnnotation(
+					"/redfish/v1/AccountService/Accounts/2",
+					"#ManagerAccount.v1_4_0.ManagerAccount",
+					"/redfish/v1/$metadata#ManagerAccount.ManagerAccount",
+				),
+				Name:            "User Account",
+				Description:     "User Account",
+				UserName:        "admin",
+				RoleID:          "Administrator",
+				Enabled:         true,
+				AccountTypes:    []string{"Redfish", "SNMP", "OEM"},
+				OEMAccountTypes: []string{"IPMI", "SOL", "WSMAN", "UI", "RACADM"},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			input:      "root_2",
+			shouldFail: false,
+			shouldErr:  true,
+		},
+	} {
+		// Read response file
+		fp := fmt.Sprintf("%s/%s.json", dataDir, test.input)
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			t.Logf("FAIL: Test %d: failed reading '%s', error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+
+		// Parse API response
+		account, err := newAccountFromBytes(content)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input '%s', expected to pass, but threw error: %v", i, fp, err)
+				testFailed++
+			} else {
+				t.Logf("PASS: Test %d: input '%s', expected to throw error, threw error: %v", i, fp, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input '%s', expected to throw error, but passed: %v", i, fp, *account)
+			testFailed++
+			continue
+		}
+
+		accountFromString, err := newAccountFromString(string(content))
+		if err != nil {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(accountFromString, account) {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (newAccountFromString) vs. '%v' (newAccountFromBytes)",
+				i, fp, *accountFromString, *account)
+			testFailed++
+			continue
+		}
+
+		if !reflect.DeepEqual(account, test.exp) && !test.shouldFail {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (actual) vs. '%v' (expected)",
+				i, fp, *account, *test.exp)
+			testFailed++
+			continue
+		}
+
+		complianceMessages, compliant := isStructCompliant(account)
+		if !compliant {
+			testFailed++
+			for _, entry := range complianceMessages {
+				t.Logf("%s", entry)
+			}
+			continue
+		}
+
+		t.Logf("PASS: Test %d: input '%s', expected to pass, passed", i, fp)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestGetAccountByUserName(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	account, err := cli.GetAccountByUserName("operator")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if account.ID != "3" || account.RoleID != "Operator" {
+		t.Fatalf("client: unexpected account: %+v", *account)
+	}
+	if _, err := cli.GetAccountByUserName("nobody"); err == nil {
+		t.Fatalf("client: expected failure, but got non-error response")
+	}
+	if err := cli.SetAccountPassword("3", "newsecret"); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.SetAccountPassword("3", ""); err == nil {
+		t.Fatalf("client: expected failure due to empty password, but got non-error response")
+	}
+}
+
+func TestManageAccounts(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	accounts, err := cli.ListAccounts()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(accounts) != 2 {
+		t.Fatalf("client: expected 2 accounts, but got %d", len(accounts))
+	}
+
+	// The first empty slot, except the reserved one, holds new accounts.
+	account, err := cli.CreateAccount("jsmith", "jsmithsecret", "ReadOnly")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if account.ID != "4" {
+		t.Fatalf("client: expected account in slot 4, but got slot %s", account.ID)
+	}
+	if _, err := cli.CreateAccount("operator", "opsecret", "ReadOnly"); err == nil {
+		t.Fatalf("client: expected failure due to existing account, but got non-error response")
+	}
+	if _, err := cli.CreateAccount("jsmith", "", "ReadOnly"); err == nil {
+		t.Fatalf("client: expected failure due to empty password, but got non-error response")
+	}
+
+	roleID := "Operator"
+	unlocked := false
+	if err := cli.UpdateAccount("3", &AccountChanges{RoleID: &roleID, Locked: &unlocked}); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	locked := true
+	for _, changes := range []*AccountChanges{{}, {Locked: &locked}} {
+		if err := cli.UpdateAccount("3", changes); err == nil {
+			t.Fatalf("client: expected failure, but got non-error response")
+		}
+	}
+	if err := cli.UpdateAccount("1", &AccountChanges{RoleID: &roleID}); err == nil {
+		t.Fatalf("client: expected failure due to reserved slot, but got non-error response")
+	}
+	if err := cli.DisableAccount("3"); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.DeleteAccount("3"); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.DeleteAccount("4"); err == nil {
+		t.Fatalf("client: expected failure due to empty slot, but got non-error response")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// APIError is an error response of Redfish API, i.e. a status code other
+// than success, and the messages of the error.
+type APIError struct {
+	StatusCode int
+	// Code is the message id of the error, e.g. Base.1.5.GeneralError.
+	Code string
+	// Messages are the details of the error, i.e. @Message.ExtendedInfo.
+	Messages []*Message
+	Body     []byte
+}
+
+// Error returns the status code and the response of the error.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error: status code %d: %s", e.StatusCode, string(e.Body))
+}
+
+// newAPIError returns APIError instance from the status code and the
+// response of an error.
+func newAPIError(statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Messages:   []*Message{},
+		Body:       body,
+	}
+	response := &struct {
+		Error struct {
+			Code         string `json:"code"`
+			ExtendedInfo []struct {
+				MessageID   string `json:"MessageId"`
+				Message     string
+				MessageArgs []string
+				Severity    string
+				Resolution  string
+			} `json:"@Message.ExtendedInfo"`
+		} `json:"error"`
+	}{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return e
+	}
+	e.Code = response.Error.Code
+	for _, entry := range response.Error.ExtendedInfo {
+		e.Messages = append(e.Messages, &Message{
+			MessageID:   entry.MessageID,
+			Message:     entry.Message,
+			MessageArgs: nonNilStrings(entry.MessageArgs),
+			Severity:    entry.Severity,
+			Resolution:  entry.Resolution,
+		})
+	}
+	return e
+}
+
+// ResolveError sets the message, the severity, and the resolution of the
+// messages of an APIError, when they are empty. The other errors remain
+// unchanged.
+func (r *MessageRegistries) ResolveError(err error) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return
+	}
+	for _, m := range apiErr.Messages {
+		r.ResolveMessage(m)
+	}
+}
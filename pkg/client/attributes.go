@@ -0,0 +1,432 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// managerAttributesPath is the path of the OEM attributes of iDRAC.
+const managerAttributesPath = "Managers/iDRAC.Embedded.1/Attributes"
+
+// The attribute sets of iDRAC, i.e. the OEM attributes of iDRAC, of the
+// computer system, and of Lifecycle Controller. The manager attribute
+// registry describes the attributes of all the sets.
+const (
+	AttributeSetIDRAC               = "iDRAC.Embedded.1"
+	AttributeSetSystem              = "System.Embedded.1"
+	AttributeSetLifecycleController = "LifecycleController.Embedded.1"
+)
+
+// MaskedValue replaces the values of the Password attributes in the output
+// and the errors.
+const MaskedValue = "********"
+
+var attributeSets = []string{AttributeSetIDRAC, AttributeSetSystem, AttributeSetLifecycleController}
+
+type attributesResponse struct {
+	ODataAnnotation
+	ID                string `json:"Id"`
+	AttributeRegistry string
+	Attributes        map[string]interface{}
+}
+
+type attributeRegistryResponse struct {
+	ODataAnnotation
+	ID              string `json:"Id"`
+	Language        string
+	RegistryVersion string
+	OwningEntity    string
+	RegistryEntries struct {
+		Attributes []struct {
+			ID            string `json:"Id"`
+			AttributeName string
+			DisplayName   string
+			HelpText      string
+			Type          string
+			ReadOnly      bool
+			Value         []struct {
+				ValueName        string
+				ValueDisplayName string
+			}
+			LowerBound      *int64
+			UpperBound      *int64
+			MinLength       *int64
+			MaxLength       *int64
+			ValueExpression string
+		}
+	}
+}
+
+// AttributeSet is the OEM attributes of a resource, e.g. iDRAC, keyed by
+// dotted names, e.g. NTPConfigGroup.1.NTPEnable, along with the id of the
+// attribute registry describing the attributes.
+type AttributeSet struct {
+	ID                string                 `yaml:"id" json:"id" xml:"id"`
+	AttributeRegistry string                 `yaml:"attribute_registry" json:"attribute_registry" xml:"attribute_registry"`
+	Attributes        map[string]interface{} `yaml:"attributes" json:"attributes" xml:"-"`
+}
+
+// AttributeRegistry represents an instance of Redfish AttributeRegistry,
+// i.e. the types and the allowed values of the attributes of the
+// attribute sets.
+type AttributeRegistry struct {
+	ID              string                 `yaml:"id" json:"id" xml:"id"`
+	Language        string                 `yaml:"language" json:"language" xml:"language"`
+	RegistryVersion string                 `yaml:"registry_version" json:"registry_version" xml:"registry_version"`
+	OwningEntity    string                 `yaml:"owning_entity" json:"owning_entity" xml:"owning_entity"`
+	Attributes      []*AttributeDefinition `yaml:"attributes" json:"attributes" xml:"attributes"`
+	index           map[string]*AttributeDefinition
+}
+
+// AttributeDefinition is an attribute of an attribute registry. The type
+// is either Enumeration, String, Integer, Boolean, or Password. The values
+// are the allowed values of an Enumeration attribute.
+type AttributeDefinition struct {
+	Set             string   `yaml:"set" json:"set" xml:"set"`
+	Name            string   `yaml:"name" json:"name" xml:"name"`
+	DisplayName     string   `yaml:"display_name" json:"display_name" xml:"display_name"`
+	HelpText        string   `yaml:"help_text" json:"help_text" xml:"help_text"`
+	Type            string   `yaml:"type" json:"type" xml:"type"`
+	ReadOnly        bool     `yaml:"read_only" json:"read_only" xml:"read_only"`
+	Values          []string `yaml:"values" json:"values" xml:"values"`
+	LowerBound      *int64   `yaml:"lower_bound" json:"lower_bound" xml:"lower_bound"`
+	UpperBound      *int64   `yaml:"upper_bound" json:"upper_bound" xml:"upper_bound"`
+	MinLength       *int64   `yaml:"min_length" json:"min_length" xml:"min_length"`
+	MaxLength       *int64   `yaml:"max_length" json:"max_length" xml:"max_length"`
+	ValueExpression string   `yaml:"value_expression" json:"value_expression" xml:"value_expression"`
+}
+
+// Attribute is the value of an attribute of an attribute set along with
+// its type in the attribute registry. The type of the attributes absent
+// from the registry is empty.
+type Attribute struct {
+	Set         string      `yaml:"set" json:"set" xml:"set"`
+	Name        string      `yaml:"name" json:"name" xml:"name"`
+	Value       interface{} `yaml:"value" json:"value" xml:"value"`
+	Type        string      `yaml:"type" json:"type" xml:"type"`
+	DisplayName string      `yaml:"display_name" json:"display_name" xml:"display_name"`
+	ReadOnly    bool        `yaml:"read_only" json:"read_only" xml:"read_only"`
+}
+
+// getAttributes returns the attributes of a resource, e.g. iDRAC attributes.
+func (cli *Client) getAttributes(urlPath string) (map[string]interface{}, error) {
+	resp, err := cli.callAPI("GET", "", urlPath, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	response := &attributesResponse{}
+	if err := json.Unmarshal(resp, response); err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+	}
+	if response.Attributes == nil {
+		return nil, fmt.Errorf("parsing error: no attributes found, server response: %s", string(resp[:]))
+	}
+	return response.Attributes, nil
+}
+
+// setAttributes changes the attributes of a resource.
+func (cli *Client) setAttributes(urlPath string, attributes map[string]interface{}) error {
+	_, err := cli.patchResource(urlPath, map[string]interface{}{
+		"Attributes": attributes,
+	})
+	return err
+}
+
+// validateAttributeSet returns an error when the attribute set is unknown.
+func validateAttributeSet(set string) error {
+	for _, s := range attributeSets {
+		if s == set {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported attribute set %s, expecting %s", set, strings.Join(attributeSets, ", "))
+}
+
+// GetAttributeSet returns the attributes of an attribute set, e.g.
+// iDRAC.Embedded.1 or LifecycleController.Embedded.1.
+func (cli *Client) GetAttributeSet(set string) (*AttributeSet, error) {
+	if err := validateAttributeSet(set); err != nil {
+		return nil, err
+	}
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"Managers/"+set+"/Attributes", []byte{})
+	if err != nil {
+		return nil, err
+	}
+	response := &attributesResponse{}
+	if err := json.Unmarshal(resp, response); err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+	}
+	if response.Attributes == nil {
+		return nil, fmt.Errorf("parsing error: no attributes found, server response: %s", string(resp[:]))
+	}
+	return &AttributeSet{
+		ID:                set,
+		AttributeRegistry: response.AttributeRegistry,
+		Attributes:        response.Attributes,
+	}, nil
+}
+
+// SetAttributeSet changes the attributes of an attribute set. The changes
+// apply immediately. The attributes are not validated, see
+// AttributeRegistry.Validate.
+func (cli *Client) SetAttributeSet(set string, attributes map[string]interface{}) error {
+	if err := validateAttributeSet(set); err != nil {
+		return err
+	}
+	if len(attributes) == 0 {
+		return fmt.Errorf("no attribute changes")
+	}
+	return cli.setAttributes(cli.rootPath+"Managers/"+set+"/Attributes", attributes)
+}
+
+// GetAttributeRegistry returns the attribute registry with the id, e.g.
+// ManagerAttributeRegistry.v1_0_0, i.e. the registry of an attribute set.
+func (cli *Client) GetAttributeRegistry(id string) (*AttributeRegistry, error) {
+	name := id
+	if i := strings.Index(id, ".v"); i > 0 {
+		name = id[:i]
+	}
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"Registries/"+name, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	file := &messageRegistryFileResponse{}
+	if err := json.Unmarshal(resp, file); err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp))
+	}
+	location := ""
+	for _, entry := range file.Location {
+		if entry.URI == "" {
+			continue
+		}
+		if location == "" || strings.EqualFold(entry.Language, "en") {
+			location = entry.URI
+		}
+	}
+	if location == "" {
+		return nil, fmt.Errorf("attribute registry %s of %s has no local copy", id, cli.host)
+	}
+	// The manager attribute registry exceeds ReceiverDataLimit.
+	fetcher := cli.Clone()
+	fetcher.dataLimit = MessageRegistryDataLimit
+	resp, err = fetcher.callAPI("GET", "", location, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newAttributeRegistryFromBytes(resp)
+}
+
+// newAttributeRegistryFromString returns AttributeRegistry instance from
+// an input string.
+func newAttributeRegistryFromString(s string) (*AttributeRegistry, error) {
+	return newAttributeRegistryFromBytes([]byte(s))
+}
+
+// newAttributeRegistryFromBytes returns AttributeRegistry instance from an
+// input byte array. The names of the attributes are the dotted names of the
+// ids, i.e. the id iDRAC.Embedded.1#NTPConfigGroup.1#NTPEnable is the
+// attribute NTPConfigGroup.1.NTPEnable of the iDRAC.Embedded.1 set.
+func newAttributeRegistryFromBytes(s []byte) (*AttributeRegistry, error) {
+	response := &attributeRegistryResponse{}
+	if err := json.Unmarshal(s, response); err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the attribute registry is empty, server response: %s", string(s[:]))
+	}
+	registry := &AttributeRegistry{
+		ID:              response.ID,
+		Language:        response.Language,
+		RegistryVersion: response.RegistryVersion,
+		OwningEntity:    response.OwningEntity,
+		Attributes:      []*AttributeDefinition{},
+		index:           make(map[string]*AttributeDefinition),
+	}
+	for _, entry := range response.RegistryEntries.Attributes {
+		attribute := &AttributeDefinition{
+			Name:            entry.AttributeName,
+			DisplayName:     entry.DisplayName,
+			HelpText:        entry.HelpText,
+			Type:            entry.Type,
+			ReadOnly:        entry.ReadOnly,
+			Values:          []string{},
+			LowerBound:      entry.LowerBound,
+			UpperBound:      entry.UpperBound,
+			MinLength:       entry.MinLength,
+			MaxLength:       entry.MaxLength,
+			ValueExpression: entry.ValueExpression,
+		}
+		if parts := strings.Split(entry.ID, "#"); len(parts) > 1 {
+			attribute.Set = parts[0]
+			attribute.Name = strings.Join(parts[1:], ".")
+		}
+		if attribute.Name == "" {
+			continue
+		}
+		for _, value := range entry.Value {
+			attribute.Values = append(attribute.Values, value.ValueName)
+		}
+		registry.Attributes = append(registry.Attributes, attribute)
+		registry.index[attribute.Set+"#"+attribute.Name] = attribute
+	}
+	return registry, nil
+}
+
+// Lookup returns the definition of an attribute of an attribute set, or nil
+// when the registry has no such attribute.
+func (r *AttributeRegistry) Lookup(set, name string) *AttributeDefinition {
+	if attribute, exists := r.index[set+"#"+name]; exists {
+		return attribute
+	}
+	return r.index["#"+name]
+}
+
+// IsPassword returns true when the attribute of an attribute set is a
+// Password attribute, i.e. its value is a secret. Without the definition of
+// the attribute, the attributes with names ending with Password are.
+func (r *AttributeRegistry) IsPassword(set, name string) bool {
+	if r != nil {
+		if definition := r.Lookup(set, name); definition != nil {
+			return definition.Type == "Password"
+		}
+	}
+	return strings.HasSuffix(name, "Password")
+}
+
+// Describe returns the attributes of an attribute set along with their
+// types, sorted by name. The values of the Password attributes are masked.
+func (r *AttributeRegistry) Describe(attributeSet *AttributeSet) []*Attribute {
+	attributes := []*Attribute{}
+	for name, value := range attributeSet.Attributes {
+		attribute := &Attribute{
+			Set:   attributeSet.ID,
+			Name:  name,
+			Value: value,
+		}
+		if r != nil {
+			if definition := r.Lookup(attributeSet.ID, name); definition != nil {
+				attribute.Type = definition.Type
+				attribute.DisplayName = definition.DisplayName
+				attribute.ReadOnly = definition.ReadOnly
+			}
+		}
+		if value != nil && value != "" && r.IsPassword(attributeSet.ID, name) {
+			attribute.Value = MaskedValue
+		}
+		attributes = append(attributes, attribute)
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Name < attributes[j].Name
+	})
+	return attributes
+}
+
+// Validate returns the attributes of an attribute set converted to the
+// types of the registry, e.g. the string "60" of an Integer attribute to
+// the number 60, and an error when an attribute is unknown, read-only, or
+// its value is not allowed.
+func (r *AttributeRegistry) Validate(set string, attributes map[string]interface{}) (map[string]interface{}, error) {
+	names := []string{}
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	validated := make(map[string]interface{})
+	for _, name := range names {
+		definition := r.Lookup(set, name)
+		if definition == nil {
+			return nil, fmt.Errorf("attribute %s of %s not found in attribute registry %s", name, set, r.ID)
+		}
+		if definition.ReadOnly {
+			return nil, fmt.Errorf("attribute %s of %s is read-only", name, set)
+		}
+		value, err := definition.convert(attributes[name])
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s of %s: %s", name, set, err)
+		}
+		validated[name] = value
+	}
+	return validated, nil
+}
+
+// convert returns the value converted to the type of the attribute, or an
+// error when the value is not allowed.
+func (a *AttributeDefinition) convert(v interface{}) (interface{}, error) {
+	switch a.Type {
+	case "Enumeration":
+		s := fmt.Sprint(v)
+		for _, value := range a.Values {
+			if strings.EqualFold(value, s) {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported value %s, expecting %s", s, strings.Join(a.Values, ", "))
+	case "Integer":
+		var i int64
+		switch value := v.(type) {
+		case int:
+			i = int64(value)
+		case int64:
+			i = value
+		case float64:
+			if value != math.Trunc(value) {
+				return nil, fmt.Errorf("value %v is not an integer", value)
+			}
+			i = int64(value)
+		case string:
+			n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("value %s is not an integer", value)
+			}
+			i = n
+		default:
+			return nil, fmt.Errorf("value %v is not an integer", v)
+		}
+		if a.LowerBound != nil && i < *a.LowerBound {
+			return nil, fmt.Errorf("value %d is less than %d", i, *a.LowerBound)
+		}
+		if a.UpperBound != nil && i > *a.UpperBound {
+			return nil, fmt.Errorf("value %d is greater than %d", i, *a.UpperBound)
+		}
+		return i, nil
+	case "Boolean":
+		switch value := v.(type) {
+		case bool:
+			return value, nil
+		case string:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("value %s is not a boolean", value)
+			}
+			return b, nil
+		}
+		return nil, fmt.Errorf("value %v is not a boolean", v)
+	case "String", "Password":
+		s := fmt.Sprint(v)
+		if a.MinLength != nil && int64(len(s)) < *a.MinLength {
+			return nil, fmt.Errorf("value is shorter than %d characters", *a.MinLength)
+		}
+		if a.MaxLength != nil && int64(len(s)) > *a.MaxLength {
+			return nil, fmt.Errorf("value is longer than %d characters", *a.MaxLength)
+		}
+		if a.ValueExpression != "" {
+			// The expressions the service supports, but Go does not, are
+			// enforced by the service.
+			if re, err := regexp.Compile(a.ValueExpression); err == nil && !re.MatchString(s) {
+				if a.Type == "Password" {
+					return nil, fmt.Errorf("value %s does not match %s", MaskedValue, a.ValueExpression)
+				}
+				return nil, fmt.Errorf("value %s does not match %s", s, a.ValueExpression)
+			}
+		}
+		return s, nil
+	}
+	return v, nil
+}
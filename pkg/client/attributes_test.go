@@ -0,0 +1,209 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAttributeSets(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	testFailed := 0
+	for i, test := range []struct {
+		set       string
+		name      string
+		exp       interface{}
+		shouldErr bool
+	}{
+		{set: AttributeSetIDRAC, name: "NTPConfigGroup.1.NTPEnable", exp: "Enabled"},
+		{set: AttributeSetSystem, name: "ServerOS.1.ServerPoweredOnTime", exp: float64(1209600)},
+		{set: AttributeSetLifecycleController, name: "LCAttributes.1.CollectSystemInventoryOnRestart", exp: "Enabled"},
+		{set: "BIOS.Setup.1-1", shouldErr: true},
+	} {
+		attributeSet, err := cli.GetAttributeSet(test.set)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: set %s, expected success, but got error: %s", i, test.set, err)
+				testFailed++
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: set %s, expected failure, but got non-error response", i, test.set)
+			testFailed++
+			continue
+		}
+		if attributeSet.ID != test.set || attributeSet.AttributeRegistry != "ManagerAttributeRegistry.v1_0_0" {
+			t.Logf("FAIL: Test %d: set %s, unexpected attribute set: %+v", i, test.set, *attributeSet)
+			testFailed++
+			continue
+		}
+		if value := attributeSet.Attributes[test.name]; value != test.exp {
+			t.Logf("FAIL: Test %d: set %s, attribute %s: expected %v, but got %v", i, test.set, test.name, test.exp, value)
+			testFailed++
+		}
+		if err := cli.SetAttributeSet(test.set, map[string]interface{}{test.name: test.exp}); err != nil {
+			t.Logf("FAIL: Test %d: set %s, expected success, but got error: %s", i, test.set, err)
+			testFailed++
+		}
+	}
+	if err := cli.SetAttributeSet(AttributeSetIDRAC, map[string]interface{}{}); err == nil {
+		t.Logf("FAIL: expected failure due to no attribute changes, but got non-error response")
+		testFailed++
+	}
+
+	registry, err := cli.GetAttributeRegistry("ManagerAttributeRegistry.v1_0_0")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if registry.ID != "ManagerAttributeRegistry.v1_0_0" || len(registry.Attributes) != 22 {
+		t.Fatalf("client: unexpected attribute registry: %s, %d attributes", registry.ID, len(registry.Attributes))
+	}
+	attributeSet, err := cli.GetAttributeSet(AttributeSetSystem)
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	attributes := registry.Describe(attributeSet)
+	if len(attributes) != 4 || attributes[0].Name != "ServerOS.1.HostName" || attributes[0].Type != "String" {
+		t.Fatalf("client: unexpected attributes: %+v", attributes[0])
+	}
+	if !attributes[1].ReadOnly || attributes[1].Type != "Integer" {
+		t.Fatalf("client: unexpected attributes: %+v", attributes[1])
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestAttributeRegistry(t *testing.T) {
+	fp := "../../assets/responses/attribute_registry_manager_1.json"
+	content, err := ioutil.ReadFile(fp)
+	if err != nil {
+		t.Fatalf("failed reading %s: %s", fp, err)
+	}
+	registry, err := newAttributeRegistryFromString(string(content))
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	definition := registry.Lookup(AttributeSetIDRAC, "NTPConfigGroup.1.NTPEnable")
+	if definition == nil || definition.Type != "Enumeration" || !reflect.DeepEqual(definition.Values, []string{"Disabled", "Enabled"}) {
+		t.Fatalf("unexpected attribute definition: %+v", definition)
+	}
+	if registry.Lookup(AttributeSetSystem, "NTPConfigGroup.1.NTPEnable") != nil {
+		t.Fatalf("expected no attribute definition of other attribute set")
+	}
+	complianceMessages, compliant := isStructCompliant(definition)
+	if !compliant {
+		for _, entry := range complianceMessages {
+			t.Logf("%s", entry)
+		}
+		t.Fatalf("attribute definition is not compliant")
+	}
+
+	testFailed := 0
+	for i, test := range []struct {
+		set        string
+		attributes map[string]interface{}
+		exp        map[string]interface{}
+		shouldErr  bool
+	}{
+		{
+			set: AttributeSetIDRAC,
+			attributes: map[string]interface{}{
+				"NTPConfigGroup.1.NTPEnable": "enabled",
+				"NTPConfigGroup.1.NTP1":      "ntp1.example.com",
+				"WebServer.1.Timeout":        "1800",
+				"LDAP.1.Port":                float64(636),
+				"NIC.1.DNSRacName":           "idrac-web01",
+			},
+			exp: map[string]interface{}{
+				"NTPConfigGroup.1.NTPEnable": "Enabled",
+				"NTPConfigGroup.1.NTP1":      "ntp1.example.com",
+				"WebServer.1.Timeout":        int64(1800),
+				"LDAP.1.Port":                int64(636),
+				"NIC.1.DNSRacName":           "idrac-web01",
+			},
+		},
+		{
+			set:        AttributeSetLifecycleController,
+			attributes: map[string]interface{}{"LCAttributes.1.LifecycleControllerState": "Recovery"},
+			exp:        map[string]interface{}{"LCAttributes.1.LifecycleControllerState": "Recovery"},
+		},
+		{set: AttributeSetIDRAC, attributes: map[string]interface{}{"NTPConfigGroup.1.NTP9": "ntp9"}, shouldErr: true},
+		{set: AttributeSetIDRAC, attributes: map[string]interface{}{"Info.1.Version": "4.00.00.00"}, shouldErr: true},
+		{set: AttributeSetIDRAC, attributes: map[string]interface{}{"IPMILan.1.Enable": "Off"}, shouldErr: true},
+		{set: AttributeSetIDRAC, attributes: map[string]interface{}{"WebServer.1.Timeout": "30"}, shouldErr: true},
+		{set: AttributeSetIDRAC, attributes: map[string]interface{}{"WebServer.1.Timeout": "thirty"}, shouldErr: true},
+		{set: AttributeSetIDRAC, attributes: map[string]interface{}{"NIC.1.DNSRacName": "idrac_web01"}, shouldErr: true},
+		{set: AttributeSetIDRAC, attributes: map[string]interface{}{"SNMP.1.AgentCommunity": "a-community-string-longer-than-31"}, shouldErr: true},
+		{set: AttributeSetSystem, attributes: map[string]interface{}{"IPMILan.1.Enable": "Disabled"}, shouldErr: true},
+		{set: AttributeSetIDRAC, attributes: map[string]interface{}{"Users.3.Password": "pässword"}, shouldErr: true},
+	} {
+		validated, err := registry.Validate(test.set, test.attributes)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: expected success, but got error: %s", i, err)
+				testFailed++
+				continue
+			}
+			if strings.Contains(err.Error(), "pässword") {
+				t.Logf("FAIL: Test %d: expected masked password, but got error: %s", i, err)
+				testFailed++
+				continue
+			}
+			t.Logf("PASS: Test %d: expected failure, failed: %s", i, err)
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: expected failure, but got non-error response", i)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(validated, test.exp) {
+			t.Logf("FAIL: Test %d: unexpected attributes: %v (actual) vs. %v (expected)", i, validated, test.exp)
+			testFailed++
+		}
+	}
+	for i, test := range []struct {
+		set  string
+		name string
+		exp  bool
+	}{
+		{set: AttributeSetIDRAC, name: "Users.3.Password", exp: true},
+		{set: AttributeSetIDRAC, name: "NIC.1.DNSRacName", exp: false},
+		// The attributes absent from the registry are passwords by name.
+		{set: AttributeSetIDRAC, name: "Users.16.Password", exp: true},
+	} {
+		if got := registry.IsPassword(test.set, test.name); got != test.exp {
+			t.Logf("FAIL: Test %d: attribute %s, expected password %t, but got %t", i, test.name, test.exp, got)
+			testFailed++
+		}
+	}
+	attributes := registry.Describe(&AttributeSet{
+		ID:         AttributeSetIDRAC,
+		Attributes: map[string]interface{}{"Users.3.Password": "secret", "Users.4.Password": nil},
+	})
+	if attributes[0].Value != MaskedValue || attributes[1].Value != nil {
+		t.Logf("FAIL: expected masked password, but got %v and %v", attributes[0].Value, attributes[1].Value)
+		testFailed++
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
@@ -4,7 +4,10 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"io"
@@ -34,20 +37,31 @@ type Client struct {
 	username           string
 	password           string
 	validateServerCert bool
+	rootCAs            *x509.CertPool
 	rootPath           string
 	dataLimit          int64
+	taskPollInterval   time.Duration
+	ctx                context.Context
 }
 
 // NewClient returns an instance of Client.
 func NewClient() *Client {
 	return &Client{
-		dataLimit: ReceiverDataLimit,
-		port:      443,
-		protocol:  "https",
-		rootPath:  "/redfish/v1/",
+		dataLimit:        ReceiverDataLimit,
+		port:             443,
+		protocol:         "https",
+		rootPath:         "/redfish/v1/",
+		taskPollInterval: DefaultTaskPollInterval,
 	}
 }
 
+// Clone returns a copy of the Client. The copy may be pointed to a different
+// host while keeping the credentials and transport settings.
+func (cli *Client) Clone() *Client {
+	c := *cli
+	return &c
+}
+
 func (cli *Client) rebaseURL() {
 	if (cli.protocol == "https" && cli.port == 443) ||
 		(cli.protocol == "http" && cli.port == 80) {
@@ -68,6 +82,29 @@ func (cli *Client) SetHost(s string) error {
 	return nil
 }
 
+// SetContext sets the context of the API calls. The API calls in progress,
+// including the uploads, are cancelled when the context is done.
+func (cli *Client) SetContext(ctx context.Context) error {
+	if ctx == nil {
+		return fmt.Errorf("nil context")
+	}
+	cli.ctx = ctx
+	return nil
+}
+
+// Context returns the context of the API calls.
+func (cli *Client) Context() context.Context {
+	if cli.ctx == nil {
+		return context.Background()
+	}
+	return cli.ctx
+}
+
+// GetHost returns the target host of the API calls.
+func (cli *Client) GetHost() string {
+	return cli.host
+}
+
 // SetPort sets the port number for the API calls.
 func (cli *Client) SetPort(p int) error {
 	if p == 0 {
@@ -96,6 +133,27 @@ func (cli *Client) SetPassword(s string) error {
 	return nil
 }
 
+// SetCredentialProvider sets the username and password for the API calls
+// to the credentials the provider supplies for the host. The host must be
+// set prior to calling the function. The empty username or password
+// supplied by the provider leave the current value unchanged.
+func (cli *Client) SetCredentialProvider(p CredentialProvider) error {
+	if cli.host == "" {
+		return fmt.Errorf("empty hostname or ip address")
+	}
+	credentials, err := p.GetCredentials(cli.host)
+	if err != nil {
+		return err
+	}
+	if credentials.Username != "" {
+		cli.username = credentials.Username
+	}
+	if credentials.Password != "" {
+		cli.password = credentials.Password
+	}
+	return nil
+}
+
 // SetProtocol sets the protocol for the API calls.
 func (cli *Client) SetProtocol(s string) error {
 	switch s {
@@ -117,6 +175,32 @@ func (cli *Client) SetValidateServerCertificate() error {
 	return nil
 }
 
+// SetServerCertificateAuthority sets the PEM-encoded certificates of
+// the authorities issuing server certificates. The system certificate pool
+// is used by default.
+func (cli *Client) SetServerCertificateAuthority(fp string) error {
+	b, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return fmt.Errorf("no certificates found in %s", fp)
+	}
+	cli.rootCAs = pool
+	return nil
+}
+
+// SetTaskPollInterval sets the interval between the requests checking the
+// state of a task, see WaitForTask.
+func (cli *Client) SetTaskPollInterval(d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("task poll interval must be positive, got %s", d)
+	}
+	cli.taskPollInterval = d
+	return nil
+}
+
 // GetOperations returns the names of available operations.
 func (cli *Client) GetOperations() map[string]*CliOperation {
 	operations := make(map[string]*CliOperation)
@@ -128,15 +212,191 @@ func (cli *Client) GetOperations() map[string]*CliOperation {
 		Name:        "get-info",
 		Description: "Get information about computer systems exposed via Redfish API",
 	}
+	operations["check-health"] = &CliOperation{
+		Name:        "check-health",
+		Description: "Check system health in the format of Nagios/Icinga monitoring plugins",
+	}
+	operations["serve-metrics"] = &CliOperation{
+		Name:        "serve-metrics",
+		Description: "Expose metrics of one or more systems in Prometheus text format",
+	}
+	operations["rotate-password"] = &CliOperation{
+		Name:        "rotate-password",
+		Description: "Rotate the password of an account and verify the login with the new password",
+	}
+	operations["list-accounts"] = &CliOperation{
+		Name:        "list-accounts",
+		Description: "List the user accounts",
+	}
+	operations["list-roles"] = &CliOperation{
+		Name:        "list-roles",
+		Description: "List the roles and privileges of the user accounts",
+	}
+	operations["get-account-policy"] = &CliOperation{
+		Name:        "get-account-policy",
+		Description: "Get the account lockout and password policy",
+	}
+	operations["set-account-policy"] = &CliOperation{
+		Name:        "set-account-policy",
+		Description: "Change the account lockout and password policy",
+	}
+	operations["create-account"] = &CliOperation{
+		Name:        "create-account",
+		Description: "Create a user account in an empty account slot",
+	}
+	operations["update-account"] = &CliOperation{
+		Name:        "update-account",
+		Description: "Change the role, password, or state of a user account",
+	}
+	operations["disable-account"] = &CliOperation{
+		Name:        "disable-account",
+		Description: "Disable a user account",
+	}
+	operations["delete-account"] = &CliOperation{
+		Name:        "delete-account",
+		Description: "Delete a user account and clear its account slot",
+	}
+	operations["get-directory"] = &CliOperation{
+		Name:        "get-directory",
+		Description: "Get the configuration of LDAP and Active Directory services",
+	}
+	operations["apply-directory"] = &CliOperation{
+		Name:        "apply-directory",
+		Description: "Apply the configuration of LDAP and Active Directory services from a YAML file",
+	}
+	operations["get-event-service"] = &CliOperation{
+		Name:        "get-event-service",
+		Description: "Get the event types and delivery settings of the event service",
+	}
+	operations["list-event-subscriptions"] = &CliOperation{
+		Name:        "list-event-subscriptions",
+		Description: "List the event subscriptions",
+	}
+	operations["create-event-subscription"] = &CliOperation{
+		Name:        "create-event-subscription",
+		Description: "Subscribe an HTTPS destination to the events",
+	}
+	operations["delete-event-subscription"] = &CliOperation{
+		Name:        "delete-event-subscription",
+		Description: "Delete an event subscription",
+	}
+	operations["submit-test-event"] = &CliOperation{
+		Name:        "submit-test-event",
+		Description: "Send a test event to the event subscribers",
+	}
+	operations["tail-events"] = &CliOperation{
+		Name:        "tail-events",
+		Description: "Print the events of the Server-Sent Events stream until interrupted",
+	}
+	operations["resolve-message"] = &CliOperation{
+		Name:        "resolve-message",
+		Description: "Resolve a message id and its arguments into the message, severity, and resolution",
+	}
+	operations["get-sel"] = &CliOperation{
+		Name:        "get-sel",
+		Description: "Get the entries of the System Event Log",
+	}
+	operations["get-lclog"] = &CliOperation{
+		Name:        "get-lclog",
+		Description: "Get the entries of the Lifecycle log",
+	}
+	operations["clear-sel"] = &CliOperation{
+		Name:        "clear-sel",
+		Description: "Clear the System Event Log",
+	}
+	operations["get-firmware"] = &CliOperation{
+		Name:        "get-firmware",
+		Description: "Get the firmware inventory, i.e. the versions of the firmware components",
+	}
+	operations["check-firmware"] = &CliOperation{
+		Name:        "check-firmware",
+		Description: "Compare the installed firmware with Dell Update Catalog or a baseline",
+	}
+	operations["firmware-update"] = &CliOperation{
+		Name:        "firmware-update",
+		Description: "Update firmware from an image file or an image URI",
+	}
+	operations["list-jobs"] = &CliOperation{
+		Name:        "list-jobs",
+		Description: "List the jobs of the job queue of iDRAC",
+	}
+	operations["wait-job"] = &CliOperation{
+		Name:        "wait-job",
+		Description: "Wait until a job is done and report its progress",
+	}
+	operations["delete-job"] = &CliOperation{
+		Name:        "delete-job",
+		Description: "Delete a job, or all the jobs, from the job queue of iDRAC",
+	}
+	operations["list-tasks"] = &CliOperation{
+		Name:        "list-tasks",
+		Description: "List the tasks of the task service",
+	}
+	operations["export-scp"] = &CliOperation{
+		Name:        "export-scp",
+		Description: "Export Server Configuration Profile",
+	}
+	operations["import-scp"] = &CliOperation{
+		Name:        "import-scp",
+		Description: "Import, or preview the import of, Server Configuration Profile",
+	}
+	operations["plan"] = &CliOperation{
+		Name:        "plan",
+		Description: "Compare the BIOS, iDRAC, boot, NIC, and account settings with the desired state",
+	}
+	operations["apply"] = &CliOperation{
+		Name:        "apply",
+		Description: "Apply the desired BIOS, iDRAC, boot, NIC, and account settings, and verify them",
+	}
+	operations["snapshot-config"] = &CliOperation{
+		Name:        "snapshot-config",
+		Description: "Save the BIOS, iDRAC, boot, and NIC settings to a snapshot",
+	}
+	operations["drift"] = &CliOperation{
+		Name:        "drift",
+		Description: "Compare the BIOS, iDRAC, boot, and NIC settings with a baseline snapshot",
+	}
+	operations["get-attributes"] = &CliOperation{
+		Name:        "get-attributes",
+		Description: "Get the iDRAC, System, or Lifecycle Controller attributes",
+	}
+	operations["set-attributes"] = &CliOperation{
+		Name:        "set-attributes",
+		Description: "Set the iDRAC, System, or Lifecycle Controller attributes",
+	}
+	operations["get-network-protocol"] = &CliOperation{
+		Name:        "get-network-protocol",
+		Description: "Get the network services of iDRAC, e.g. NTP, SNMP, SSH, and IPMI",
+	}
+	operations["set-network-protocol"] = &CliOperation{
+		Name:        "set-network-protocol",
+		Description: "Change the network services of iDRAC, e.g. NTP, SNMP, SSH, and IPMI",
+	}
+	operations["check-network-protocol"] = &CliOperation{
+		Name:        "check-network-protocol",
+		Description: "Check the network services of iDRAC against a hardening policy",
+	}
+	operations["get-ethernet-interfaces"] = &CliOperation{
+		Name:        "get-ethernet-interfaces",
+		Description: "Get the network interfaces of iDRAC, e.g. IP addresses, DHCP, VLAN, and DNS servers",
+	}
+	operations["set-ethernet-interface"] = &CliOperation{
+		Name:        "set-ethernet-interface",
+		Description: "Change the network interface of iDRAC and confirm the changes by reconnecting",
+	}
+	operations["forward-logs"] = &CliOperation{
+		Name:        "forward-logs",
+		Description: "Forward the new entries of the System Event Log and the Lifecycle log to a syslog server",
+	}
+	operations["listen-events"] = &CliOperation{
+		Name:        "listen-events",
+		Description: "Receive the events pushed by event subscriptions over HTTPS",
+	}
 	return operations
 }
 
-func (cli *Client) callAPI(method string, contentType string, urlPath string, payload []byte) ([]byte, error) {
-	if !strings.HasPrefix(urlPath, "/") {
-		urlPath = "/" + urlPath
-	}
-	url := fmt.Sprintf("%s%s", cli.url, urlPath)
-	log.Debugf("%s request to %s", method, url)
+// newTransport returns the transport of the API calls.
+func (cli *Client) newTransport() *http.Transport {
 	tr := &http.Transport{
 		Dial: (&net.Dialer{
 			Timeout: 10 * time.Second,
@@ -147,34 +407,66 @@ func (cli *Client) callAPI(method string, contentType string, urlPath string, pa
 		tr.TLSClientConfig = &tls.Config{
 			InsecureSkipVerify: true,
 		}
+	} else if cli.rootCAs != nil {
+		tr.TLSClientConfig = &tls.Config{
+			RootCAs: cli.rootCAs,
+		}
 	}
-	httpClient := &http.Client{
-		Transport: tr,
-		Timeout:   time.Second * 30,
+	return tr
+}
+
+func (cli *Client) callAPI(method string, contentType string, urlPath string, payload []byte) ([]byte, error) {
+	body, _, err := cli.callAPIWithHeaders(method, contentType, urlPath, payload)
+	return body, err
+}
+
+// callAPIWithHeaders returns the body and the headers of the response, e.g.
+// Location header of the resources created via POST requests.
+func (cli *Client) callAPIWithHeaders(method string, contentType string, urlPath string, payload []byte) ([]byte, http.Header, error) {
+	req, err := cli.newRequest(method, contentType, urlPath, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, nil, err
 	}
+	return cli.doRequest(req, time.Second*30)
+}
 
-	var req *http.Request
-	var err error
-	req, err = http.NewRequest(method, url, bytes.NewBuffer(payload))
+// newRequest returns an authenticated API request.
+func (cli *Client) newRequest(method string, contentType string, urlPath string, body io.Reader) (*http.Request, error) {
+	if !strings.HasPrefix(urlPath, "/") {
+		urlPath = "/" + urlPath
+	}
+	url := fmt.Sprintf("%s%s", cli.url, urlPath)
+	log.Debugf("%s request to %s", method, url)
+	req, err := http.NewRequestWithContext(cli.Context(), method, url, body)
 	if err != nil {
 		return nil, err
 	}
-
 	if contentType != "" {
 		req.Header.Add("Content-Type", contentType)
 	}
 	req.Header.Add("Accept", "application/json;charset=utf-8")
 	req.Header.Add("Cache-Control", "no-cache")
 	req.SetBasicAuth(cli.username, cli.password)
+	return req, nil
+}
+
+// doRequest sends an API request and returns the body and the headers of
+// the response.
+func (cli *Client) doRequest(req *http.Request, timeout time.Duration) ([]byte, http.Header, error) {
+	url := req.URL.String()
+	httpClient := &http.Client{
+		Transport: cli.newTransport(),
+		Timeout:   timeout,
+	}
 
 	res, err := httpClient.Do(req)
 	if err != nil {
 		if !strings.HasSuffix(err.Error(), "EOF") {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 	if res == nil {
-		return nil, fmt.Errorf("response: <nil>, verify url: %s", url)
+		return nil, nil, fmt.Errorf("response: <nil>, verify url: %s", url)
 	}
 	defer res.Body.Close()
 
@@ -183,15 +475,32 @@ func (cli *Client) callAPI(method string, contentType string, urlPath string, pa
 	dataLimiter := io.LimitReader(res.Body, cli.dataLimit)
 	body, err := ioutil.ReadAll(dataLimiter)
 	if err != nil {
-		return nil, fmt.Errorf("non-EOF error at url %s: %s", url, err)
+		return nil, nil, fmt.Errorf("non-EOF error at url %s: %s", url, err)
 	}
 
 	switch res.StatusCode {
-	case 200:
-		return body, nil
+	case 200, 201, 202, 204:
+		return body, res.Header, nil
 	default:
-		return nil, fmt.Errorf("error: status code %d: %s", res.StatusCode, string(body))
+		return nil, nil, newAPIError(res.StatusCode, body)
 	}
+}
 
-	return body, nil
+// patchResource sends the properties to a resource via PATCH request.
+func (cli *Client) patchResource(urlPath string, properties interface{}) ([]byte, error) {
+	payload, err := json.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+	return cli.callAPI("PATCH", "application/json", urlPath, payload)
+}
+
+// postResource sends the properties to a collection or an action via
+// POST request.
+func (cli *Client) postResource(urlPath string, properties interface{}) ([]byte, http.Header, error) {
+	payload, err := json.Marshal(properties)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cli.callAPIWithHeaders("POST", "application/json", urlPath, payload)
 }
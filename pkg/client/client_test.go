@@ -3,6 +3,7 @@
 package client
 
 import (
+	"context"
 	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
 	log "github.com/sirupsen/logrus"
 	"strings"
@@ -114,6 +115,21 @@ func TestClient(t *testing.T) {
 		t.Fatalf("client: expected failure, but got non-error response")
 	}
 
+	t.Logf("client: testing SetContext()")
+	if err := cli.SetContext(nil); err == nil {
+		t.Fatalf("expected failure, but succeeded")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cancelled := cli.Clone()
+	cancelled.SetContext(ctx)
+	if _, err := cancelled.GetInfo(); err == nil {
+		t.Fatalf("client: expected failure with cancelled context, but got non-error response")
+	}
+	if _, err := cli.GetInfo(); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+
 	t.Logf("client: testing secure client")
 	cli = NewClient()
 	cli.SetHost(server.TLS.Hostname)
@@ -0,0 +1,40 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// collectionResponse is the common part of Redfish resource collections,
+// e.g. NetworkInterfaceCollection or NetworkPortCollection.
+type collectionResponse struct {
+	ODataAnnotation
+	Name         string
+	Description  string
+	MembersCount uint64 `json:"Members@odata.count"`
+	Members      []ODataAnnotation
+	NextLink     string `json:"Members@odata.nextLink"`
+}
+
+// getCollectionMembers returns the paths of the members of a collection.
+// The function follows the next links of paginated collections.
+func (cli *Client) getCollectionMembers(s string) ([]string, error) {
+	members := []string{}
+	for s != "" {
+		resp, err := cli.callAPI("GET", "", s, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		response := &collectionResponse{}
+		if err := json.Unmarshal(resp, response); err != nil {
+			return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+		}
+		for _, member := range response.Members {
+			members = append(members, member.ID)
+		}
+		s = response.NextLink
+	}
+	return members, nil
+}
@@ -215,6 +215,8 @@ func TestParseComputerSystemJsonOutput(t *testing.T) {
 
 func convertFieldToTag(s string) string {
 	s = strings.Replace(s, "OData", "odata", -1)
+	// The plural initialisms, e.g. MessageIDs, map to message_ids.
+	s = strings.Replace(s, "IDs", "Ids", -1)
 	s = strcase.ToSnake(s)
 	return s
 }
@@ -0,0 +1,362 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"sort"
+)
+
+// Config is the configuration of hosts, e.g. $HOME/.redfish/redfish.yaml.
+// The profiles inherit the settings of their group, and the groups inherit
+// the top-level defaults.
+type Config struct {
+	// Username, Password, and Host are the legacy top-level settings.
+	// The username and password are the defaults of all profiles.
+	Username string                  `yaml:"username" json:"username" xml:"username"`
+	Password string                  `yaml:"password" json:"password" xml:"password"`
+	Host     string                  `yaml:"host" json:"host" xml:"host"`
+	Defaults *HostProfile            `yaml:"defaults" json:"defaults" xml:"defaults"`
+	Groups   map[string]*HostGroup   `yaml:"groups" json:"groups" xml:"-"`
+	Profiles map[string]*HostProfile `yaml:"profiles" json:"profiles" xml:"-"`
+}
+
+// HostProfile holds the settings of a host.
+type HostProfile struct {
+	Name        string            `yaml:"name" json:"name" xml:"name"`
+	Group       string            `yaml:"group" json:"group" xml:"group"`
+	Host        string            `yaml:"host" json:"host" xml:"host"`
+	Port        int               `yaml:"port" json:"port" xml:"port"`
+	Protocol    string            `yaml:"protocol" json:"protocol" xml:"protocol"`
+	Credentials *CredentialSource `yaml:"credentials" json:"credentials" xml:"credentials"`
+	TLS         *TLSSettings      `yaml:"tls" json:"tls" xml:"tls"`
+	Labels      map[string]string `yaml:"labels" json:"labels" xml:"-"`
+}
+
+// CredentialSource holds the credentials of a host, or the instructions
+// for obtaining them. A source has at most one of password, password_env,
+// password_file, password_command, and netrc. The hosts hold per-host,
+// or per-CIDR range, overrides of the source.
+type CredentialSource struct {
+	Username        string                       `yaml:"username" json:"username" xml:"username"`
+	Password        string                       `yaml:"password" json:"password" xml:"password"`
+	PasswordEnv     string                       `yaml:"password_env" json:"password_env" xml:"password_env"`
+	PasswordFile    string                       `yaml:"password_file" json:"password_file" xml:"password_file"`
+	PasswordCommand string                       `yaml:"password_command" json:"password_command" xml:"password_command"`
+	Netrc           bool                         `yaml:"netrc" json:"netrc" xml:"netrc"`
+	NetrcFile       string                       `yaml:"netrc_file" json:"netrc_file" xml:"netrc_file"`
+	Hosts           map[string]*CredentialSource `yaml:"hosts" json:"hosts" xml:"-"`
+}
+
+// TLSSettings holds the settings of TLS connections to a host.
+type TLSSettings struct {
+	ValidateServerCert *bool  `yaml:"validate_server_cert" json:"validate_server_cert" xml:"validate_server_cert"`
+	CAFile             string `yaml:"ca_file" json:"ca_file" xml:"ca_file"`
+}
+
+// HostGroup is a group of profiles. The hosts of a group are the profiles
+// referencing the group, and the hosts, or CIDR ranges, listed in the group.
+// A group may be a list of hosts only.
+type HostGroup struct {
+	Defaults *HostProfile `yaml:"defaults" json:"defaults" xml:"defaults"`
+	Hosts    []string     `yaml:"hosts" json:"hosts" xml:"hosts"`
+}
+
+// UnmarshalYAML unpacks HostGroup from either a mapping or a list of hosts.
+func (g *HostGroup) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		return node.Decode(&g.Hosts)
+	}
+	type rawHostGroup HostGroup
+	return node.Decode((*rawHostGroup)(g))
+}
+
+// LoadConfig loads the configuration from a YAML or JSON file.
+func LoadConfig(fp string) (*Config, error) {
+	b, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	return newConfigFromBytes(b)
+}
+
+// newConfigFromBytes returns Config instance from an input byte array.
+func newConfigFromBytes(b []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("configuration parsing error: %s", err)
+	}
+	if cfg.Defaults == nil {
+		cfg.Defaults = &HostProfile{}
+	}
+	if cfg.Username != "" || cfg.Password != "" {
+		cfg.Defaults = cfg.Defaults.Merge(&HostProfile{
+			Credentials: &CredentialSource{
+				Username: cfg.Username,
+				Password: cfg.Password,
+			},
+		})
+	}
+	if cfg.Groups == nil {
+		cfg.Groups = make(map[string]*HostGroup)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*HostProfile)
+	}
+	for name, group := range cfg.Groups {
+		if group == nil {
+			cfg.Groups[name] = &HostGroup{}
+		}
+	}
+	for name, profile := range cfg.Profiles {
+		if profile == nil {
+			profile = &HostProfile{}
+			cfg.Profiles[name] = profile
+		}
+		profile.Name = name
+		if profile.Group != "" {
+			if _, exists := cfg.Groups[profile.Group]; !exists {
+				return nil, fmt.Errorf("profile %s references undefined group %s", name, profile.Group)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// GetProfile returns the profile with the settings inherited from its
+// group and the defaults. The name of the profile is the host, unless
+// the profile sets the host.
+func (cfg *Config) GetProfile(name string) (*HostProfile, error) {
+	profile, exists := cfg.Profiles[name]
+	if !exists {
+		return nil, fmt.Errorf("profile %s not found", name)
+	}
+	resolved := profile.Merge(nil)
+	if profile.Group != "" {
+		resolved = resolved.Merge(cfg.Groups[profile.Group].Defaults)
+	}
+	resolved = resolved.Merge(cfg.Defaults)
+	if resolved.Host == "" {
+		resolved.Host = name
+	}
+	return resolved, nil
+}
+
+// GetGroupProfiles returns the profiles of a group, sorted by name,
+// followed by the profiles of the hosts listed in the group. The hosts
+// listed in the group are returned as is, i.e. CIDR ranges are not expanded.
+func (cfg *Config) GetGroupProfiles(name string) ([]*HostProfile, error) {
+	group, exists := cfg.Groups[name]
+	if !exists {
+		return nil, fmt.Errorf("group %s not found", name)
+	}
+	names := []string{}
+	for profileName, profile := range cfg.Profiles {
+		if profile.Group == name {
+			names = append(names, profileName)
+		}
+	}
+	sort.Strings(names)
+	profiles := []*HostProfile{}
+	for _, profileName := range names {
+		profile, err := cfg.GetProfile(profileName)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	for _, host := range group.Hosts {
+		profile := &HostProfile{Name: host, Group: name, Host: host}
+		profile = profile.Merge(group.Defaults).Merge(cfg.Defaults)
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// Merge returns a copy of the profile with the settings not set in the
+// profile taken from the defaults.
+func (p *HostProfile) Merge(defaults *HostProfile) *HostProfile {
+	merged := *p
+	if p.Credentials != nil {
+		credentials := *p.Credentials
+		merged.Credentials = &credentials
+	}
+	if p.TLS != nil {
+		tlsSettings := *p.TLS
+		merged.TLS = &tlsSettings
+	}
+	merged.Labels = make(map[string]string)
+	if defaults != nil {
+		for k, v := range defaults.Labels {
+			merged.Labels[k] = v
+		}
+	}
+	for k, v := range p.Labels {
+		merged.Labels[k] = v
+	}
+	if defaults == nil {
+		return &merged
+	}
+	if merged.Host == "" {
+		merged.Host = defaults.Host
+	}
+	if merged.Port == 0 {
+		merged.Port = defaults.Port
+	}
+	if merged.Protocol == "" {
+		merged.Protocol = defaults.Protocol
+	}
+	if defaults.Credentials != nil {
+		if merged.Credentials == nil {
+			merged.Credentials = &CredentialSource{}
+		}
+		merged.Credentials.merge(defaults.Credentials)
+	}
+	if defaults.TLS != nil {
+		if merged.TLS == nil {
+			merged.TLS = &TLSSettings{}
+		}
+		if merged.TLS.ValidateServerCert == nil {
+			merged.TLS.ValidateServerCert = defaults.TLS.ValidateServerCert
+		}
+		if merged.TLS.CAFile == "" {
+			merged.TLS.CAFile = defaults.TLS.CAFile
+		}
+	}
+	return &merged
+}
+
+func (c *CredentialSource) merge(defaults *CredentialSource) {
+	if c.Username == "" {
+		c.Username = defaults.Username
+	}
+	if !c.hasPasswordSource() {
+		c.Password = defaults.Password
+		c.PasswordEnv = defaults.PasswordEnv
+		c.PasswordFile = defaults.PasswordFile
+		c.PasswordCommand = defaults.PasswordCommand
+		c.Netrc = defaults.Netrc
+		c.NetrcFile = defaults.NetrcFile
+	}
+	if len(defaults.Hosts) > 0 {
+		hosts := make(map[string]*CredentialSource)
+		for k, v := range defaults.Hosts {
+			hosts[k] = v
+		}
+		for k, v := range c.Hosts {
+			hosts[k] = v
+		}
+		c.Hosts = hosts
+	}
+}
+
+func (c *CredentialSource) hasPasswordSource() bool {
+	return c.Password != "" || c.PasswordEnv != "" || c.PasswordFile != "" ||
+		c.PasswordCommand != "" || c.Netrc || c.NetrcFile != ""
+}
+
+// Provider returns the provider of the credentials of the source.
+func (c *CredentialSource) Provider() (CredentialProvider, error) {
+	provider, err := c.provider()
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Hosts) == 0 {
+		return provider, nil
+	}
+	hp := &HostCredentialProvider{
+		Hosts:   make(map[string]CredentialProvider),
+		Default: provider,
+	}
+	for host, override := range c.Hosts {
+		if override == nil {
+			continue
+		}
+		source := *override
+		source.Hosts = nil
+		source.merge(&CredentialSource{Username: c.Username})
+		if !source.hasPasswordSource() {
+			return nil, fmt.Errorf("credentials of host %s have no password source", host)
+		}
+		hostProvider, err := source.provider()
+		if err != nil {
+			return nil, fmt.Errorf("credentials of host %s: %s", host, err)
+		}
+		hp.Hosts[host] = hostProvider
+	}
+	return hp, nil
+}
+
+func (c *CredentialSource) provider() (CredentialProvider, error) {
+	sources := []string{}
+	if c.Password != "" {
+		sources = append(sources, "password")
+	}
+	if c.PasswordEnv != "" {
+		sources = append(sources, "password_env")
+	}
+	if c.PasswordFile != "" {
+		sources = append(sources, "password_file")
+	}
+	if c.PasswordCommand != "" {
+		sources = append(sources, "password_command")
+	}
+	if c.Netrc || c.NetrcFile != "" {
+		sources = append(sources, "netrc")
+	}
+	if len(sources) > 1 {
+		return nil, fmt.Errorf("credentials have conflicting password sources: %v", sources)
+	}
+	switch {
+	case c.PasswordEnv != "":
+		return &EnvCredentialProvider{Username: c.Username, Variable: c.PasswordEnv}, nil
+	case c.PasswordFile != "":
+		return &PasswordFileCredentialProvider{Username: c.Username, Path: c.PasswordFile}, nil
+	case c.PasswordCommand != "":
+		return &CommandCredentialProvider{Username: c.Username, Command: c.PasswordCommand}, nil
+	case c.Netrc || c.NetrcFile != "":
+		return &NetrcCredentialProvider{Username: c.Username, Path: c.NetrcFile}, nil
+	}
+	return &StaticCredentialProvider{Username: c.Username, Password: c.Password}, nil
+}
+
+// NewClientFromProfile returns an instance of Client configured with the
+// settings of a profile.
+func NewClientFromProfile(p *HostProfile) (*Client, error) {
+	cli := NewClient()
+	if err := cli.SetHost(p.Host); err != nil {
+		return nil, fmt.Errorf("profile %s: %s", p.Name, err)
+	}
+	if p.Port != 0 {
+		if err := cli.SetPort(p.Port); err != nil {
+			return nil, fmt.Errorf("profile %s: %s", p.Name, err)
+		}
+	}
+	if p.Protocol != "" {
+		if err := cli.SetProtocol(p.Protocol); err != nil {
+			return nil, fmt.Errorf("profile %s: %s", p.Name, err)
+		}
+	}
+	if p.TLS != nil {
+		if p.TLS.ValidateServerCert != nil && *p.TLS.ValidateServerCert {
+			cli.SetValidateServerCertificate()
+		}
+		if p.TLS.CAFile != "" {
+			if err := cli.SetServerCertificateAuthority(p.TLS.CAFile); err != nil {
+				return nil, fmt.Errorf("profile %s: %s", p.Name, err)
+			}
+		}
+	}
+	if p.Credentials != nil {
+		provider, err := p.Credentials.Provider()
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %s", p.Name, err)
+		}
+		if err := cli.SetCredentialProvider(provider); err != nil {
+			return nil, fmt.Errorf("profile %s: %s", p.Name, err)
+		}
+	}
+	return cli, nil
+}
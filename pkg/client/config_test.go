@@ -0,0 +1,140 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	os.Setenv("RACK1_IDRAC_PASSWORD", "rack1secret")
+	defer os.Unsetenv("RACK1_IDRAC_PASSWORD")
+
+	cfg, err := LoadConfig("../../assets/configs/redfish_1.yaml")
+	if err != nil {
+		t.Fatalf("config: expected success, but got error: %s", err)
+	}
+
+	validate := false
+	for i, test := range []struct {
+		name string
+		exp  *HostProfile
+	}{
+		{
+			name: "r640-01",
+			exp: &HostProfile{
+				Name:     "r640-01",
+				Group:    "rack1",
+				Host:     "10.10.10.10",
+				Port:     443,
+				Protocol: "https",
+				Credentials: &CredentialSource{
+					Username:    "admin",
+					PasswordEnv: "RACK1_IDRAC_PASSWORD",
+				},
+				TLS: &TLSSettings{
+					ValidateServerCert: &validate,
+				},
+				Labels: map[string]string{
+					"site": "dc1",
+					"rack": "1",
+					"role": "db",
+				},
+			},
+		},
+		{
+			name: "lab",
+			exp: &HostProfile{
+				Name:     "lab",
+				Host:     "lab-idrac.example.com",
+				Port:     8080,
+				Protocol: "http",
+				Credentials: &CredentialSource{
+					Username: "labadmin",
+					Password: "labsecret",
+				},
+				TLS: &TLSSettings{
+					ValidateServerCert: &validate,
+				},
+				Labels: map[string]string{
+					"site": "dc1",
+				},
+			},
+		},
+	} {
+		profile, err := cfg.GetProfile(test.name)
+		if err != nil {
+			t.Fatalf("FAIL: Test %d: profile %s, expected to pass, but threw error: %v", i, test.name, err)
+		}
+		if !reflect.DeepEqual(profile, test.exp) {
+			t.Fatalf("FAIL: Test %d: profile %s, value mismatch: '%+v' (actual) vs. '%+v' (expected)", i, test.name, *profile, *test.exp)
+		}
+	}
+
+	profile, err := cfg.GetProfile("r640-02")
+	if err != nil {
+		t.Fatalf("config: expected success, but got error: %s", err)
+	}
+	if profile.Port != 8443 || !*profile.TLS.ValidateServerCert {
+		t.Fatalf("config: expected profile settings to take precedence over defaults, got: %+v", *profile)
+	}
+
+	if _, err := cfg.GetProfile("r640-03"); err == nil {
+		t.Fatalf("config: expected failure, but got non-error response")
+	}
+
+	profiles, err := cfg.GetGroupProfiles("rack1")
+	if err != nil {
+		t.Fatalf("config: expected success, but got error: %s", err)
+	}
+	names := []string{}
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"r640-01", "r640-02", "10.10.20.0/30"}) {
+		t.Fatalf("config: unexpected group profiles: %v", names)
+	}
+	if profiles[2].Credentials.Username != "admin" || profiles[2].Labels["rack"] != "1" {
+		t.Fatalf("config: expected group hosts to inherit group defaults, got: %+v", *profiles[2])
+	}
+
+	profiles, err = cfg.GetGroupProfiles("rack2")
+	if err != nil {
+		t.Fatalf("config: expected success, but got error: %s", err)
+	}
+	if len(profiles) != 2 || profiles[0].Credentials.Username != "root" || profiles[0].Credentials.Password != "calvin" {
+		t.Fatalf("config: expected group hosts to inherit legacy credentials, got: %+v", *profiles[0])
+	}
+
+	cli, err := NewClientFromProfile(profiles[0])
+	if err != nil {
+		t.Fatalf("config: expected success, but got error: %s", err)
+	}
+	if cli.url != "https://10.10.30.10" || cli.username != "root" || cli.password != "calvin" {
+		t.Fatalf("config: unexpected client settings: %s, %s", cli.url, cli.username)
+	}
+
+	lab, _ := cfg.GetProfile("lab")
+	cli, err = NewClientFromProfile(lab)
+	if err != nil {
+		t.Fatalf("config: expected success, but got error: %s", err)
+	}
+	if cli.url != "http://lab-idrac.example.com:8080" {
+		t.Fatalf("config: unexpected client url: %s", cli.url)
+	}
+
+	os.Unsetenv("RACK1_IDRAC_PASSWORD")
+	rack1, _ := cfg.GetProfile("r640-01")
+	if _, err := NewClientFromProfile(rack1); err == nil {
+		t.Fatalf("config: expected failure due to empty password environment variable, but succeeded")
+	}
+
+	if _, err := newConfigFromBytes([]byte("profiles:\n  a:\n    group: nope\n")); err == nil {
+		t.Fatalf("config: expected failure due to undefined group, but succeeded")
+	}
+	if _, err := LoadConfig("../../assets/configs/nonexistent.yaml"); err == nil {
+		t.Fatalf("config: expected failure, but got non-error response")
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultPasswordCommandTimeout is the time a password command may run.
+const DefaultPasswordCommandTimeout = 30 * time.Second
+
+// Credentials are the username and password of a host.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// CredentialProvider supplies the credentials of hosts.
+type CredentialProvider interface {
+	GetCredentials(host string) (*Credentials, error)
+}
+
+// StaticCredentialProvider supplies the same credentials to all hosts.
+type StaticCredentialProvider struct {
+	Username string
+	Password string
+}
+
+// GetCredentials returns the credentials of a host.
+func (p *StaticCredentialProvider) GetCredentials(host string) (*Credentials, error) {
+	return &Credentials{Username: p.Username, Password: p.Password}, nil
+}
+
+// EnvCredentialProvider reads the password from an environment variable.
+type EnvCredentialProvider struct {
+	Username string
+	Variable string
+}
+
+// GetCredentials returns the credentials of a host.
+func (p *EnvCredentialProvider) GetCredentials(host string) (*Credentials, error) {
+	password := os.Getenv(p.Variable)
+	if password == "" {
+		return nil, fmt.Errorf("environment variable %s is empty", p.Variable)
+	}
+	return &Credentials{Username: p.Username, Password: password}, nil
+}
+
+// PasswordFileCredentialProvider reads the password from the first line of
+// a file. The file must not be accessible by group or others.
+type PasswordFileCredentialProvider struct {
+	Username string
+	Path     string
+}
+
+// GetCredentials returns the credentials of a host.
+func (p *PasswordFileCredentialProvider) GetCredentials(host string) (*Credentials, error) {
+	b, err := readSecretFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	password := firstLine(b)
+	if password == "" {
+		return nil, fmt.Errorf("password file %s is empty", p.Path)
+	}
+	return &Credentials{Username: p.Username, Password: password}, nil
+}
+
+// NetrcCredentialProvider reads the credentials from a netrc file, i.e.
+// the login and password of the machine entry matching the host, or of
+// the default entry. The path defaults to $NETRC, or $HOME/.netrc. The
+// username applies when the entry has no login.
+type NetrcCredentialProvider struct {
+	Username string
+	Path     string
+}
+
+// GetCredentials returns the credentials of a host.
+func (p *NetrcCredentialProvider) GetCredentials(host string) (*Credentials, error) {
+	fp := p.Path
+	if fp == "" {
+		fp = os.Getenv("NETRC")
+	}
+	if fp == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		fp = filepath.Join(home, ".netrc")
+	}
+	b, err := readSecretFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	entry := parseNetrc(b, host)
+	if entry == nil || entry.Password == "" {
+		return nil, fmt.Errorf("netrc file %s has no password for %s", fp, host)
+	}
+	if entry.Username == "" {
+		entry.Username = p.Username
+	}
+	return entry, nil
+}
+
+// parseNetrc returns the credentials of the machine entry matching a host,
+// or of the default entry.
+func parseNetrc(b []byte, host string) *Credentials {
+	var matched, fallback, current *Credentials
+	var keyword string
+	inMacro := false
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			// A macro definition ends with an empty line.
+			if strings.TrimSpace(line) == "" {
+				inMacro = false
+			}
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, token := range strings.Fields(line) {
+			if keyword == "" {
+				switch token {
+				case "default":
+					current = nil
+					if fallback == nil {
+						fallback = &Credentials{}
+						current = fallback
+					}
+				case "macdef":
+					inMacro = true
+				default:
+					keyword = token
+				}
+				if inMacro {
+					break
+				}
+				continue
+			}
+			switch keyword {
+			case "machine":
+				current = nil
+				if token == host && matched == nil {
+					matched = &Credentials{}
+					current = matched
+				}
+			case "login":
+				if current != nil {
+					current.Username = token
+				}
+			case "password":
+				if current != nil {
+					current.Password = token
+				}
+			}
+			keyword = ""
+		}
+	}
+	if matched != nil {
+		return matched
+	}
+	return fallback
+}
+
+// CommandCredentialProvider runs a helper command, e.g. a secrets manager
+// client, and reads the password from the first line of its output. The
+// command runs via /bin/sh with the host in IDRAC_API_HOST environment
+// variable.
+type CommandCredentialProvider struct {
+	Username string
+	Command  string
+	Timeout  time.Duration
+}
+
+// GetCredentials returns the credentials of a host.
+func (p *CommandCredentialProvider) GetCredentials(host string) (*Credentials, error) {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = DefaultPasswordCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", p.Command)
+	cmd.Env = append(os.Environ(), "IDRAC_API_HOST="+host)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("password command timed out after %s", timeout)
+		}
+		return nil, fmt.Errorf("password command failed: %s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	password := firstLine(stdout.Bytes())
+	if password == "" {
+		return nil, fmt.Errorf("password command returned empty output")
+	}
+	return &Credentials{Username: p.Username, Password: password}, nil
+}
+
+// HostCredentialProvider dispatches to the provider of a host, or of the
+// CIDR range containing the host. The default provider applies to the
+// remaining hosts.
+type HostCredentialProvider struct {
+	Hosts   map[string]CredentialProvider
+	Default CredentialProvider
+}
+
+// GetCredentials returns the credentials of a host.
+func (p *HostCredentialProvider) GetCredentials(host string) (*Credentials, error) {
+	if provider, exists := p.Hosts[host]; exists {
+		return provider.GetCredentials(host)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		var bestProvider CredentialProvider
+		bestSize := -1
+		for k, provider := range p.Hosts {
+			_, network, err := net.ParseCIDR(k)
+			if err != nil || !network.Contains(ip) {
+				continue
+			}
+			if size, _ := network.Mask.Size(); size > bestSize {
+				bestProvider, bestSize = provider, size
+			}
+		}
+		if bestProvider != nil {
+			return bestProvider.GetCredentials(host)
+		}
+	}
+	if p.Default == nil {
+		return nil, fmt.Errorf("no credentials for %s", host)
+	}
+	return p.Default.GetCredentials(host)
+}
+
+// readSecretFile reads a file holding secrets. It rejects the files
+// accessible by group or others.
+func readSecretFile(fp string) ([]byte, error) {
+	info, err := os.Stat(fp)
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("file %s is accessible by group or others, permissions %#o, expected 0600", fp, info.Mode().Perm())
+	}
+	return ioutil.ReadFile(fp)
+}
+
+func firstLine(b []byte) string {
+	s := string(b)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string, perm os.FileMode) string {
+	fp := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(fp, []byte(content), perm); err != nil {
+		t.Fatalf("failed writing %s: %s", fp, err)
+	}
+	if err := os.Chmod(fp, perm); err != nil {
+		t.Fatalf("failed changing permissions of %s: %s", fp, err)
+	}
+	return fp
+}
+
+func TestCredentialProviders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatalf("failed creating temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	passwordFile := writeTestFile(t, dir, "password", "filesecret\nignored\n", 0600)
+	openPasswordFile := writeTestFile(t, dir, "password_open", "filesecret\n", 0644)
+	emptyPasswordFile := writeTestFile(t, dir, "password_empty", "\n", 0600)
+	netrcFile := writeTestFile(t, dir, "netrc", `# iDRAC credentials
+machine 10.10.10.10 login root password netrcsecret
+macdef init
+machine 10.10.10.11 login nobody password nobody
+
+machine 10.10.10.12
+  password onlypassword
+default login fallback password fallbacksecret
+`, 0600)
+	os.Setenv("TEST_IDRAC_PASSWORD", "envsecret")
+	defer os.Unsetenv("TEST_IDRAC_PASSWORD")
+
+	testFailed := 0
+	for i, test := range []struct {
+		provider  CredentialProvider
+		host      string
+		exp       *Credentials
+		shouldErr bool
+	}{
+		{
+			provider: &StaticCredentialProvider{Username: "admin", Password: "secret"},
+			host:     "10.10.10.10",
+			exp:      &Credentials{Username: "admin", Password: "secret"},
+		},
+		{
+			provider: &EnvCredentialProvider{Username: "admin", Variable: "TEST_IDRAC_PASSWORD"},
+			host:     "10.10.10.10",
+			exp:      &Credentials{Username: "admin", Password: "envsecret"},
+		},
+		{
+			provider:  &EnvCredentialProvider{Username: "admin", Variable: "TEST_IDRAC_UNDEFINED"},
+			host:      "10.10.10.10",
+			shouldErr: true,
+		},
+		{
+			provider: &PasswordFileCredentialProvider{Username: "admin", Path: passwordFile},
+			host:     "10.10.10.10",
+			exp:      &Credentials{Username: "admin", Password: "filesecret"},
+		},
+		{
+			provider:  &PasswordFileCredentialProvider{Username: "admin", Path: openPasswordFile},
+			host:      "10.10.10.10",
+			shouldErr: true,
+		},
+		{
+			provider:  &PasswordFileCredentialProvider{Username: "admin", Path: emptyPasswordFile},
+			host:      "10.10.10.10",
+			shouldErr: true,
+		},
+		{
+			provider:  &PasswordFileCredentialProvider{Username: "admin", Path: filepath.Join(dir, "nonexistent")},
+			host:      "10.10.10.10",
+			shouldErr: true,
+		},
+		{
+			provider: &NetrcCredentialProvider{Username: "admin", Path: netrcFile},
+			host:     "10.10.10.10",
+			exp:      &Credentials{Username: "root", Password: "netrcsecret"},
+		},
+		{
+			// The machine entry inside a macro definition is ignored.
+			provider: &NetrcCredentialProvider{Username: "admin", Path: netrcFile},
+			host:     "10.10.10.11",
+			exp:      &Credentials{Username: "fallback", Password: "fallbacksecret"},
+		},
+		{
+			provider: &NetrcCredentialProvider{Username: "admin", Path: netrcFile},
+			host:     "10.10.10.12",
+			exp:      &Credentials{Username: "admin", Password: "onlypassword"},
+		},
+		{
+			provider: &CommandCredentialProvider{Username: "admin", Command: "echo cmd-$IDRAC_API_HOST"},
+			host:     "10.10.10.10",
+			exp:      &Credentials{Username: "admin", Password: "cmd-10.10.10.10"},
+		},
+		{
+			provider:  &CommandCredentialProvider{Username: "admin", Command: "echo denied >&2; exit 1"},
+			host:      "10.10.10.10",
+			shouldErr: true,
+		},
+		{
+			provider:  &CommandCredentialProvider{Username: "admin", Command: "true"},
+			host:      "10.10.10.10",
+			shouldErr: true,
+		},
+		{
+			provider: &HostCredentialProvider{
+				Hosts: map[string]CredentialProvider{
+					"10.10.0.0/16":  &StaticCredentialProvider{Username: "admin", Password: "wide"},
+					"10.10.20.0/24": &StaticCredentialProvider{Username: "admin", Password: "narrow"},
+				},
+				Default: &StaticCredentialProvider{Username: "admin", Password: "default"},
+			},
+			host: "10.10.20.5",
+			exp:  &Credentials{Username: "admin", Password: "narrow"},
+		},
+		{
+			provider: &HostCredentialProvider{
+				Hosts: map[string]CredentialProvider{
+					"idrac1.example.com": &StaticCredentialProvider{Username: "admin", Password: "exact"},
+				},
+				Default: &StaticCredentialProvider{Username: "admin", Password: "default"},
+			},
+			host: "idrac2.example.com",
+			exp:  &Credentials{Username: "admin", Password: "default"},
+		},
+		{
+			provider:  &HostCredentialProvider{},
+			host:      "10.10.10.10",
+			shouldErr: true,
+		},
+	} {
+		credentials, err := test.provider.GetCredentials(test.host)
+		if test.shouldErr {
+			if err == nil {
+				t.Logf("FAIL: Test %d: expected to fail, but succeeded: %+v", i, *credentials)
+				testFailed++
+			}
+			continue
+		}
+		if err != nil {
+			t.Logf("FAIL: Test %d: expected to pass, but threw error: %v", i, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(credentials, test.exp) {
+			t.Logf("FAIL: Test %d: value mismatch: '%+v' (actual) vs. '%+v' (expected)", i, *credentials, *test.exp)
+			testFailed++
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestCredentialSourceProvider(t *testing.T) {
+	cfg, err := newConfigFromBytes([]byte(`
+defaults:
+  credentials:
+    username: admin
+    password_command: echo default-$IDRAC_API_HOST
+    hosts:
+      10.10.10.0/24:
+        password: rack
+groups:
+  rack1:
+    defaults:
+      credentials:
+        hosts:
+          10.10.10.10:
+            username: root
+            password: host
+    hosts:
+      - 10.10.10.10
+      - 10.10.10.11
+      - 10.10.11.10
+`))
+	if err != nil {
+		t.Fatalf("config: expected success, but got error: %s", err)
+	}
+	profiles, err := cfg.GetGroupProfiles("rack1")
+	if err != nil {
+		t.Fatalf("config: expected success, but got error: %s", err)
+	}
+	for i, exp := range []*Credentials{
+		{Username: "root", Password: "host"},
+		{Username: "admin", Password: "rack"},
+		{Username: "admin", Password: "default-10.10.11.10"},
+	} {
+		cli, err := NewClientFromProfile(profiles[i])
+		if err != nil {
+			t.Fatalf("FAIL: Test %d: expected to pass, but threw error: %v", i, err)
+		}
+		if cli.username != exp.Username || cli.password != exp.Password {
+			t.Fatalf("FAIL: Test %d: value mismatch: '%s:%s' (actual) vs. '%+v' (expected)", i, cli.username, cli.password, *exp)
+		}
+	}
+
+	source := &CredentialSource{Username: "admin", Password: "secret", PasswordFile: "/etc/idrac.password"}
+	if _, err := source.Provider(); err == nil {
+		t.Fatalf("credentials: expected failure due to conflicting password sources, but succeeded")
+	}
+	source = &CredentialSource{Username: "admin", Password: "secret", Hosts: map[string]*CredentialSource{
+		"10.10.10.10": {Username: "root"},
+	}}
+	if _, err := source.Provider(); err == nil {
+		t.Fatalf("credentials: expected failure due to host override without password source, but succeeded")
+	}
+}
@@ -0,0 +1,428 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"reflect"
+	"strings"
+)
+
+// The types of directory services.
+const (
+	DirectoryServiceLDAP            = "ldap"
+	DirectoryServiceActiveDirectory = "active_directory"
+)
+
+// directoryServiceProperties maps the types of directory services to
+// the properties of AccountService and the prefixes of iDRAC attributes.
+var directoryServiceProperties = map[string][2]string{
+	DirectoryServiceLDAP:            {"LDAP", "LDAP.1."},
+	DirectoryServiceActiveDirectory: {"ActiveDirectory", "ActiveDirectory.1."},
+}
+
+type directoryServiceResponse struct {
+	ServiceEnabled   *bool
+	ServiceAddresses []string
+	Authentication   *struct {
+		AuthenticationType string
+		Username           string
+	}
+	LDAPService *struct {
+		SearchSettings *struct {
+			BaseDistinguishedNames []string
+			UsernameAttribute      string
+			GroupNameAttribute     string
+			GroupsAttribute        string
+		}
+	}
+	RemoteRoleMapping []*struct {
+		LocalRole   string
+		RemoteGroup string
+	}
+}
+
+type directoryServicesResponse struct {
+	LDAP            *directoryServiceResponse
+	ActiveDirectory *directoryServiceResponse
+}
+
+// DirectoryServices holds the configuration of LDAP and Active Directory
+// services.
+type DirectoryServices struct {
+	LDAP            *DirectoryService `yaml:"ldap" json:"ldap" xml:"ldap"`
+	ActiveDirectory *DirectoryService `yaml:"active_directory" json:"active_directory" xml:"active_directory"`
+}
+
+// DirectoryService is the configuration of LDAP or Active Directory service.
+// In the changes of the configuration, the nil fields and the empty strings
+// remain unchanged, while the empty lists clear the values.
+type DirectoryService struct {
+	ServiceEnabled        *bool                    `yaml:"service_enabled" json:"service_enabled" xml:"service_enabled"`
+	ServiceAddresses      []string                 `yaml:"service_addresses" json:"service_addresses" xml:"service_addresses"`
+	Authentication        *DirectoryAuthentication `yaml:"authentication" json:"authentication" xml:"authentication"`
+	SearchSettings        *DirectorySearchSettings `yaml:"search_settings" json:"search_settings" xml:"search_settings"`
+	RemoteRoleMapping     []*RemoteRoleMapping     `yaml:"remote_role_mapping" json:"remote_role_mapping" xml:"remote_role_mapping"`
+	CertificateValidation *bool                    `yaml:"certificate_validation" json:"certificate_validation" xml:"certificate_validation"`
+}
+
+// DirectoryAuthentication holds the bind settings of a directory service.
+// The password is write-only, i.e. the service never returns it.
+type DirectoryAuthentication struct {
+	AuthenticationType string `yaml:"authentication_type" json:"authentication_type" xml:"authentication_type"`
+	Username           string `yaml:"username" json:"username" xml:"username"`
+	Password           string `yaml:"-" json:"-" xml:"-"`
+}
+
+// DirectorySearchSettings holds the settings of the searches of users and
+// groups in a directory service.
+type DirectorySearchSettings struct {
+	BaseDistinguishedNames []string `yaml:"base_distinguished_names" json:"base_distinguished_names" xml:"base_distinguished_names"`
+	UsernameAttribute      string   `yaml:"username_attribute" json:"username_attribute" xml:"username_attribute"`
+	GroupNameAttribute     string   `yaml:"group_name_attribute" json:"group_name_attribute" xml:"group_name_attribute"`
+	GroupsAttribute        string   `yaml:"groups_attribute" json:"groups_attribute" xml:"groups_attribute"`
+}
+
+// RemoteRoleMapping maps a group of a directory service to a role, e.g.
+// Administrator.
+type RemoteRoleMapping struct {
+	LocalRole   string `yaml:"local_role" json:"local_role" xml:"local_role"`
+	RemoteGroup string `yaml:"remote_group" json:"remote_group" xml:"remote_group"`
+}
+
+// DirectoryServiceChange is a difference between the live and the desired
+// configuration of a directory service.
+type DirectoryServiceChange struct {
+	Service string `yaml:"service" json:"service" xml:"service"`
+	Field   string `yaml:"field" json:"field" xml:"field"`
+	Current string `yaml:"current" json:"current" xml:"current"`
+	Desired string `yaml:"desired" json:"desired" xml:"desired"`
+}
+
+// GetDirectoryServices returns the configuration of LDAP and Active
+// Directory services. The certificate validation settings come from iDRAC
+// attributes, and remain nil when the attributes are unavailable.
+func (cli *Client) GetDirectoryServices() (*DirectoryServices, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"AccountService", []byte{})
+	if err != nil {
+		return nil, err
+	}
+	services, err := newDirectoryServicesFromBytes(resp)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := cli.getAttributes(cli.rootPath + managerAttributesPath)
+	if err != nil {
+		log.Debugf("failed fetching iDRAC attributes: %s", err)
+		return services, nil
+	}
+	for serviceType, service := range map[string]*DirectoryService{
+		DirectoryServiceLDAP:            services.LDAP,
+		DirectoryServiceActiveDirectory: services.ActiveDirectory,
+	} {
+		if service == nil {
+			continue
+		}
+		v, exists := attributes[directoryServiceProperties[serviceType][1]+"CertValidationEnable"]
+		if !exists {
+			continue
+		}
+		enabled := v == "Enabled"
+		service.CertificateValidation = &enabled
+	}
+	return services, nil
+}
+
+// UpdateDirectoryService changes the configuration of a directory service,
+// i.e. ldap or active_directory.
+func (cli *Client) UpdateDirectoryService(serviceType string, changes *DirectoryService) error {
+	properties, exists := directoryServiceProperties[serviceType]
+	if !exists {
+		return fmt.Errorf("unsupported directory service %q", serviceType)
+	}
+	patch := make(map[string]interface{})
+	if changes.ServiceEnabled != nil {
+		patch["ServiceEnabled"] = *changes.ServiceEnabled
+	}
+	if changes.ServiceAddresses != nil {
+		patch["ServiceAddresses"] = changes.ServiceAddresses
+	}
+	if changes.Authentication != nil {
+		authentication := make(map[string]interface{})
+		if changes.Authentication.AuthenticationType != "" {
+			authentication["AuthenticationType"] = changes.Authentication.AuthenticationType
+		}
+		if changes.Authentication.Username != "" {
+			authentication["Username"] = changes.Authentication.Username
+		}
+		if changes.Authentication.Password != "" {
+			authentication["Password"] = changes.Authentication.Password
+		}
+		if len(authentication) > 0 {
+			patch["Authentication"] = authentication
+		}
+	}
+	if changes.SearchSettings != nil {
+		settings := make(map[string]interface{})
+		if changes.SearchSettings.BaseDistinguishedNames != nil {
+			settings["BaseDistinguishedNames"] = changes.SearchSettings.BaseDistinguishedNames
+		}
+		for k, v := range map[string]string{
+			"UsernameAttribute":  changes.SearchSettings.UsernameAttribute,
+			"GroupNameAttribute": changes.SearchSettings.GroupNameAttribute,
+			"GroupsAttribute":    changes.SearchSettings.GroupsAttribute,
+		} {
+			if v != "" {
+				settings[k] = v
+			}
+		}
+		if len(settings) > 0 {
+			patch["LDAPService"] = map[string]interface{}{
+				"SearchSettings": settings,
+			}
+		}
+	}
+	if changes.RemoteRoleMapping != nil {
+		mappings := []map[string]string{}
+		for _, mapping := range changes.RemoteRoleMapping {
+			mappings = append(mappings, map[string]string{
+				"LocalRole":   mapping.LocalRole,
+				"RemoteGroup": mapping.RemoteGroup,
+			})
+		}
+		patch["RemoteRoleMapping"] = mappings
+	}
+	if len(patch) == 0 && changes.CertificateValidation == nil {
+		return fmt.Errorf("no %s changes", serviceType)
+	}
+	if len(patch) > 0 {
+		if _, err := cli.patchResource(cli.rootPath+"AccountService", map[string]interface{}{
+			properties[0]: patch,
+		}); err != nil {
+			return err
+		}
+	}
+	if changes.CertificateValidation != nil {
+		value := "Disabled"
+		if *changes.CertificateValidation {
+			value = "Enabled"
+		}
+		return cli.setAttributes(cli.rootPath+managerAttributesPath, map[string]interface{}{
+			properties[1] + "CertValidationEnable": value,
+		})
+	}
+	return nil
+}
+
+// DiffDirectoryService returns the differences between the live and the
+// desired configuration of a directory service. The bind password is
+// write-only, i.e. it is reported and applied along with the other changes,
+// or when rotated. The result, when applied, holds the desired settings
+// differing from the live ones.
+func DiffDirectoryService(serviceType string, current, desired *DirectoryService, rotatePassword bool) ([]*DirectoryServiceChange, *DirectoryService) {
+	changes := []*DirectoryServiceChange{}
+	apply := &DirectoryService{}
+	if desired == nil {
+		return changes, apply
+	}
+	if current == nil {
+		current = &DirectoryService{}
+	}
+	add := func(field string, currentValue, desiredValue interface{}) {
+		changes = append(changes, &DirectoryServiceChange{
+			Service: serviceType,
+			Field:   field,
+			Current: formatDirectoryValue(currentValue),
+			Desired: formatDirectoryValue(desiredValue),
+		})
+	}
+
+	if desired.ServiceEnabled != nil && !reflect.DeepEqual(current.ServiceEnabled, desired.ServiceEnabled) {
+		add("service_enabled", current.ServiceEnabled, desired.ServiceEnabled)
+		apply.ServiceEnabled = desired.ServiceEnabled
+	}
+	if desired.ServiceAddresses != nil && !equalStrings(trimEmpty(current.ServiceAddresses), trimEmpty(desired.ServiceAddresses)) {
+		add("service_addresses", trimEmpty(current.ServiceAddresses), trimEmpty(desired.ServiceAddresses))
+		apply.ServiceAddresses = desired.ServiceAddresses
+	}
+	if desired.Authentication != nil {
+		currentAuth := current.Authentication
+		if currentAuth == nil {
+			currentAuth = &DirectoryAuthentication{}
+		}
+		auth := &DirectoryAuthentication{}
+		if desired.Authentication.AuthenticationType != "" && desired.Authentication.AuthenticationType != currentAuth.AuthenticationType {
+			add("authentication.authentication_type", currentAuth.AuthenticationType, desired.Authentication.AuthenticationType)
+			auth.AuthenticationType = desired.Authentication.AuthenticationType
+		}
+		if desired.Authentication.Username != "" && desired.Authentication.Username != currentAuth.Username {
+			add("authentication.username", currentAuth.Username, desired.Authentication.Username)
+			auth.Username = desired.Authentication.Username
+		}
+		if *auth != (DirectoryAuthentication{}) {
+			apply.Authentication = auth
+		}
+	}
+	if desired.SearchSettings != nil {
+		currentSearch := current.SearchSettings
+		if currentSearch == nil {
+			currentSearch = &DirectorySearchSettings{}
+		}
+		search := &DirectorySearchSettings{}
+		changed := false
+		if desired.SearchSettings.BaseDistinguishedNames != nil &&
+			!equalStrings(trimEmpty(currentSearch.BaseDistinguishedNames), trimEmpty(desired.SearchSettings.BaseDistinguishedNames)) {
+			add("search_settings.base_distinguished_names", trimEmpty(currentSearch.BaseDistinguishedNames), trimEmpty(desired.SearchSettings.BaseDistinguishedNames))
+			search.BaseDistinguishedNames = desired.SearchSettings.BaseDistinguishedNames
+			changed = true
+		}
+		for _, entry := range []struct {
+			field   string
+			current string
+			desired string
+			target  *string
+		}{
+			{"search_settings.username_attribute", currentSearch.UsernameAttribute, desired.SearchSettings.UsernameAttribute, &search.UsernameAttribute},
+			{"search_settings.group_name_attribute", currentSearch.GroupNameAttribute, desired.SearchSettings.GroupNameAttribute, &search.GroupNameAttribute},
+			{"search_settings.groups_attribute", currentSearch.GroupsAttribute, desired.SearchSettings.GroupsAttribute, &search.GroupsAttribute},
+		} {
+			if entry.desired != "" && entry.desired != entry.current {
+				add(entry.field, entry.current, entry.desired)
+				*entry.target = entry.desired
+				changed = true
+			}
+		}
+		if changed {
+			apply.SearchSettings = search
+		}
+	}
+	if desired.RemoteRoleMapping != nil && !equalRoleMappings(current.RemoteRoleMapping, desired.RemoteRoleMapping) {
+		add("remote_role_mapping", current.RemoteRoleMapping, desired.RemoteRoleMapping)
+		apply.RemoteRoleMapping = desired.RemoteRoleMapping
+	}
+	if desired.CertificateValidation != nil && !reflect.DeepEqual(current.CertificateValidation, desired.CertificateValidation) {
+		add("certificate_validation", current.CertificateValidation, desired.CertificateValidation)
+		apply.CertificateValidation = desired.CertificateValidation
+	}
+	if desired.Authentication != nil && desired.Authentication.Password != "" && (rotatePassword || len(changes) > 0) {
+		changes = append(changes, &DirectoryServiceChange{
+			Service: serviceType,
+			Field:   "authentication.password",
+			Current: "(write-only)",
+			Desired: "(set)",
+		})
+		if apply.Authentication == nil {
+			apply.Authentication = &DirectoryAuthentication{}
+		}
+		apply.Authentication.Password = desired.Authentication.Password
+	}
+	return changes, apply
+}
+
+func formatDirectoryValue(v interface{}) string {
+	switch value := v.(type) {
+	case *bool:
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprintf("%t", *value)
+	case []string:
+		return strings.Join(value, ",")
+	case []*RemoteRoleMapping:
+		mappings := []string{}
+		for _, mapping := range value {
+			if mapping.RemoteGroup == "" && mapping.LocalRole == "" {
+				continue
+			}
+			mappings = append(mappings, mapping.RemoteGroup+"="+mapping.LocalRole)
+		}
+		return strings.Join(mappings, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// trimEmpty returns the non-empty values, e.g. iDRAC reports unused slots
+// of service addresses as empty strings.
+func trimEmpty(values []string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalRoleMappings(a, b []*RemoteRoleMapping) bool {
+	return formatDirectoryValue(a) == formatDirectoryValue(b)
+}
+
+// newDirectoryServicesFromBytes returns DirectoryServices instance from
+// an input byte array, i.e. AccountService resource.
+func newDirectoryServicesFromBytes(s []byte) (*DirectoryServices, error) {
+	response := &directoryServicesResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	return &DirectoryServices{
+		LDAP:            newDirectoryService(response.LDAP),
+		ActiveDirectory: newDirectoryService(response.ActiveDirectory),
+	}, nil
+}
+
+func newDirectoryService(response *directoryServiceResponse) *DirectoryService {
+	if response == nil {
+		return nil
+	}
+	service := &DirectoryService{
+		ServiceEnabled:    response.ServiceEnabled,
+		ServiceAddresses:  response.ServiceAddresses,
+		RemoteRoleMapping: []*RemoteRoleMapping{},
+	}
+	if service.ServiceAddresses == nil {
+		service.ServiceAddresses = []string{}
+	}
+	if response.Authentication != nil {
+		service.Authentication = &DirectoryAuthentication{
+			AuthenticationType: response.Authentication.AuthenticationType,
+			Username:           response.Authentication.Username,
+		}
+	}
+	if response.LDAPService != nil && response.LDAPService.SearchSettings != nil {
+		settings := response.LDAPService.SearchSettings
+		service.SearchSettings = &DirectorySearchSettings{
+			BaseDistinguishedNames: settings.BaseDistinguishedNames,
+			UsernameAttribute:      settings.UsernameAttribute,
+			GroupNameAttribute:     settings.GroupNameAttribute,
+			GroupsAttribute:        settings.GroupsAttribute,
+		}
+		if service.SearchSettings.BaseDistinguishedNames == nil {
+			service.SearchSettings.BaseDistinguishedNames = []string{}
+		}
+	}
+	for _, mapping := range response.RemoteRoleMapping {
+		if mapping == nil {
+			continue
+		}
+		service.RemoteRoleMapping = append(service.RemoteRoleMapping, &RemoteRoleMapping{
+			LocalRole:   mapping.LocalRole,
+			RemoteGroup: mapping.RemoteGroup,
+		})
+	}
+	return service
+}
@@ -0,0 +1,224 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseDirectoryServicesJsonOutput(t *testing.T) {
+	content, err := ioutil.ReadFile("../../assets/responses/account_service_1.json")
+	if err != nil {
+		t.Fatalf("failed reading account_service_1.json: %s", err)
+	}
+	services, err := newDirectoryServicesFromBytes(content)
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	disabled := false
+	exp := &DirectoryService{
+		ServiceEnabled:   &disabled,
+		ServiceAddresses: []string{"ldap.example.com"},
+		Authentication: &DirectoryAuthentication{
+			AuthenticationType: "UsernameAndPassword",
+			Username:           "cn=idrac,ou=services,dc=example,dc=com",
+		},
+		SearchSettings: &DirectorySearchSettings{
+			BaseDistinguishedNames: []string{"dc=example,dc=com"},
+			UsernameAttribute:      "uid",
+			GroupNameAttribute:     "memberUid",
+		},
+		RemoteRoleMapping: []*RemoteRoleMapping{
+			{LocalRole: "Administrator", RemoteGroup: "cn=idrac-admins,ou=groups,dc=example,dc=com"},
+		},
+	}
+	if !reflect.DeepEqual(services.LDAP, exp) {
+		t.Fatalf("value mismatch: '%+v' (actual) vs. '%+v' (expected)", *services.LDAP, *exp)
+	}
+	if services.ActiveDirectory.SearchSettings != nil || len(services.ActiveDirectory.RemoteRoleMapping) != 2 {
+		t.Fatalf("unexpected active directory settings: %+v", *services.ActiveDirectory)
+	}
+	for _, resource := range []interface{}{services, services.LDAP, services.LDAP.SearchSettings, services.LDAP.RemoteRoleMapping[0]} {
+		complianceMessages, compliant := isStructCompliant(resource)
+		if !compliant {
+			t.Fatalf("struct is not compliant: %v", complianceMessages)
+		}
+	}
+}
+
+func TestDiffDirectoryService(t *testing.T) {
+	enabled, disabled := true, false
+	current := &DirectoryService{
+		ServiceEnabled:   &disabled,
+		ServiceAddresses: []string{"dc1.example.com", ""},
+		Authentication: &DirectoryAuthentication{
+			AuthenticationType: "UsernameAndPassword",
+			Username:           "cn=idrac",
+		},
+		SearchSettings: &DirectorySearchSettings{
+			BaseDistinguishedNames: []string{"dc=example,dc=com"},
+			UsernameAttribute:      "uid",
+		},
+		RemoteRoleMapping: []*RemoteRoleMapping{
+			{LocalRole: "Administrator", RemoteGroup: "admins"},
+		},
+		CertificateValidation: &enabled,
+	}
+
+	testFailed := 0
+	for i, test := range []struct {
+		desired *DirectoryService
+		rotate  bool
+		fields  []string
+		apply   *DirectoryService
+	}{
+		{
+			// The empty slots of service addresses are ignored.
+			desired: &DirectoryService{
+				ServiceEnabled:        &disabled,
+				ServiceAddresses:      []string{"dc1.example.com"},
+				CertificateValidation: &enabled,
+				SearchSettings:        &DirectorySearchSettings{UsernameAttribute: "uid"},
+			},
+			fields: []string{},
+			apply:  &DirectoryService{},
+		},
+		{
+			desired: &DirectoryService{
+				ServiceEnabled:   &enabled,
+				ServiceAddresses: []string{"dc1.example.com", "dc2.example.com"},
+				Authentication: &DirectoryAuthentication{
+					Username: "cn=idrac",
+					Password: "bindsecret",
+				},
+				SearchSettings: &DirectorySearchSettings{
+					GroupNameAttribute: "memberUid",
+				},
+				RemoteRoleMapping: []*RemoteRoleMapping{
+					{LocalRole: "Administrator", RemoteGroup: "admins"},
+					{LocalRole: "ReadOnly", RemoteGroup: "viewers"},
+				},
+				CertificateValidation: &disabled,
+			},
+			fields: []string{
+				"service_enabled",
+				"service_addresses",
+				"search_settings.group_name_attribute",
+				"remote_role_mapping",
+				"certificate_validation",
+				"authentication.password",
+			},
+			apply: &DirectoryService{
+				ServiceEnabled:   &enabled,
+				ServiceAddresses: []string{"dc1.example.com", "dc2.example.com"},
+				Authentication: &DirectoryAuthentication{
+					Password: "bindsecret",
+				},
+				SearchSettings: &DirectorySearchSettings{
+					GroupNameAttribute: "memberUid",
+				},
+				RemoteRoleMapping: []*RemoteRoleMapping{
+					{LocalRole: "Administrator", RemoteGroup: "admins"},
+					{LocalRole: "ReadOnly", RemoteGroup: "viewers"},
+				},
+				CertificateValidation: &disabled,
+			},
+		},
+		{
+			// The write-only bind password alone is not a change.
+			desired: &DirectoryService{
+				ServiceEnabled: &disabled,
+				Authentication: &DirectoryAuthentication{
+					Username: "cn=idrac",
+					Password: "bindsecret",
+				},
+			},
+			fields: []string{},
+			apply:  &DirectoryService{},
+		},
+		{
+			desired: &DirectoryService{
+				ServiceEnabled: &disabled,
+				Authentication: &DirectoryAuthentication{
+					Username: "cn=idrac",
+					Password: "bindsecret",
+				},
+			},
+			rotate: true,
+			fields: []string{"authentication.password"},
+			apply: &DirectoryService{
+				Authentication: &DirectoryAuthentication{
+					Password: "bindsecret",
+				},
+			},
+		},
+	} {
+		changes, apply := DiffDirectoryService(DirectoryServiceLDAP, current, test.desired, test.rotate)
+		fields := []string{}
+		for _, change := range changes {
+			fields = append(fields, change.Field)
+			if change.Field == "authentication.password" && change.Desired == "bindsecret" {
+				t.Logf("FAIL: Test %d: the difference reveals the password", i)
+				testFailed++
+			}
+		}
+		if !reflect.DeepEqual(fields, test.fields) {
+			t.Logf("FAIL: Test %d: changed fields mismatch: %v (actual) vs. %v (expected)", i, fields, test.fields)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(apply, test.apply) {
+			t.Logf("FAIL: Test %d: changes mismatch: '%+v' (actual) vs. '%+v' (expected)", i, *apply, *test.apply)
+			testFailed++
+			continue
+		}
+		t.Logf("PASS: Test %d: changes: %v", i, fields)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestUpdateDirectoryService(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	services, err := cli.GetDirectoryServices()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if services.LDAP.CertificateValidation == nil || *services.LDAP.CertificateValidation {
+		t.Fatalf("client: expected LDAP certificate validation disabled, got: %v", services.LDAP.CertificateValidation)
+	}
+	if services.ActiveDirectory.CertificateValidation == nil || !*services.ActiveDirectory.CertificateValidation {
+		t.Fatalf("client: expected Active Directory certificate validation enabled, got: %v", services.ActiveDirectory.CertificateValidation)
+	}
+
+	enabled := true
+	if err := cli.UpdateDirectoryService(DirectoryServiceLDAP, &DirectoryService{
+		ServiceEnabled:        &enabled,
+		SearchSettings:        &DirectorySearchSettings{UsernameAttribute: "sAMAccountName"},
+		CertificateValidation: &enabled,
+	}); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.UpdateDirectoryService(DirectoryServiceLDAP, &DirectoryService{}); err == nil {
+		t.Fatalf("client: expected failure due to no changes, but got non-error response")
+	}
+	if err := cli.UpdateDirectoryService("nis", &DirectoryService{ServiceEnabled: &enabled}); err == nil {
+		t.Fatalf("client: expected failure due to unsupported service, but got non-error response")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type eventResponse struct {
+	ODataAnnotation
+	ID      string `json:"Id"`
+	Name    string
+	Context string
+	Events  []struct {
+		EventID           string `json:"EventId"`
+		EventType         string
+		EventTimestamp    string
+		Severity          string
+		Message           string
+		MessageID         string `json:"MessageId"`
+		MessageArgs       []string
+		OriginOfCondition ODataAnnotation
+		MemberID          string `json:"MemberId"`
+	}
+}
+
+// Event represents an instance of Redfish Event resource, i.e. the event
+// records iDRAC delivers to a subscriber at once. The host, the profile,
+// and the time of receipt are set by the receiver of the event.
+type Event struct {
+	ID         string           `yaml:"id" json:"id" xml:"id"`
+	OData      *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name       string           `yaml:"name" json:"name" xml:"name"`
+	Context    string           `yaml:"context" json:"context" xml:"context"`
+	Host       string           `yaml:"host" json:"host" xml:"host"`
+	Profile    string           `yaml:"profile" json:"profile" xml:"profile"`
+	ReceivedAt time.Time        `yaml:"received_at" json:"received_at" xml:"received_at"`
+	Events     []*EventRecord   `yaml:"events" json:"events" xml:"events"`
+}
+
+// EventRecord is a record of Redfish Event resource, e.g. an alert. The
+// events carry no resolution, i.e. it comes from message registries.
+type EventRecord struct {
+	EventID           string   `yaml:"event_id" json:"event_id" xml:"event_id"`
+	EventType         string   `yaml:"event_type" json:"event_type" xml:"event_type"`
+	EventTimestamp    string   `yaml:"event_timestamp" json:"event_timestamp" xml:"event_timestamp"`
+	Severity          string   `yaml:"severity" json:"severity" xml:"severity"`
+	Message           string   `yaml:"message" json:"message" xml:"message"`
+	MessageID         string   `yaml:"message_id" json:"message_id" xml:"message_id"`
+	MessageArgs       []string `yaml:"message_args" json:"message_args" xml:"message_args"`
+	Resolution        string   `yaml:"resolution" json:"resolution" xml:"resolution"`
+	OriginOfCondition string   `yaml:"origin_of_condition" json:"origin_of_condition" xml:"origin_of_condition"`
+	MemberID          string   `yaml:"member_id" json:"member_id" xml:"member_id"`
+}
+
+// NewEventFromString returns Event instance from an input string.
+func NewEventFromString(s string) (*Event, error) {
+	return NewEventFromBytes([]byte(s))
+}
+
+// NewEventFromBytes returns Event instance from an input byte array, e.g.
+// the body of the request delivering the event to a subscriber.
+func NewEventFromBytes(s []byte) (*Event, error) {
+	response := &eventResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if len(response.Events) == 0 {
+		return nil, fmt.Errorf("parsing error: the event has no records, server response: %s", string(s[:]))
+	}
+	event := &Event{
+		ID:      response.ID,
+		Name:    response.Name,
+		Context: response.Context,
+		Events:  []*EventRecord{},
+	}
+	for _, entry := range response.Events {
+		record := &EventRecord{
+			EventID:           entry.EventID,
+			EventType:         entry.EventType,
+			EventTimestamp:    entry.EventTimestamp,
+			Severity:          entry.Severity,
+			Message:           entry.Message,
+			MessageID:         entry.MessageID,
+			MessageArgs:       nonNilStrings(entry.MessageArgs),
+			OriginOfCondition: entry.OriginOfCondition.ID,
+			MemberID:          entry.MemberID,
+		}
+		event.Events = append(event.Events, record)
+	}
+	event.OData = &ODataAnnotation{
+		Context: response.ODataAnnotation.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return event, nil
+}
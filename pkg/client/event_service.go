@@ -0,0 +1,315 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DefaultEventProtocol is the protocol of event subscriptions.
+const DefaultEventProtocol = "Redfish"
+
+type eventServiceResponse struct {
+	ODataAnnotation
+	ID                           string `json:"Id"`
+	Name                         string
+	Description                  string
+	ServiceEnabled               bool
+	DeliveryRetryAttempts        uint64
+	DeliveryRetryIntervalSeconds uint64
+	EventFormatTypes             []string
+	EventTypesForSubscription    []string
+	RegistryPrefixes             []string
+	ResourceTypes                []string
+	ServerSentEventURI           string `json:"ServerSentEventUri"`
+	Status                       HealthStatus
+	Subscriptions                ODataAnnotation
+	Actions                      struct {
+		SubmitTestEvent struct {
+			Target string `json:"target"`
+		} `json:"#EventService.SubmitTestEvent"`
+	}
+}
+
+// EventService represents an instance of Redfish EventService resource,
+// i.e. the event types and the delivery of events to subscribers.
+type EventService struct {
+	ID                           string           `yaml:"id" json:"id" xml:"id"`
+	OData                        *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name                         string           `yaml:"name" json:"name" xml:"name"`
+	Description                  string           `yaml:"description" json:"description" xml:"description"`
+	ServiceEnabled               bool             `yaml:"service_enabled" json:"service_enabled" xml:"service_enabled"`
+	DeliveryRetryAttempts        uint64           `yaml:"delivery_retry_attempts" json:"delivery_retry_attempts" xml:"delivery_retry_attempts"`
+	DeliveryRetryIntervalSeconds uint64           `yaml:"delivery_retry_interval_seconds" json:"delivery_retry_interval_seconds" xml:"delivery_retry_interval_seconds"`
+	EventFormatTypes             []string         `yaml:"event_format_types" json:"event_format_types" xml:"event_format_types"`
+	EventTypesForSubscription    []string         `yaml:"event_types_for_subscription" json:"event_types_for_subscription" xml:"event_types_for_subscription"`
+	RegistryPrefixes             []string         `yaml:"registry_prefixes" json:"registry_prefixes" xml:"registry_prefixes"`
+	ResourceTypes                []string         `yaml:"resource_types" json:"resource_types" xml:"resource_types"`
+	ServerSentEventURI           string           `yaml:"server_sent_event_uri" json:"server_sent_event_uri" xml:"server_sent_event_uri"`
+	SubscriptionsURI             string           `yaml:"subscriptions_uri" json:"subscriptions_uri" xml:"subscriptions_uri"`
+	SubmitTestEventURI           string           `yaml:"submit_test_event_uri" json:"submit_test_event_uri" xml:"submit_test_event_uri"`
+	Status                       HealthStatus     `yaml:"status" json:"status" xml:"status"`
+}
+
+type eventSubscriptionResponse struct {
+	ODataAnnotation
+	ID                  string `json:"Id"`
+	Name                string
+	Description         string
+	Destination         string
+	Context             string
+	Protocol            string
+	SubscriptionType    string
+	EventFormatType     string
+	DeliveryRetryPolicy string
+	EventTypes          []string
+	MessageIDs          []string `json:"MessageIds"`
+	RegistryPrefixes    []string
+	ResourceTypes       []string
+	OriginResources     []ODataAnnotation
+	Status              HealthStatus
+}
+
+// EventSubscription represents an instance of Redfish EventDestination
+// resource, i.e. a subscriber receiving events from iDRAC.
+type EventSubscription struct {
+	ID                  string           `yaml:"id" json:"id" xml:"id"`
+	OData               *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name                string           `yaml:"name" json:"name" xml:"name"`
+	Description         string           `yaml:"description" json:"description" xml:"description"`
+	Destination         string           `yaml:"destination" json:"destination" xml:"destination"`
+	Context             string           `yaml:"context" json:"context" xml:"context"`
+	Protocol            string           `yaml:"protocol" json:"protocol" xml:"protocol"`
+	SubscriptionType    string           `yaml:"subscription_type" json:"subscription_type" xml:"subscription_type"`
+	EventFormatType     string           `yaml:"event_format_type" json:"event_format_type" xml:"event_format_type"`
+	DeliveryRetryPolicy string           `yaml:"delivery_retry_policy" json:"delivery_retry_policy" xml:"delivery_retry_policy"`
+	EventTypes          []string         `yaml:"event_types" json:"event_types" xml:"event_types"`
+	MessageIDs          []string         `yaml:"message_ids" json:"message_ids" xml:"message_ids"`
+	RegistryPrefixes    []string         `yaml:"registry_prefixes" json:"registry_prefixes" xml:"registry_prefixes"`
+	ResourceTypes       []string         `yaml:"resource_types" json:"resource_types" xml:"resource_types"`
+	OriginResources     []string         `yaml:"origin_resources" json:"origin_resources" xml:"origin_resources"`
+	Status              HealthStatus     `yaml:"status" json:"status" xml:"status"`
+}
+
+// EventSubscriptionRequest holds the properties of a new event
+// subscription. The empty filters match all events. The protocol defaults
+// to DefaultEventProtocol.
+type EventSubscriptionRequest struct {
+	Destination      string   `yaml:"destination" json:"destination" xml:"destination"`
+	EventTypes       []string `yaml:"event_types" json:"event_types" xml:"event_types"`
+	MessageIDs       []string `yaml:"message_ids" json:"message_ids" xml:"message_ids"`
+	RegistryPrefixes []string `yaml:"registry_prefixes" json:"registry_prefixes" xml:"registry_prefixes"`
+	ResourceTypes    []string `yaml:"resource_types" json:"resource_types" xml:"resource_types"`
+	Context          string   `yaml:"context" json:"context" xml:"context"`
+	Protocol         string   `yaml:"protocol" json:"protocol" xml:"protocol"`
+}
+
+// GetEventService returns an instance of Redfish EventService resource.
+func (cli *Client) GetEventService() (*EventService, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"EventService", []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newEventServiceFromBytes(resp)
+}
+
+// ListEventSubscriptions returns the event subscriptions.
+func (cli *Client) ListEventSubscriptions() ([]*EventSubscription, error) {
+	members, err := cli.getCollectionMembers(cli.rootPath + "EventService/Subscriptions")
+	if err != nil {
+		return nil, err
+	}
+	subscriptions := []*EventSubscription{}
+	for _, member := range members {
+		resp, err := cli.callAPI("GET", "", member, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		subscription, err := newEventSubscriptionFromBytes(resp)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+// GetEventSubscription returns an instance of Redfish EventDestination
+// resource.
+func (cli *Client) GetEventSubscription(subscriptionID string) (*EventSubscription, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"EventService/Subscriptions/"+subscriptionID, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newEventSubscriptionFromBytes(resp)
+}
+
+// CreateEventSubscription subscribes the destination, i.e. an HTTPS URL,
+// to the events matching the filters of the request. It returns the new
+// subscription.
+func (cli *Client) CreateEventSubscription(req *EventSubscriptionRequest) (*EventSubscription, error) {
+	if req.Destination == "" {
+		return nil, fmt.Errorf("empty event destination")
+	}
+	if !strings.HasPrefix(req.Destination, "https://") {
+		return nil, fmt.Errorf("event destination %s is not an HTTPS URL", req.Destination)
+	}
+	protocol := req.Protocol
+	if protocol == "" {
+		protocol = DefaultEventProtocol
+	}
+	properties := map[string]interface{}{
+		"Destination": req.Destination,
+		"Protocol":    protocol,
+		"Context":     req.Context,
+	}
+	for k, v := range map[string][]string{
+		"EventTypes":       req.EventTypes,
+		"MessageIds":       req.MessageIDs,
+		"RegistryPrefixes": req.RegistryPrefixes,
+		"ResourceTypes":    req.ResourceTypes,
+	} {
+		if len(v) > 0 {
+			properties[k] = v
+		}
+	}
+	resp, headers, err := cli.postResource(cli.rootPath+"EventService/Subscriptions", properties)
+	if err != nil {
+		return nil, err
+	}
+	if subscription, err := newEventSubscriptionFromBytes(resp); err == nil {
+		return subscription, nil
+	}
+	// iDRAC responds with a message, and the Location header references
+	// the new subscription.
+	location := headers.Get("Location")
+	if location == "" {
+		return nil, fmt.Errorf("the response to the creation of the event subscription has no location, server response: %s", string(resp))
+	}
+	resp, err = cli.callAPI("GET", "", location, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newEventSubscriptionFromBytes(resp)
+}
+
+// DeleteEventSubscription deletes an event subscription.
+func (cli *Client) DeleteEventSubscription(subscriptionID string) error {
+	if subscriptionID == "" {
+		return fmt.Errorf("empty event subscription id")
+	}
+	_, err := cli.callAPI("DELETE", "", cli.rootPath+"EventService/Subscriptions/"+subscriptionID, []byte{})
+	return err
+}
+
+// SubmitTestEvent requests iDRAC to send a test event, e.g. Alert, with
+// the message id to the subscribers.
+func (cli *Client) SubmitTestEvent(eventType, messageID string) error {
+	svc, err := cli.GetEventService()
+	if err != nil {
+		return err
+	}
+	if svc.SubmitTestEventURI == "" {
+		return fmt.Errorf("the event service does not support test events")
+	}
+	properties := map[string]interface{}{
+		"EventType": eventType,
+	}
+	if messageID != "" {
+		properties["MessageId"] = messageID
+	}
+	_, _, err = cli.postResource(svc.SubmitTestEventURI, properties)
+	return err
+}
+
+// newEventServiceFromString returns EventService instance from an input string.
+func newEventServiceFromString(s string) (*EventService, error) {
+	return newEventServiceFromBytes([]byte(s))
+}
+
+// newEventServiceFromBytes returns EventService instance from an input byte array.
+func newEventServiceFromBytes(s []byte) (*EventService, error) {
+	response := &eventServiceResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the event service is empty, server response: %s", string(s[:]))
+	}
+	svc := &EventService{
+		ID:                           response.ID,
+		Name:                         response.Name,
+		Description:                  response.Description,
+		ServiceEnabled:               response.ServiceEnabled,
+		DeliveryRetryAttempts:        response.DeliveryRetryAttempts,
+		DeliveryRetryIntervalSeconds: response.DeliveryRetryIntervalSeconds,
+		EventFormatTypes:             nonNilStrings(response.EventFormatTypes),
+		EventTypesForSubscription:    nonNilStrings(response.EventTypesForSubscription),
+		RegistryPrefixes:             nonNilStrings(response.RegistryPrefixes),
+		ResourceTypes:                nonNilStrings(response.ResourceTypes),
+		ServerSentEventURI:           response.ServerSentEventURI,
+		SubscriptionsURI:             response.Subscriptions.ID,
+		SubmitTestEventURI:           response.Actions.SubmitTestEvent.Target,
+		Status:                       response.Status,
+	}
+	svc.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return svc, nil
+}
+
+// newEventSubscriptionFromString returns EventSubscription instance from an input string.
+func newEventSubscriptionFromString(s string) (*EventSubscription, error) {
+	return newEventSubscriptionFromBytes([]byte(s))
+}
+
+// newEventSubscriptionFromBytes returns EventSubscription instance from an input byte array.
+func newEventSubscriptionFromBytes(s []byte) (*EventSubscription, error) {
+	response := &eventSubscriptionResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the event subscription is empty, server response: %s", string(s[:]))
+	}
+	subscription := &EventSubscription{
+		ID:                  response.ID,
+		Name:                response.Name,
+		Description:         response.Description,
+		Destination:         response.Destination,
+		Context:             response.Context,
+		Protocol:            response.Protocol,
+		SubscriptionType:    response.SubscriptionType,
+		EventFormatType:     response.EventFormatType,
+		DeliveryRetryPolicy: response.DeliveryRetryPolicy,
+		EventTypes:          nonNilStrings(response.EventTypes),
+		MessageIDs:          nonNilStrings(response.MessageIDs),
+		RegistryPrefixes:    nonNilStrings(response.RegistryPrefixes),
+		ResourceTypes:       nonNilStrings(response.ResourceTypes),
+		OriginResources:     []string{},
+		Status:              response.Status,
+	}
+	for _, resource := range response.OriginResources {
+		subscription.OriginResources = append(subscription.OriginResources, resource.ID)
+	}
+	subscription.OData = &ODataAnnotation{
+		Context: response.ODataAnnotation.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return subscription, nil
+}
+
+// nonNilStrings returns an empty slice in place of nil one.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
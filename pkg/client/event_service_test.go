@@ -0,0 +1,279 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"fmt"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseEventServiceJsonOutput(t *testing.T) {
+	testFailed := 0
+	dataDir := "../../assets/responses"
+	for i, test := range []struct {
+		input      string
+		exp        *EventService
+		shouldFail bool // Whether test should result in a failure
+		shouldErr  bool // Whether parsing of a response should result in error
+	}{
+		{
+			input: "event_service_1",
+			exp: &EventService{
+				ID: "EventService",
+				OData: NewODataAnnotation(
+					"/redfish/v1/EventService",
+					"#EventService.v1_4_0.EventService",
+					"/redfish/v1/$metadata#EventService.EventService",
+				),
+				Name:                         "Event Service",
+				Description:                  "Event Service represents the properties for the service",
+				ServiceEnabled:               true,
+				DeliveryRetryAttempts:        3,
+				DeliveryRetryIntervalSeconds: 5,
+				EventFormatTypes:             []string{"Event", "MetricReport"},
+				EventTypesForSubscription: []string{
+					"StatusChange", "ResourceUpdated", "ResourceAdded",
+					"ResourceRemoved", "Alert", "MetricReport",
+				},
+				RegistryPrefixes:   []string{"EEMI", "TelemetryReport"},
+				ResourceTypes:      []string{},
+				ServerSentEventURI: "/redfish/v1/SSE",
+				SubscriptionsURI:   "/redfish/v1/EventService/Subscriptions",
+				SubmitTestEventURI: "/redfish/v1/EventService/Actions/EventService.SubmitTestEvent",
+				Status: HealthStatus{
+					Health:       "OK",
+					HealthRollup: "OK",
+					State:        "Enabled",
+				},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			input:      "root_2",
+			shouldFail: false,
+			shouldErr:  true,
+		},
+	} {
+		// Read response file
+		fp := fmt.Sprintf("%s/%s.json", dataDir, test.input)
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			t.Logf("FAIL: Test %d: failed reading '%s', error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+
+		// Parse API response
+		svc, err := newEventServiceFromBytes(content)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input '%s', expected to pass, but threw error: %v", i, fp, err)
+				testFailed++
+			} else {
+				t.Logf("PASS: Test %d: input '%s', expected to throw error, threw error: %v", i, fp, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input '%s', expected to throw error, but passed: %v", i, fp, *svc)
+			testFailed++
+			continue
+		}
+
+		svcFromString, err := newEventServiceFromString(string(content))
+		if err != nil {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(svcFromString, svc) {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (newEventServiceFromString) vs. '%v' (newEventServiceFromBytes)",
+				i, fp, *svcFromString, *svc)
+			testFailed++
+			continue
+		}
+
+		if !reflect.DeepEqual(svc, test.exp) && !test.shouldFail {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (actual) vs. '%v' (expected)",
+				i, fp, *svc, *test.exp)
+			testFailed++
+			continue
+		}
+
+		complianceMessages, compliant := isStructCompliant(svc)
+		if !compliant {
+			testFailed++
+			for _, entry := range complianceMessages {
+				t.Logf("%s", entry)
+			}
+			continue
+		}
+
+		t.Logf("PASS: Test %d: input '%s', expected to pass, passed", i, fp)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestParseEventSubscriptionJsonOutput(t *testing.T) {
+	testFailed := 0
+	dataDir := "../../assets/responses"
+	for i, test := range []struct {
+		input      string
+		exp        *EventSubscription
+		shouldFail bool // Whether test should result in a failure
+		shouldErr  bool // Whether parsing of a response should result in error
+	}{
+		{
+			input: "event_subscription_1",
+			exp: &EventSubscription{
+				ID: "c1a71140-ba1d-11e9-842f-d094662a05e6",
+				OData: NewODataAnnotation(
+					"/redfish/v1/EventService/Subscriptions/c1a71140-ba1d-11e9-842f-d094662a05e6",
+					"#EventDestination.v1_6_0.EventDestination",
+					"/redfish/v1/$metadata#EventDestination.EventDestination",
+				),
+				Name:                "EventSubscription c1a71140-ba1d-11e9-842f-d094662a05e6",
+				Description:         "Event Subscription Details",
+				Destination:         "https://192.168.10.20:8443/events",
+				Context:             "fleet-events",
+				Protocol:            "Redfish",
+				SubscriptionType:    "RedfishEvent",
+				EventFormatType:     "Event",
+				DeliveryRetryPolicy: "RetryForever",
+				EventTypes:          []string{"Alert", "StatusChange"},
+				MessageIDs:          []string{},
+				RegistryPrefixes:    []string{},
+				ResourceTypes:       []string{},
+				OriginResources:     []string{},
+				Status: HealthStatus{
+					Health:       "OK",
+					HealthRollup: "OK",
+					State:        "Enabled",
+				},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			input:      "success_1",
+			shouldFail: false,
+			shouldErr:  true,
+		},
+	} {
+		// Read response file
+		fp := fmt.Sprintf("%s/%s.json", dataDir, test.input)
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			t.Logf("FAIL: Test %d: failed reading '%s', error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+
+		// Parse API response
+		subscription, err := newEventSubscriptionFromBytes(content)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input '%s', expected to pass, but threw error: %v", i, fp, err)
+				testFailed++
+			} else {
+				t.Logf("PASS: Test %d: input '%s', expected to throw error, threw error: %v", i, fp, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input '%s', expected to throw error, but passed: %v", i, fp, *subscription)
+			testFailed++
+			continue
+		}
+
+		subscriptionFromString, err := newEventSubscriptionFromString(string(content))
+		if err != nil {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(subscriptionFromString, subscription) {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (newEventSubscriptionFromString) vs. '%v' (newEventSubscriptionFromBytes)",
+				i, fp, *subscriptionFromString, *subscription)
+			testFailed++
+			continue
+		}
+
+		if !reflect.DeepEqual(subscription, test.exp) && !test.shouldFail {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (actual) vs. '%v' (expected)",
+				i, fp, *subscription, *test.exp)
+			testFailed++
+			continue
+		}
+
+		complianceMessages, compliant := isStructCompliant(subscription)
+		if !compliant {
+			testFailed++
+			for _, entry := range complianceMessages {
+				t.Logf("%s", entry)
+			}
+			continue
+		}
+
+		t.Logf("PASS: Test %d: input '%s', expected to pass, passed", i, fp)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestManageEventSubscriptions(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	subscriptions, err := cli.ListEventSubscriptions()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(subscriptions) != 1 {
+		t.Fatalf("client: expected 1 event subscription, but got %d", len(subscriptions))
+	}
+
+	subscription, err := cli.CreateEventSubscription(&EventSubscriptionRequest{
+		Destination: "https://192.168.10.20:8443/events",
+		EventTypes:  []string{"Alert", "StatusChange"},
+		Context:     "fleet-events",
+	})
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if subscription.ID != "c1a71140-ba1d-11e9-842f-d094662a05e6" {
+		t.Fatalf("client: unexpected event subscription: %+v", *subscription)
+	}
+	for _, destination := range []string{"", "http://192.168.10.20/events"} {
+		if _, err := cli.CreateEventSubscription(&EventSubscriptionRequest{Destination: destination}); err == nil {
+			t.Fatalf("client: expected failure due to destination %q, but got non-error response", destination)
+		}
+	}
+
+	if err := cli.SubmitTestEvent("Alert", "TMP0118"); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.DeleteEventSubscription(subscription.ID); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.DeleteEventSubscription("unknown"); err == nil {
+		t.Fatalf("client: expected failure due to unknown subscription, but got non-error response")
+	}
+}
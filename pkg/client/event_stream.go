@@ -0,0 +1,210 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultEventStreamRetry is the delay before reconnecting to the event
+// stream, unless the server sets the delay.
+const DefaultEventStreamRetry = 5 * time.Second
+
+// EventStreamFilter selects the events of the Server-Sent Events stream.
+// The empty fields match all events.
+type EventStreamFilter struct {
+	// EventFormatType is either Event or MetricReport.
+	EventFormatType string
+	// MessageIDs are the message ids of the events, e.g. TMP0118.
+	MessageIDs []string
+}
+
+// query returns the $filter query parameter of the event stream URL.
+func (f *EventStreamFilter) query() string {
+	if f == nil {
+		return ""
+	}
+	conditions := []string{}
+	if f.EventFormatType != "" {
+		conditions = append(conditions, "EventFormatType eq "+f.EventFormatType)
+	}
+	if len(f.MessageIDs) > 0 {
+		messageConditions := []string{}
+		for _, messageID := range f.MessageIDs {
+			messageConditions = append(messageConditions, "MessageId eq '"+messageID+"'")
+		}
+		condition := strings.Join(messageConditions, " or ")
+		if len(messageConditions) > 1 && len(conditions) > 0 {
+			condition = "(" + condition + ")"
+		}
+		conditions = append(conditions, condition)
+	}
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "?" + url.Values{"$filter": {strings.Join(conditions, " and ")}}.Encode()
+}
+
+// eventStream is a connection to the Server-Sent Events stream of the
+// event service.
+type eventStream struct {
+	cli         *Client
+	urlPath     string
+	lastEventID string
+	retry       time.Duration
+}
+
+// StreamEvents connects to the Server-Sent Events stream of the event
+// service, i.e. ServerSentEventUri, and returns the events matching the
+// filter. The stream reconnects after failures, and resumes after the
+// last received event. The channel closes when the context is done.
+func (cli *Client) StreamEvents(ctx context.Context, filter *EventStreamFilter) (<-chan *Event, error) {
+	svc, err := cli.GetEventService()
+	if err != nil {
+		return nil, err
+	}
+	if svc.ServerSentEventURI == "" {
+		return nil, fmt.Errorf("the event service does not support Server-Sent Events")
+	}
+	stream := &eventStream{
+		cli:     cli,
+		urlPath: svc.ServerSentEventURI + filter.query(),
+		retry:   DefaultEventStreamRetry,
+	}
+	body, err := stream.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan *Event)
+	go stream.run(ctx, body, events)
+	return events, nil
+}
+
+// connect opens the stream. It sends the id of the last received event,
+// i.e. the server resends the events missed while disconnected.
+func (s *eventStream) connect(ctx context.Context) (io.ReadCloser, error) {
+	cli := s.cli
+	url := fmt.Sprintf("%s%s", cli.url, s.urlPath)
+	log.Debugf("GET request to %s", url)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Accept", "text/event-stream")
+	req.Header.Add("Cache-Control", "no-cache")
+	if s.lastEventID != "" {
+		req.Header.Add("Last-Event-ID", s.lastEventID)
+	}
+	req.SetBasicAuth(cli.username, cli.password)
+
+	// The stream has no timeout, i.e. it remains open until the server
+	// or the context closes it.
+	httpClient := &http.Client{Transport: cli.newTransport()}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("API Server responded with %s", res.Status)
+	if res.StatusCode != 200 {
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, cli.dataLimit))
+		return nil, newAPIError(res.StatusCode, body)
+	}
+	return res.Body, nil
+}
+
+// run reads the stream, and reconnects until the context is done.
+func (s *eventStream) run(ctx context.Context, body io.ReadCloser, events chan<- *Event) {
+	defer close(events)
+	for {
+		err := s.read(ctx, body, events)
+		body.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Warnf("event stream of %s failed: %s", s.cli.host, err)
+		} else {
+			log.Debugf("event stream of %s closed by server", s.cli.host)
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.retry):
+			}
+			body, err = s.connect(ctx)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnf("failed reconnecting to event stream of %s: %s", s.cli.host, err)
+		}
+	}
+}
+
+// read passes the events of the stream to the channel until the stream
+// ends.
+func (s *eventStream) read(ctx context.Context, body io.Reader, events chan<- *Event) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 4096), int(s.cli.dataLimit))
+	var data []string
+	var eventID string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// An empty line dispatches the event.
+			if eventID != "" {
+				s.lastEventID = eventID
+			}
+			if len(data) > 0 {
+				event, err := NewEventFromString(strings.Join(data, "\n"))
+				if err != nil {
+					log.Debugf("skipped event stream message of %s: %s", s.cli.host, err)
+				} else {
+					event.Host = s.cli.host
+					event.ReceivedAt = time.Now().UTC()
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return nil
+					}
+				}
+			}
+			data, eventID = nil, ""
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// Comments keep the connection alive.
+			continue
+		}
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "data":
+			data = append(data, value)
+		case "id":
+			eventID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	return scanner.Err()
+}
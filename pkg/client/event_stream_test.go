@@ -0,0 +1,84 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"context"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"testing"
+	"time"
+)
+
+func TestEventStreamFilterQuery(t *testing.T) {
+	for i, test := range []struct {
+		filter *EventStreamFilter
+		exp    string
+	}{
+		{filter: nil, exp: ""},
+		{filter: &EventStreamFilter{}, exp: ""},
+		{
+			filter: &EventStreamFilter{EventFormatType: "Event"},
+			exp:    "?%24filter=EventFormatType+eq+Event",
+		},
+		{
+			filter: &EventStreamFilter{MessageIDs: []string{"TMP0118"}},
+			exp:    "?%24filter=MessageId+eq+%27TMP0118%27",
+		},
+		{
+			filter: &EventStreamFilter{EventFormatType: "Event", MessageIDs: []string{"TMP0118", "PSU0001"}},
+			exp:    "?%24filter=EventFormatType+eq+Event+and+%28MessageId+eq+%27TMP0118%27+or+MessageId+eq+%27PSU0001%27%29",
+		},
+	} {
+		if query := test.filter.query(); query != test.exp {
+			t.Fatalf("FAIL: Test %d: expected %q, but got %q", i, test.exp, query)
+		}
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events, err := cli.StreamEvents(ctx, &EventStreamFilter{EventFormatType: "Event"})
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+
+	// The mock server closes the stream after each event, i.e. the client
+	// reconnects and resumes after the last received event.
+	for _, expID := range []string{"1", "2", "3"} {
+		event, ok := <-events
+		if !ok {
+			t.Fatalf("client: event stream closed before event %s", expID)
+		}
+		if event.ID != expID {
+			t.Fatalf("client: expected event %s, but got %s", expID, event.ID)
+		}
+		if event.Context != "EventFormatType eq Event" {
+			t.Fatalf("client: unexpected event filter: %s", event.Context)
+		}
+		if event.Host != server.NonTLS.Hostname || len(event.Events) != 2 {
+			t.Fatalf("client: unexpected event: %+v", *event)
+		}
+	}
+	cancel()
+	for range events {
+	}
+
+	cli.SetPassword("wrong")
+	if _, err := cli.StreamEvents(context.Background(), nil); err == nil {
+		t.Fatalf("client: expected failure due to wrong password, but got non-error response")
+	}
+}
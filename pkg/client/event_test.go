@@ -0,0 +1,127 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseEventJsonOutput(t *testing.T) {
+	testFailed := 0
+	dataDir := "../../assets/responses"
+	for i, test := range []struct {
+		input      string
+		exp        *Event
+		shouldFail bool // Whether test should result in a failure
+		shouldErr  bool // Whether parsing of a response should result in error
+	}{
+		{
+			input: "event_1",
+			exp: &Event{
+				ID: "5e004f5a-e3d1-11eb-ae9c-3448edf18a38",
+				OData: NewODataAnnotation(
+					"/redfish/v1/EventService/Events/5e004f5a-e3d1-11eb-ae9c-3448edf18a38",
+					"#Event.v1_4_0.Event",
+					"/redfish/v1/$metadata#Event.Event",
+				),
+				Name:    "Event Array",
+				Context: "rack1",
+				Events: []*EventRecord{
+					{
+						EventID:           "2162",
+						EventType:         "Alert",
+						EventTimestamp:    "2020-11-12T14:25:31-0600",
+						Severity:          "Warning",
+						Message:           "The system inlet temperature is less than the lower warning threshold.",
+						MessageID:         "TMP0118",
+						MessageArgs:       []string{"System Board Inlet Temp"},
+						OriginOfCondition: "/redfish/v1/Chassis/System.Embedded.1/Thermal",
+						MemberID:          "7e675c8e-127a-11eb-9f86-e4434b1f7e7a",
+					},
+					{
+						EventID:           "2163",
+						EventType:         "StatusChange",
+						EventTimestamp:    "2020-11-12T14:25:33-0600",
+						Severity:          "OK",
+						Message:           "The power supply is operating normally.",
+						MessageID:         "PSU0001",
+						MessageArgs:       []string{"1"},
+						OriginOfCondition: "/redfish/v1/Chassis/System.Embedded.1/Power",
+						MemberID:          "7f1c1b4a-127a-11eb-9f86-e4434b1f7e7a",
+					},
+				},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			input:      "success_1",
+			shouldFail: false,
+			shouldErr:  true,
+		},
+	} {
+		// Read response file
+		fp := fmt.Sprintf("%s/%s.json", dataDir, test.input)
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			t.Logf("FAIL: Test %d: failed reading '%s', error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+
+		// Parse event
+		event, err := NewEventFromBytes(content)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input '%s', expected to pass, but threw error: %v", i, fp, err)
+				testFailed++
+			} else {
+				t.Logf("PASS: Test %d: input '%s', expected to throw error, threw error: %v", i, fp, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input '%s', expected to throw error, but passed: %v", i, fp, *event)
+			testFailed++
+			continue
+		}
+
+		eventFromString, err := NewEventFromString(string(content))
+		if err != nil {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(eventFromString, event) {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (NewEventFromString) vs. '%v' (NewEventFromBytes)",
+				i, fp, *eventFromString, *event)
+			testFailed++
+			continue
+		}
+
+		if !reflect.DeepEqual(event, test.exp) && !test.shouldFail {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (actual) vs. '%v' (expected)",
+				i, fp, *event, *test.exp)
+			testFailed++
+			continue
+		}
+
+		for _, resource := range []interface{}{event, event.Events[0]} {
+			complianceMessages, compliant := isStructCompliant(resource)
+			if !compliant {
+				testFailed++
+				for _, entry := range complianceMessages {
+					t.Logf("%s", entry)
+				}
+			}
+		}
+
+		t.Logf("PASS: Test %d: input '%s', expected to pass, passed", i, fp)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultUploadTimeout is the timeout of uploading a firmware image.
+const DefaultUploadTimeout = 30 * time.Minute
+
+// UploadProgressFunc receives the number of the bytes of a firmware image
+// uploaded so far, and the size of the image.
+type UploadProgressFunc func(sent, total int64)
+
+// progressReader reports the progress of reading an upload.
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress UploadProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		if p.progress != nil {
+			p.progress(p.sent, p.total)
+		}
+	}
+	return n, err
+}
+
+// SimpleUpdate instructs the service to download a firmware image from the
+// image URI, e.g. http://10.10.10.1/BIOS_R6XK3_WN64_2.9.4.EXE, and to
+// update the targets, or the components the image applies to when the
+// targets are empty. The apply time is either Immediate or OnReset, and
+// defaults to the service default.
+func (cli *Client) SimpleUpdate(imageURI, transferProtocol string, targets []string, applyTime string) (*TaskHandle, error) {
+	svc, err := cli.GetUpdateService()
+	if err != nil {
+		return nil, err
+	}
+	return cli.simpleUpdate(svc, imageURI, transferProtocol, targets, applyTime)
+}
+
+func (cli *Client) simpleUpdate(svc *UpdateService, imageURI, transferProtocol string, targets []string, applyTime string) (*TaskHandle, error) {
+	if svc.SimpleUpdateURI == "" {
+		return nil, fmt.Errorf("update service %s does not support SimpleUpdate", svc.ID)
+	}
+	if imageURI == "" {
+		return nil, fmt.Errorf("image uri is empty")
+	}
+	if err := validateUpdateValue("transfer protocol", transferProtocol, svc.TransferProtocols); err != nil {
+		return nil, err
+	}
+	if err := validateUpdateValue("apply time", applyTime, svc.ApplyTimes); err != nil {
+		return nil, err
+	}
+	req := map[string]interface{}{
+		"ImageURI": imageURI,
+	}
+	if transferProtocol != "" {
+		req["TransferProtocol"] = strings.ToUpper(transferProtocol)
+	}
+	if len(targets) > 0 {
+		req["Targets"] = targets
+	}
+	if applyTime != "" {
+		req["@Redfish.OperationApplyTime"] = applyTime
+	}
+	_, header, err := cli.postResource(svc.SimpleUpdateURI, req)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskHandle(header)
+}
+
+// validateUpdateValue returns an error when the value is not one of the
+// values the service supports. The empty value, and any value when the
+// service does not advertise its values, are valid.
+func validateUpdateValue(name, value string, supported []string) error {
+	if value == "" || len(supported) == 0 {
+		return nil
+	}
+	for _, s := range supported {
+		if strings.EqualFold(s, value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported %s %s, expecting %s", name, value, strings.Join(supported, ", "))
+}
+
+// PushUpdate uploads a firmware image to the service and starts the
+// update. The image is uploaded to MultipartHttpPushUri, which stages the
+// image and starts the update in one request, and the update fails when
+// its task failed already. When the service has no MultipartHttpPushUri,
+// the image is uploaded to HttpPushUri, and, once the image is staged,
+// installed via SimpleUpdate. The progress function, if any, receives the
+// progress of the upload.
+func (cli *Client) PushUpdate(fp, applyTime string, progress UploadProgressFunc) (*TaskHandle, error) {
+	svc, err := cli.GetUpdateService()
+	if err != nil {
+		return nil, err
+	}
+	return cli.pushUpdate(svc, fp, applyTime, progress)
+}
+
+func (cli *Client) pushUpdate(svc *UpdateService, fp, applyTime string, progress UploadProgressFunc) (*TaskHandle, error) {
+	if err := validateUpdateValue("apply time", applyTime, svc.ApplyTimes); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(fp)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return nil, fmt.Errorf("firmware image %s is a directory", fp)
+	}
+	if svc.MaxImageSizeBytes > 0 && uint64(fi.Size()) > svc.MaxImageSizeBytes {
+		return nil, fmt.Errorf("firmware image %s exceeds the maximum image size of %d bytes", fp, svc.MaxImageSizeBytes)
+	}
+
+	if svc.MultipartHTTPPushURI != "" {
+		params := map[string]interface{}{
+			"Targets": []string{},
+		}
+		if applyTime != "" {
+			params["@Redfish.OperationApplyTime"] = applyTime
+		}
+		upload := &imageUpload{
+			urlPath:   svc.MultipartHTTPPushURI,
+			fileField: "UpdateFile",
+			params:    params,
+		}
+		header, err := cli.uploadImage(upload, f, fi.Size(), progress)
+		if err != nil {
+			return nil, err
+		}
+		handle, err := newTaskHandle(header)
+		if err != nil {
+			return nil, err
+		}
+		// The task of the image the service could not stage fails, e.g.
+		// an image for another platform.
+		task, resp, err := cli.getTask(handle.TaskURI)
+		if err != nil && resp == nil {
+			return nil, err
+		}
+		if task != nil && task.IsFailed() {
+			return nil, task.failure()
+		}
+		return handle, nil
+	}
+
+	if svc.HTTPPushURI == "" {
+		return nil, fmt.Errorf("update service %s supports neither MultipartHttpPushUri nor HttpPushUri", svc.ID)
+	}
+	// The uploads to HttpPushUri of iDRAC require the ETag of the
+	// firmware inventory.
+	_, inventoryHeader, err := cli.callAPIWithHeaders("GET", "", svc.HTTPPushURI, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	upload := &imageUpload{
+		urlPath:   svc.HTTPPushURI,
+		fileField: "file",
+		etag:      inventoryHeader.Get("ETag"),
+	}
+	header, err := cli.uploadImage(upload, f, fi.Size(), progress)
+	if err != nil {
+		return nil, err
+	}
+	staged, err := cli.getStagedImage(header.Get("Location"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.simpleUpdate(svc, staged.OData.ID, "", nil, applyTime)
+}
+
+// imageUpload is the multipart request uploading a firmware image.
+type imageUpload struct {
+	urlPath   string
+	fileField string
+	params    interface{}
+	etag      string
+}
+
+// uploadImage uploads a firmware image as multipart/form-data, along with
+// the update parameters, if any, and returns the headers of the response.
+// The image is streamed from the reader rather than buffered in memory.
+func (cli *Client) uploadImage(upload *imageUpload, r io.Reader, size int64, progress UploadProgressFunc) (http.Header, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if upload.params != nil {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="UpdateParameters"`)
+		h.Set("Content-Type", "application/json")
+		pw, err := mw.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.NewEncoder(pw).Encode(upload.params); err != nil {
+			return nil, err
+		}
+	}
+	fileName := "image"
+	if f, ok := r.(*os.File); ok {
+		fileName = filepath.Base(f.Name())
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, upload.fileField, fileName))
+	h.Set("Content-Type", "application/octet-stream")
+	if _, err := mw.CreatePart(h); err != nil {
+		return nil, err
+	}
+	prefix := append([]byte{}, buf.Bytes()...)
+	buf.Reset()
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	suffix := buf.Bytes()
+
+	body := io.MultiReader(
+		bytes.NewReader(prefix),
+		&progressReader{r: io.LimitReader(r, size), total: size, progress: progress},
+		bytes.NewReader(suffix),
+	)
+	req, err := cli.newRequest("POST", mw.FormDataContentType(), upload.urlPath, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(prefix)) + size + int64(len(suffix))
+	if upload.etag != "" {
+		req.Header.Set("If-Match", upload.etag)
+	}
+	_, header, err := cli.doRequest(req, DefaultUploadTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// getStagedImage returns the firmware inventory entry of an uploaded
+// image, and verifies the image is staged, i.e. available for install.
+func (cli *Client) getStagedImage(location string) (*SoftwareInventory, error) {
+	if location == "" {
+		return nil, fmt.Errorf("the service accepted the image, but returned no image location")
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("image location %s is invalid: %s", location, err)
+	}
+	resp, err := cli.callAPI("GET", "", u.Path, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	item, err := newSoftwareInventoryFromBytes(resp)
+	if err != nil {
+		return nil, err
+	}
+	if item.InstallState != "Available" {
+		return nil, fmt.Errorf("the uploaded image %s is not staged, its state is %s", item.ID, item.InstallState)
+	}
+	return item, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFirmwareUpdate(t *testing.T) {
+	server, err := NewMockTestServer(map[string]string{
+		"POST /redfish/v1/UpdateService/MultipartUploadRejected": "task_4.json",
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	expHandle := &TaskHandle{
+		TaskURI: "/redfish/v1/TaskService/Tasks/JID_467762674724",
+		JobID:   "JID_467762674724",
+	}
+
+	for i, test := range []struct {
+		imageURI         string
+		transferProtocol string
+		targets          []string
+		applyTime        string
+		shouldErr        bool
+	}{
+		{imageURI: "http://10.10.10.1/BIOS_R6XK3_WN64_2.9.4.EXE"},
+		{imageURI: "//10.10.10.1/share/BIOS_R6XK3_WN64_2.9.4.EXE", transferProtocol: "cifs", applyTime: "OnReset"},
+		{imageURI: "http://10.10.10.1/BIOS_R6XK3_WN64_2.9.4.EXE", targets: []string{"/redfish/v1/Systems/System.Embedded.1"}},
+		{imageURI: "scp://10.10.10.1/BIOS_R6XK3_WN64_2.9.4.EXE", transferProtocol: "SCP", shouldErr: true},
+		{imageURI: "http://10.10.10.1/BIOS_R6XK3_WN64_2.9.4.EXE", applyTime: "AtMaintenanceWindowStart", shouldErr: true},
+		{shouldErr: true},
+	} {
+		handle, err := cli.SimpleUpdate(test.imageURI, test.transferProtocol, test.targets, test.applyTime)
+		if err != nil {
+			if !test.shouldErr {
+				t.Fatalf("client: Test %d: expected success, but got error: %s", i, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Fatalf("client: Test %d: expected failure, but got non-error response", i)
+		}
+		if !reflect.DeepEqual(handle, expHandle) {
+			t.Fatalf("client: Test %d: unexpected task handle: %+v", i, *handle)
+		}
+	}
+
+	fp := filepath.Join(t.TempDir(), "SAS-RAID_Firmware_F0XJR_WN64_51.13.0-3485_A08.EXE")
+	if err := ioutil.WriteFile(fp, make([]byte, 100000), 0600); err != nil {
+		t.Fatalf("failed writing image: %s", err)
+	}
+	svc, err := cli.GetUpdateService()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	for _, multipart := range []bool{true, false} {
+		var sent, total int64
+		progress := func(n, size int64) {
+			if n < sent {
+				t.Fatalf("client: the upload progress went back from %d to %d", sent, n)
+			}
+			sent, total = n, size
+		}
+		pushSvc := *svc
+		if !multipart {
+			// The image is uploaded to HttpPushUri, staged, and installed
+			// via SimpleUpdate.
+			pushSvc.MultipartHTTPPushURI = ""
+		}
+		handle, err := cli.pushUpdate(&pushSvc, fp, "Immediate", progress)
+		if err != nil {
+			t.Fatalf("client: multipart %t: expected success, but got error: %s", multipart, err)
+		}
+		if !reflect.DeepEqual(handle, expHandle) {
+			t.Fatalf("client: multipart %t: unexpected task handle: %+v", multipart, *handle)
+		}
+		if sent != 100000 || total != 100000 {
+			t.Fatalf("client: multipart %t: expected progress of 100000 bytes, but got %d of %d", multipart, sent, total)
+		}
+	}
+
+	pushSvc := *svc
+	pushSvc.MultipartHTTPPushURI = "/redfish/v1/UpdateService/MultipartUploadRejected"
+	if _, err := cli.pushUpdate(&pushSvc, fp, "", nil); err == nil {
+		t.Fatalf("client: expected failure due to failed task, but got non-error response")
+	}
+	pushSvc = *svc
+	pushSvc.MaxImageSizeBytes = 1000
+	if _, err := cli.pushUpdate(&pushSvc, fp, "", nil); err == nil {
+		t.Fatalf("client: expected failure due to image size, but got non-error response")
+	}
+	if _, err := cli.PushUpdate(fp+".missing", "", nil); err == nil {
+		t.Fatalf("client: expected failure due to missing image, but got non-error response")
+	}
+	if _, err := cli.getStagedImage("/redfish/v1/UpdateService/FirmwareInventory/Installed-159-2.8.2"); err == nil {
+		t.Fatalf("client: expected failure due to installed image, but got non-error response")
+	}
+}
+
+func TestNewTaskHandle(t *testing.T) {
+	for i, test := range []struct {
+		location  string
+		exp       *TaskHandle
+		shouldErr bool
+	}{
+		{
+			location: "https://10.10.10.10/redfish/v1/TaskService/Tasks/JID_467762674724",
+			exp: &TaskHandle{
+				TaskURI: "/redfish/v1/TaskService/Tasks/JID_467762674724",
+				JobID:   "JID_467762674724",
+			},
+		},
+		{
+			location: "/redfish/v1/TaskService/TaskMonitors/7",
+			exp: &TaskHandle{
+				TaskURI: "/redfish/v1/TaskService/TaskMonitors/7",
+			},
+		},
+		{shouldErr: true},
+	} {
+		header := http.Header{}
+		if test.location != "" {
+			header.Set("Location", test.location)
+		}
+		handle, err := newTaskHandle(header)
+		if err != nil {
+			if !test.shouldErr {
+				t.Fatalf("Test %d: expected success, but got error: %s", i, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Fatalf("Test %d: expected failure, but got non-error response", i)
+		}
+		if !reflect.DeepEqual(handle, test.exp) {
+			t.Fatalf("Test %d: unexpected task handle: %+v", i, *handle)
+		}
+	}
+}
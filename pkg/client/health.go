@@ -1,8 +1,33 @@
 package client
 
+import (
+	"encoding/json"
+)
+
 // HealthStatus represents a collection of health indicators.
 type HealthStatus struct {
-	Health       string
-	HealthRollup string
-	State        string
+	Health       string `yaml:"health" json:"health" xml:"health"`
+	HealthRollup string `yaml:"health_rollup" json:"health_rollup" xml:"health_rollup"`
+	State        string `yaml:"state" json:"state" xml:"state"`
+}
+
+// UnmarshalJSON unpacks HealthStatus from both Redfish API responses, i.e.
+// HealthRollup, and the output of this library, i.e. health_rollup.
+func (s *HealthStatus) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Health             string
+		HealthRollup       string
+		HealthRollupOutput string `json:"health_rollup"`
+		State              string
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	s.Health = v.Health
+	s.HealthRollup = v.HealthRollup
+	if s.HealthRollup == "" {
+		s.HealthRollup = v.HealthRollupOutput
+	}
+	s.State = v.State
+	return nil
 }
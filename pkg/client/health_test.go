@@ -0,0 +1,46 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHealthStatusJSON(t *testing.T) {
+	for i, test := range []struct {
+		input string
+		exp   HealthStatus
+	}{
+		{
+			input: `{"Health": "OK", "HealthRollup": "Warning", "State": "Enabled"}`,
+			exp:   HealthStatus{Health: "OK", HealthRollup: "Warning", State: "Enabled"},
+		},
+		{
+			input: `{"health": "OK", "health_rollup": "Critical", "state": "Enabled"}`,
+			exp:   HealthStatus{Health: "OK", HealthRollup: "Critical", State: "Enabled"},
+		},
+		{
+			input: `{"State": "Absent"}`,
+			exp:   HealthStatus{State: "Absent"},
+		},
+	} {
+		var status HealthStatus
+		if err := json.Unmarshal([]byte(test.input), &status); err != nil {
+			t.Fatalf("FAIL: Test %d: expected to pass, but threw error: %v", i, err)
+		}
+		if status != test.exp {
+			t.Fatalf("FAIL: Test %d: value mismatch: '%v' (actual) vs. '%v' (expected)", i, status, test.exp)
+		}
+		b, err := json.Marshal(status)
+		if err != nil {
+			t.Fatalf("FAIL: Test %d: expected to pass, but threw error: %v", i, err)
+		}
+		var roundTrip HealthStatus
+		if err := json.Unmarshal(b, &roundTrip); err != nil || roundTrip != status {
+			t.Fatalf("FAIL: Test %d: round trip mismatch: '%v' (actual) vs. '%v' (expected)", i, roundTrip, status)
+		}
+	}
+	complianceMessages, compliant := isStructCompliant(&HealthStatus{})
+	if !compliant {
+		t.Fatalf("FAIL: HealthStatus struct is not compliant: %v", complianceMessages)
+	}
+}
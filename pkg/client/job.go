@@ -0,0 +1,181 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DellJobServiceResource is the path of Dell JobService of iDRAC.
+const DellJobServiceResource = "Dell/Managers/iDRAC.Embedded.1/DellJobService"
+
+// The job ids deleting all the jobs of the job queue. The force variant
+// also clears the pending configuration data.
+const (
+	JobIDClearAll      = "JID_CLEARALL"
+	JobIDClearAllForce = "JID_CLEARALL_FORCE"
+)
+
+type jobResponse struct {
+	ODataAnnotation
+	ID              string `json:"Id"`
+	Name            string
+	Description     string
+	JobState        string
+	JobType         string
+	Message         string
+	MessageID       string `json:"MessageId"`
+	MessageArgs     []string
+	PercentComplete int
+	StartTime       string
+	EndTime         string
+	CompletionTime  string
+}
+
+// Job represents an instance of Dell Job resource, i.e. an entry of the
+// job queue of iDRAC.
+type Job struct {
+	ID              string           `yaml:"id" json:"id" xml:"id"`
+	OData           *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name            string           `yaml:"name" json:"name" xml:"name"`
+	Description     string           `yaml:"description" json:"description" xml:"description"`
+	JobState        string           `yaml:"job_state" json:"job_state" xml:"job_state"`
+	JobType         string           `yaml:"job_type" json:"job_type" xml:"job_type"`
+	Message         string           `yaml:"message" json:"message" xml:"message"`
+	MessageID       string           `yaml:"message_id" json:"message_id" xml:"message_id"`
+	MessageArgs     []string         `yaml:"message_args" json:"message_args" xml:"message_args"`
+	PercentComplete int              `yaml:"percent_complete" json:"percent_complete" xml:"percent_complete"`
+	StartTime       string           `yaml:"start_time" json:"start_time" xml:"start_time"`
+	EndTime         string           `yaml:"end_time" json:"end_time" xml:"end_time"`
+	CompletionTime  string           `yaml:"completion_time" json:"completion_time" xml:"completion_time"`
+}
+
+// IsDone returns true when the job is in a final state, i.e. Completed,
+// Failed, CompletedWithErrors, or RebootFailed.
+func (j *Job) IsDone() bool {
+	switch j.JobState {
+	case "Completed", "Failed", "CompletedWithErrors", "RebootFailed":
+		return true
+	}
+	return false
+}
+
+// ListJobs returns the jobs of the job queue of iDRAC.
+func (cli *Client) ListJobs() ([]*Job, error) {
+	members, err := cli.getCollectionMembers(cli.rootPath + ManagerResource + "/Jobs")
+	if err != nil {
+		return nil, err
+	}
+	jobs := []*Job{}
+	for _, member := range members {
+		job, err := cli.getJob(member)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// GetJobQueue returns the pending jobs of the job queue of iDRAC, i.e. the
+// jobs which are not done yet.
+func (cli *Client) GetJobQueue() ([]*Job, error) {
+	jobs, err := cli.ListJobs()
+	if err != nil {
+		return nil, err
+	}
+	pending := []*Job{}
+	for _, job := range jobs {
+		if !job.IsDone() {
+			pending = append(pending, job)
+		}
+	}
+	return pending, nil
+}
+
+// GetJob returns an instance of Dell Job resource, e.g. JID_467762674724.
+func (cli *Client) GetJob(id string) (*Job, error) {
+	if err := validateJobID(id, false); err != nil {
+		return nil, err
+	}
+	return cli.getJob(cli.rootPath + ManagerResource + "/Jobs/" + id)
+}
+
+func (cli *Client) getJob(s string) (*Job, error) {
+	resp, err := cli.callAPI("GET", "", s, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newJobFromBytes(resp)
+}
+
+// DeleteJob deletes a job from the job queue of iDRAC. The id is either the
+// id of a job, or JID_CLEARALL, or JID_CLEARALL_FORCE.
+func (cli *Client) DeleteJob(id string) error {
+	if err := validateJobID(id, true); err != nil {
+		return err
+	}
+	req := map[string]interface{}{
+		"JobID": id,
+	}
+	_, _, err := cli.postResource(cli.rootPath+DellJobServiceResource+"/Actions/DellJobService.DeleteJobQueue", req)
+	return err
+}
+
+// validateJobID returns an error when the id is neither a job id, e.g.
+// JID_467762674724, nor a reboot job id, e.g. RID_467762674724. The ids
+// deleting all the jobs are valid when clearAll is true.
+func validateJobID(id string, clearAll bool) error {
+	if id == "" {
+		return fmt.Errorf("job id is empty")
+	}
+	if id == JobIDClearAll || id == JobIDClearAllForce {
+		if clearAll {
+			return nil
+		}
+		return fmt.Errorf("job id %s is not supported", id)
+	}
+	if !strings.HasPrefix(id, "JID_") && !strings.HasPrefix(id, "RID_") {
+		return fmt.Errorf("job id %s is invalid, expecting JID_ or RID_ prefix", id)
+	}
+	return nil
+}
+
+// newJobFromString returns Job instance from an input string.
+func newJobFromString(s string) (*Job, error) {
+	return newJobFromBytes([]byte(s))
+}
+
+// newJobFromBytes returns Job instance from an input byte array.
+func newJobFromBytes(s []byte) (*Job, error) {
+	response := &jobResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the job is empty, server response: %s", string(s[:]))
+	}
+	job := &Job{
+		ID:              response.ID,
+		Name:            response.Name,
+		Description:     response.Description,
+		JobState:        response.JobState,
+		JobType:         response.JobType,
+		Message:         response.Message,
+		MessageID:       response.MessageID,
+		MessageArgs:     nonNilStrings(response.MessageArgs),
+		PercentComplete: response.PercentComplete,
+		StartTime:       response.StartTime,
+		EndTime:         response.EndTime,
+		CompletionTime:  response.CompletionTime,
+	}
+	job.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return job, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseJobJsonOutput(t *testing.T) {
+	content, err := ioutil.ReadFile("../../assets/responses/job_3.json")
+	if err != nil {
+		t.Fatalf("failed reading job: %s", err)
+	}
+	job, err := newJobFromBytes(content)
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	jobFromString, err := newJobFromString(string(content))
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if !reflect.DeepEqual(jobFromString, job) {
+		t.Fatalf("value mismatch: '%v' (newJobFromString) vs. '%v' (newJobFromBytes)", *jobFromString, *job)
+	}
+	if job.ID != "JID_467762674724" || job.JobState != "Running" || job.JobType != "FirmwareUpdate" || job.PercentComplete != 50 {
+		t.Fatalf("unexpected job: %+v", *job)
+	}
+	if job.IsDone() {
+		t.Fatalf("expected running job, but the job is done")
+	}
+	complianceMessages, compliant := isStructCompliant(job)
+	if !compliant {
+		for _, entry := range complianceMessages {
+			t.Logf("%s", entry)
+		}
+		t.Fatalf("job is not compliant")
+	}
+	if _, err := newJobFromString(`{"JobState": "Running"}`); err == nil {
+		t.Fatalf("expected failure due to empty Id, but got non-error response")
+	}
+}
+
+func TestJobs(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	jobs, err := cli.ListJobs()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(jobs) != 4 {
+		t.Fatalf("client: expected 4 jobs, but got %d", len(jobs))
+	}
+	queue, err := cli.GetJobQueue()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	pending := []string{}
+	for _, job := range queue {
+		pending = append(pending, job.ID)
+	}
+	if !reflect.DeepEqual(pending, []string{"JID_467762674724", "JID_467799000000"}) {
+		t.Fatalf("client: unexpected pending jobs: %v", pending)
+	}
+
+	job, err := cli.GetJob("JID_467762000001")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if job.JobState != "Failed" || !job.IsDone() {
+		t.Fatalf("client: expected failed job, but got %s", job.JobState)
+	}
+	if _, err := cli.GetJob(JobIDClearAll); err == nil {
+		t.Fatalf("client: expected failure due to invalid job id, but got non-error response")
+	}
+
+	for i, test := range []struct {
+		id        string
+		shouldErr bool
+	}{
+		{id: "JID_467799000000"},
+		{id: "RID_467799000000"},
+		{id: JobIDClearAll},
+		{id: JobIDClearAllForce},
+		{id: "467799000000", shouldErr: true},
+		{shouldErr: true},
+	} {
+		err := cli.DeleteJob(test.id)
+		if err != nil {
+			if !test.shouldErr {
+				t.Fatalf("client: Test %d: expected success, but got error: %s", i, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Fatalf("client: Test %d: expected failure, but got non-error response", i)
+		}
+	}
+}
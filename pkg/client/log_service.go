@@ -0,0 +1,290 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+// ManagerResource is the manager of the system, i.e. iDRAC. Its log
+// services are the System Event Log (Sel) and the Lifecycle log (Lclog).
+const ManagerResource = "Managers/iDRAC.Embedded.1"
+
+// severityRanks orders the severities of log entries and events.
+var severityRanks = map[string]int{
+	"ok":       0,
+	"warning":  1,
+	"critical": 2,
+}
+
+type logServiceResponse struct {
+	ODataAnnotation
+	ID                 string `json:"Id"`
+	Name               string
+	Description        string
+	ServiceEnabled     bool
+	MaxNumberOfRecords uint64
+	OverWritePolicy    string
+	DateTime           string
+	Entries            ODataAnnotation
+	Status             HealthStatus
+	Actions            struct {
+		ClearLog struct {
+			Target string `json:"target"`
+		} `json:"#LogService.ClearLog"`
+	}
+}
+
+// LogService represents an instance of Redfish LogService resource, e.g.
+// the System Event Log of iDRAC.
+type LogService struct {
+	ID                 string           `yaml:"id" json:"id" xml:"id"`
+	OData              *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name               string           `yaml:"name" json:"name" xml:"name"`
+	Description        string           `yaml:"description" json:"description" xml:"description"`
+	ServiceEnabled     bool             `yaml:"service_enabled" json:"service_enabled" xml:"service_enabled"`
+	MaxNumberOfRecords uint64           `yaml:"max_number_of_records" json:"max_number_of_records" xml:"max_number_of_records"`
+	OverWritePolicy    string           `yaml:"over_write_policy" json:"over_write_policy" xml:"over_write_policy"`
+	DateTime           string           `yaml:"date_time" json:"date_time" xml:"date_time"`
+	EntriesURI         string           `yaml:"entries_uri" json:"entries_uri" xml:"entries_uri"`
+	ClearLogURI        string           `yaml:"clear_log_uri" json:"clear_log_uri" xml:"clear_log_uri"`
+	Status             HealthStatus     `yaml:"status" json:"status" xml:"status"`
+}
+
+type logEntryResponse struct {
+	ODataAnnotation
+	ID           string `json:"Id"`
+	Name         string
+	EntryType    string
+	Created      string
+	Severity     string
+	Message      string
+	MessageID    string `json:"MessageId"`
+	MessageArgs  []string
+	SensorType   string
+	SensorNumber uint64
+	EntryCode    string
+	Links        struct {
+		OriginOfCondition ODataAnnotation
+	}
+}
+
+// LogEntry represents an instance of Redfish LogEntry resource, i.e. a
+// record of a log service.
+type LogEntry struct {
+	ID                string           `yaml:"id" json:"id" xml:"id"`
+	OData             *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name              string           `yaml:"name" json:"name" xml:"name"`
+	EntryType         string           `yaml:"entry_type" json:"entry_type" xml:"entry_type"`
+	Created           time.Time        `yaml:"created" json:"created" xml:"created"`
+	Severity          string           `yaml:"severity" json:"severity" xml:"severity"`
+	Message           string           `yaml:"message" json:"message" xml:"message"`
+	MessageID         string           `yaml:"message_id" json:"message_id" xml:"message_id"`
+	MessageArgs       []string         `yaml:"message_args" json:"message_args" xml:"message_args"`
+	SensorType        string           `yaml:"sensor_type" json:"sensor_type" xml:"sensor_type"`
+	SensorNumber      uint64           `yaml:"sensor_number" json:"sensor_number" xml:"sensor_number"`
+	EntryCode         string           `yaml:"entry_code" json:"entry_code" xml:"entry_code"`
+	OriginOfCondition string           `yaml:"origin_of_condition" json:"origin_of_condition" xml:"origin_of_condition"`
+}
+
+// logEntryCollectionResponse is a page of the entries of a log service.
+// The entries are members of the collection, i.e. not references.
+type logEntryCollectionResponse struct {
+	Members  []json.RawMessage
+	NextLink string `json:"Members@odata.nextLink"`
+}
+
+// ListLogServices returns the log services of a manager or a system, e.g.
+// ManagerResource or Systems/System.Embedded.1.
+func (cli *Client) ListLogServices(managerOrSystem string) ([]*LogService, error) {
+	members, err := cli.getCollectionMembers(cli.rootPath + strings.Trim(managerOrSystem, "/") + "/LogServices")
+	if err != nil {
+		return nil, err
+	}
+	services := []*LogService{}
+	for _, member := range members {
+		resp, err := cli.callAPI("GET", "", member, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		svc, err := newLogServiceFromBytes(resp)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, svc)
+	}
+	return services, nil
+}
+
+// GetLogService returns the log service of a manager or a system with the
+// id, e.g. Sel or Lclog.
+func (cli *Client) GetLogService(managerOrSystem, logServiceID string) (*LogService, error) {
+	services, err := cli.ListLogServices(managerOrSystem)
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services {
+		if strings.EqualFold(svc.ID, logServiceID) {
+			return svc, nil
+		}
+	}
+	return nil, fmt.Errorf("log service %s not found in %s", logServiceID, managerOrSystem)
+}
+
+// GetLogEntries returns the entries of a log service created at or after
+// the time since, and with the severity or higher, i.e. Warning returns
+// Warning and Critical entries. The zero time and the empty severity match
+// all entries. The entries without a creation time, i.e. with a missing or
+// unparsable Created, match any time. The function follows the next links
+// of the entries, newest first, until the entries of a page with a creation
+// time were all created before the time since, or a next link repeats.
+func (cli *Client) GetLogEntries(logService *LogService, since time.Time, severity string) ([]*LogEntry, error) {
+	minRank := 0
+	if severity != "" {
+		rank, exists := severityRanks[strings.ToLower(severity)]
+		if !exists {
+			return nil, fmt.Errorf("unsupported severity %s, expecting OK, Warning, or Critical", severity)
+		}
+		minRank = rank
+	}
+	if logService.EntriesURI == "" {
+		return nil, fmt.Errorf("log service %s has no entries", logService.ID)
+	}
+	entries := []*LogEntry{}
+	visited := make(map[string]bool)
+	for s := logService.EntriesURI; s != "" && !visited[s]; {
+		visited[s] = true
+		resp, err := cli.callAPI("GET", "", s, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		page := &logEntryCollectionResponse{}
+		if err := json.Unmarshal(resp, page); err != nil {
+			return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+		}
+		dated, older := 0, 0
+		for _, member := range page.Members {
+			entry, err := newLogEntryFromBytes(member)
+			if err != nil {
+				return nil, err
+			}
+			if !entry.Created.IsZero() {
+				dated++
+			}
+			if !since.IsZero() && !entry.Created.IsZero() && entry.Created.Before(since) {
+				older++
+				continue
+			}
+			if severityRanks[strings.ToLower(entry.Severity)] < minRank {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		if older > 0 && older == dated {
+			// The next pages have older entries only.
+			break
+		}
+		s = page.NextLink
+	}
+	return entries, nil
+}
+
+// ClearLog deletes the entries of a log service.
+func (cli *Client) ClearLog(logService *LogService) error {
+	if logService.ClearLogURI == "" {
+		return fmt.Errorf("log service %s does not support clearing", logService.ID)
+	}
+	_, _, err := cli.postResource(logService.ClearLogURI, map[string]interface{}{})
+	return err
+}
+
+// newLogServiceFromString returns LogService instance from an input string.
+func newLogServiceFromString(s string) (*LogService, error) {
+	return newLogServiceFromBytes([]byte(s))
+}
+
+// newLogServiceFromBytes returns LogService instance from an input byte array.
+func newLogServiceFromBytes(s []byte) (*LogService, error) {
+	response := &logServiceResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the log service is empty, server response: %s", string(s[:]))
+	}
+	svc := &LogService{
+		ID:                 response.ID,
+		Name:               response.Name,
+		Description:        response.Description,
+		ServiceEnabled:     response.ServiceEnabled,
+		MaxNumberOfRecords: response.MaxNumberOfRecords,
+		OverWritePolicy:    response.OverWritePolicy,
+		DateTime:           response.DateTime,
+		EntriesURI:         response.Entries.ID,
+		ClearLogURI:        response.Actions.ClearLog.Target,
+		Status:             response.Status,
+	}
+	svc.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return svc, nil
+}
+
+// newLogEntryFromString returns LogEntry instance from an input string.
+func newLogEntryFromString(s string) (*LogEntry, error) {
+	return newLogEntryFromBytes([]byte(s))
+}
+
+// newLogEntryFromBytes returns LogEntry instance from an input byte array.
+func newLogEntryFromBytes(s []byte) (*LogEntry, error) {
+	response := &logEntryResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the log entry is empty, server response: %s", string(s[:]))
+	}
+	entry := &LogEntry{
+		ID:                response.ID,
+		Name:              response.Name,
+		EntryType:         response.EntryType,
+		Severity:          response.Severity,
+		Message:           response.Message,
+		MessageID:         response.MessageID,
+		MessageArgs:       nonNilStrings(response.MessageArgs),
+		SensorType:        response.SensorType,
+		SensorNumber:      response.SensorNumber,
+		EntryCode:         response.EntryCode,
+		OriginOfCondition: response.Links.OriginOfCondition.ID,
+	}
+	if response.Created != "" {
+		entry.Created, err = parseRedfishTime(response.Created)
+		if err != nil {
+			log.Debugf("log entry %s has invalid creation time: %s", response.ID, err)
+		}
+	}
+	entry.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return entry, nil
+}
+
+// parseRedfishTime parses the timestamps of Redfish resources, i.e.
+// RFC 3339, or with the time zone offset without colon.
+func parseRedfishTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02T15:04:05-0700", s)
+}
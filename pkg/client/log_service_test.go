@@ -0,0 +1,245 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"fmt"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLogServiceJsonOutput(t *testing.T) {
+	testFailed := 0
+	dataDir := "../../assets/responses"
+	for i, test := range []struct {
+		input      string
+		exp        *LogService
+		shouldFail bool // Whether test should result in a failure
+		shouldErr  bool // Whether parsing of a response should result in error
+	}{
+		{
+			input: "log_service_sel_1",
+			exp: &LogService{
+				ID: "Sel",
+				OData: NewODataAnnotation(
+					"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel",
+					"#LogService.v1_1_1.LogService",
+					"/redfish/v1/$metadata#LogService.LogService",
+				),
+				Name:               "SEL Log Service",
+				Description:        "SEL Log Service",
+				ServiceEnabled:     true,
+				MaxNumberOfRecords: 1024,
+				OverWritePolicy:    "WrapsWhenFull",
+				DateTime:           "2020-11-12T15:02:11-06:00",
+				EntriesURI:         "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries",
+				ClearLogURI:        "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Actions/LogService.ClearLog",
+				Status: HealthStatus{
+					Health: "OK",
+					State:  "Enabled",
+				},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			input:      "root_2",
+			shouldFail: false,
+			shouldErr:  true,
+		},
+	} {
+		// Read response file
+		fp := fmt.Sprintf("%s/%s.json", dataDir, test.input)
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			t.Logf("FAIL: Test %d: failed reading '%s', error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+
+		// Parse API response
+		svc, err := newLogServiceFromBytes(content)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input '%s', expected to pass, but threw error: %v", i, fp, err)
+				testFailed++
+			} else {
+				t.Logf("PASS: Test %d: input '%s', expected to throw error, threw error: %v", i, fp, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input '%s', expected to throw error, but passed: %v", i, fp, *svc)
+			testFailed++
+			continue
+		}
+
+		svcFromString, err := newLogServiceFromString(string(content))
+		if err != nil {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(svcFromString, svc) {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (newLogServiceFromString) vs. '%v' (newLogServiceFromBytes)",
+				i, fp, *svcFromString, *svc)
+			testFailed++
+			continue
+		}
+
+		if !reflect.DeepEqual(svc, test.exp) && !test.shouldFail {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (actual) vs. '%v' (expected)",
+				i, fp, *svc, *test.exp)
+			testFailed++
+			continue
+		}
+
+		for _, resource := range []interface{}{svc, &LogEntry{}} {
+			complianceMessages, compliant := isStructCompliant(resource)
+			if !compliant {
+				testFailed++
+				for _, entry := range complianceMessages {
+					t.Logf("%s", entry)
+				}
+			}
+		}
+
+		t.Logf("PASS: Test %d: input '%s', expected to pass, passed", i, fp)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestGetLogEntries(t *testing.T) {
+	server, err := NewMockTestServer(map[string]string{
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries?$skip=10": "log_entry_collection_sel_4.json",
+		"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries?$skip=20": "log_entry_collection_sel_5.json",
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	services, err := cli.ListLogServices(ManagerResource)
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(services) != 2 {
+		t.Fatalf("client: expected 2 log services, but got %d", len(services))
+	}
+	sel, err := cli.GetLogService(ManagerResource, "sel")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+
+	created, _ := time.Parse(time.RFC3339, "2020-11-11T08:03:12-06:00")
+	for i, test := range []struct {
+		since     time.Time
+		severity  string
+		expIDs    []string
+		shouldErr bool
+	}{
+		// The entries span two pages.
+		{expIDs: []string{"3", "2", "1"}},
+		{since: created, expIDs: []string{"3", "2"}},
+		{severity: "warning", expIDs: []string{"3", "2"}},
+		{since: created.Add(time.Second), severity: "Critical", expIDs: []string{}},
+		{severity: "Informational", shouldErr: true},
+	} {
+		entries, err := cli.GetLogEntries(sel, test.since, test.severity)
+		if err != nil {
+			if !test.shouldErr {
+				t.Fatalf("client: Test %d: expected success, but got error: %s", i, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Fatalf("client: Test %d: expected failure, but got non-error response", i)
+		}
+		ids := []string{}
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		if !reflect.DeepEqual(ids, test.expIDs) {
+			t.Fatalf("client: Test %d: expected entries %v, but got %v", i, test.expIDs, ids)
+		}
+	}
+
+	for i, test := range []struct {
+		uri    string
+		expIDs []string
+	}{
+		// The entry 7 has an invalid creation time, and matches any time.
+		// The other entries of the page are older, i.e. the next page,
+		// absent from the server, is not requested.
+		{uri: "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries?$skip=10", expIDs: []string{"7"}},
+		// The next link of the page is the page itself.
+		{uri: "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries?$skip=20", expIDs: []string{"8"}},
+	} {
+		entries, err := cli.GetLogEntries(&LogService{ID: "Sel", EntriesURI: test.uri}, created, "")
+		if err != nil {
+			t.Fatalf("client: Test %d: expected success, but got error: %s", i, err)
+		}
+		ids := []string{}
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		if !reflect.DeepEqual(ids, test.expIDs) {
+			t.Fatalf("client: Test %d: expected entries %v, but got %v", i, test.expIDs, ids)
+		}
+	}
+
+	entries, _ := cli.GetLogEntries(sel, time.Time{}, "")
+	expEntry := &LogEntry{
+		ID: "2",
+		OData: NewODataAnnotation(
+			"/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries/2",
+			"#LogEntry.v1_6_1.LogEntry",
+			"",
+		),
+		Name:              "Log Entry 2",
+		EntryType:         "SEL",
+		Created:           created,
+		Severity:          "Critical",
+		Message:           "The power input for power supply 2 is lost.",
+		MessageID:         "PSU0003",
+		MessageArgs:       []string{"2"},
+		SensorType:        "Power Supply",
+		SensorNumber:      99,
+		EntryCode:         "Assert",
+		OriginOfCondition: "/redfish/v1/Systems/System.Embedded.1",
+	}
+	if !reflect.DeepEqual(entries[1], expEntry) {
+		t.Fatalf("client: unexpected log entry: %+v", *entries[1])
+	}
+
+	lclog, err := cli.GetLogService(ManagerResource, "Lclog")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	entries, err = cli.GetLogEntries(lclog, time.Time{}, "")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(entries) != 2 || entries[0].MessageID != "USR0030" {
+		t.Fatalf("client: unexpected lifecycle log entries: %+v", entries)
+	}
+
+	if err := cli.ClearLog(sel); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.ClearLog(lclog); err == nil {
+		t.Fatalf("client: expected failure due to unsupported clearing, but got non-error response")
+	}
+}
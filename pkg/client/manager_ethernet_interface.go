@@ -0,0 +1,495 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+type ipv4AddressResponse struct {
+	Address       string
+	SubnetMask    string
+	Gateway       string
+	AddressOrigin string
+}
+
+type ipv6AddressResponse struct {
+	Address       string
+	PrefixLength  *int
+	AddressOrigin string
+	AddressState  string
+}
+
+type ethernetInterfaceResponse struct {
+	ODataAnnotation
+	ID               string `json:"Id"`
+	Name             string
+	Description      string
+	InterfaceEnabled *bool
+	MACAddress       string
+	HostName         string
+	FQDN             string
+	SpeedMbps        uint64
+	DHCPv4           *struct {
+		DHCPEnabled   *bool
+		UseDNSServers *bool
+		UseDomainName *bool
+		UseGateway    *bool
+		UseNTPServers *bool
+	}
+	DHCPv6 *struct {
+		OperatingMode string
+		UseDNSServers *bool
+		UseDomainName *bool
+		UseNTPServers *bool
+	}
+	IPv4Addresses       []*ipv4AddressResponse
+	IPv4StaticAddresses []*ipv4AddressResponse
+	IPv6Addresses       []*ipv6AddressResponse
+	IPv6StaticAddresses []*ipv6AddressResponse
+	IPv6DefaultGateway  string
+	NameServers         []string
+	StaticNameServers   []string
+	VLAN                *struct {
+		VLANEnable *bool
+		VLANID     *int `json:"VLANId"`
+	}
+	Status HealthStatus
+}
+
+// EthernetInterface represents an instance of Redfish EthernetInterface of
+// a manager, i.e. the network interface of iDRAC. The IPv4 and IPv6
+// addresses and the name servers are the addresses in use, while the static
+// addresses and the static name servers are the configured ones. In the
+// changes of the interface, the nil fields and the empty strings remain
+// unchanged.
+type EthernetInterface struct {
+	ID                  string               `yaml:"id" json:"id" xml:"id"`
+	OData               *ODataAnnotation     `yaml:"odata" json:"odata" xml:"odata"`
+	Name                string               `yaml:"name" json:"name" xml:"name"`
+	InterfaceEnabled    *bool                `yaml:"interface_enabled" json:"interface_enabled" xml:"interface_enabled"`
+	MACAddress          string               `yaml:"mac_address" json:"mac_address" xml:"mac_address"`
+	HostName            string               `yaml:"host_name" json:"host_name" xml:"host_name"`
+	FQDN                string               `yaml:"fqdn" json:"fqdn" xml:"fqdn"`
+	SpeedMbps           uint64               `yaml:"speed_mbps" json:"speed_mbps" xml:"speed_mbps"`
+	DHCPv4              *DHCPv4Configuration `yaml:"dhcpv4" json:"dhcpv4" xml:"dhcpv4"`
+	DHCPv6              *DHCPv6Configuration `yaml:"dhcpv6" json:"dhcpv6" xml:"dhcpv6"`
+	IPv4Addresses       []*IPv4Address       `yaml:"ipv4_addresses" json:"ipv4_addresses" xml:"ipv4_addresses"`
+	IPv4StaticAddresses []*IPv4Address       `yaml:"ipv4_static_addresses" json:"ipv4_static_addresses" xml:"ipv4_static_addresses"`
+	IPv6Addresses       []*IPv6Address       `yaml:"ipv6_addresses" json:"ipv6_addresses" xml:"ipv6_addresses"`
+	IPv6StaticAddresses []*IPv6Address       `yaml:"ipv6_static_addresses" json:"ipv6_static_addresses" xml:"ipv6_static_addresses"`
+	IPv6DefaultGateway  string               `yaml:"ipv6_default_gateway" json:"ipv6_default_gateway" xml:"ipv6_default_gateway"`
+	NameServers         []string             `yaml:"name_servers" json:"name_servers" xml:"name_servers"`
+	StaticNameServers   []string             `yaml:"static_name_servers" json:"static_name_servers" xml:"static_name_servers"`
+	VLAN                *VLAN                `yaml:"vlan" json:"vlan" xml:"vlan"`
+	Status              HealthStatus         `yaml:"status" json:"status" xml:"status"`
+}
+
+// DHCPv4Configuration is the DHCPv4 settings of an interface.
+type DHCPv4Configuration struct {
+	DHCPEnabled   *bool `yaml:"dhcp_enabled" json:"dhcp_enabled" xml:"dhcp_enabled"`
+	UseDNSServers *bool `yaml:"use_dns_servers" json:"use_dns_servers" xml:"use_dns_servers"`
+	UseDomainName *bool `yaml:"use_domain_name" json:"use_domain_name" xml:"use_domain_name"`
+	UseGateway    *bool `yaml:"use_gateway" json:"use_gateway" xml:"use_gateway"`
+	UseNTPServers *bool `yaml:"use_ntp_servers" json:"use_ntp_servers" xml:"use_ntp_servers"`
+}
+
+// DHCPv6Configuration is the DHCPv6 settings of an interface. The operating
+// mode is either Stateful, Stateless, or Disabled.
+type DHCPv6Configuration struct {
+	OperatingMode string `yaml:"operating_mode" json:"operating_mode" xml:"operating_mode"`
+	UseDNSServers *bool  `yaml:"use_dns_servers" json:"use_dns_servers" xml:"use_dns_servers"`
+	UseDomainName *bool  `yaml:"use_domain_name" json:"use_domain_name" xml:"use_domain_name"`
+	UseNTPServers *bool  `yaml:"use_ntp_servers" json:"use_ntp_servers" xml:"use_ntp_servers"`
+}
+
+// IPv4Address is an IPv4 address of an interface. The address origin, e.g.
+// Static or DHCP, is read-only.
+type IPv4Address struct {
+	Address       string `yaml:"address" json:"address" xml:"address"`
+	SubnetMask    string `yaml:"subnet_mask" json:"subnet_mask" xml:"subnet_mask"`
+	Gateway       string `yaml:"gateway" json:"gateway" xml:"gateway"`
+	AddressOrigin string `yaml:"address_origin" json:"address_origin" xml:"address_origin"`
+}
+
+// IPv6Address is an IPv6 address of an interface. The address origin and
+// the address state are read-only.
+type IPv6Address struct {
+	Address       string `yaml:"address" json:"address" xml:"address"`
+	PrefixLength  *int   `yaml:"prefix_length" json:"prefix_length" xml:"prefix_length"`
+	AddressOrigin string `yaml:"address_origin" json:"address_origin" xml:"address_origin"`
+	AddressState  string `yaml:"address_state" json:"address_state" xml:"address_state"`
+}
+
+// VLAN is the VLAN settings of an interface.
+type VLAN struct {
+	VLANEnable *bool `yaml:"vlan_enable" json:"vlan_enable" xml:"vlan_enable"`
+	VLANID     *int  `yaml:"vlan_id" json:"vlan_id" xml:"vlan_id"`
+}
+
+// GetManagerEthernetInterfaces returns the network interfaces of a manager,
+// e.g. iDRAC.Embedded.1.
+func (cli *Client) GetManagerEthernetInterfaces(managerID string) ([]*EthernetInterface, error) {
+	interfaces := []*EthernetInterface{}
+	members, err := cli.getCollectionMembers(cli.rootPath + "Managers/" + managerID + "/EthernetInterfaces")
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		resp, err := cli.callAPI("GET", "", member, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		iface, err := newEthernetInterfaceFromBytes(resp)
+		if err != nil {
+			return nil, err
+		}
+		interfaces = append(interfaces, iface)
+	}
+	return interfaces, nil
+}
+
+// GetManagerEthernetInterface returns a network interface of a manager,
+// e.g. NIC.1 of iDRAC.Embedded.1.
+func (cli *Client) GetManagerEthernetInterface(managerID, interfaceID string) (*EthernetInterface, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"Managers/"+managerID+"/EthernetInterfaces/"+interfaceID, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newEthernetInterfaceFromBytes(resp)
+}
+
+// SetManagerEthernetInterface changes a network interface of a manager. The
+// nil fields and the empty strings of the changes remain unchanged. The
+// changes of the address of the interface the client connects to may cut
+// the connection, see ChangeManagerEthernetInterface.
+func (cli *Client) SetManagerEthernetInterface(managerID, interfaceID string, changes *EthernetInterface) error {
+	patch := make(map[string]interface{})
+	addSetting(patch, "InterfaceEnabled", changes.InterfaceEnabled)
+	if changes.HostName != "" {
+		patch["HostName"] = changes.HostName
+	}
+	if changes.FQDN != "" {
+		patch["FQDN"] = changes.FQDN
+	}
+	if changes.DHCPv4 != nil {
+		settings := make(map[string]interface{})
+		addSetting(settings, "DHCPEnabled", changes.DHCPv4.DHCPEnabled)
+		addSetting(settings, "UseDNSServers", changes.DHCPv4.UseDNSServers)
+		addSetting(settings, "UseDomainName", changes.DHCPv4.UseDomainName)
+		addSetting(settings, "UseGateway", changes.DHCPv4.UseGateway)
+		addSetting(settings, "UseNTPServers", changes.DHCPv4.UseNTPServers)
+		if len(settings) > 0 {
+			patch["DHCPv4"] = settings
+		}
+	}
+	if changes.DHCPv6 != nil {
+		settings := make(map[string]interface{})
+		if changes.DHCPv6.OperatingMode != "" {
+			settings["OperatingMode"] = changes.DHCPv6.OperatingMode
+		}
+		addSetting(settings, "UseDNSServers", changes.DHCPv6.UseDNSServers)
+		addSetting(settings, "UseDomainName", changes.DHCPv6.UseDomainName)
+		addSetting(settings, "UseNTPServers", changes.DHCPv6.UseNTPServers)
+		if len(settings) > 0 {
+			patch["DHCPv6"] = settings
+		}
+	}
+	if changes.IPv4StaticAddresses != nil {
+		addresses := []map[string]interface{}{}
+		for _, address := range changes.IPv4StaticAddresses {
+			entry := make(map[string]interface{})
+			for k, v := range map[string]string{
+				"Address":    address.Address,
+				"SubnetMask": address.SubnetMask,
+				"Gateway":    address.Gateway,
+			} {
+				if v != "" {
+					entry[k] = v
+				}
+			}
+			addresses = append(addresses, entry)
+		}
+		patch["IPv4StaticAddresses"] = addresses
+	}
+	if changes.IPv6StaticAddresses != nil {
+		addresses := []map[string]interface{}{}
+		for _, address := range changes.IPv6StaticAddresses {
+			entry := make(map[string]interface{})
+			if address.Address != "" {
+				entry["Address"] = address.Address
+			}
+			addSetting(entry, "PrefixLength", address.PrefixLength)
+			addresses = append(addresses, entry)
+		}
+		patch["IPv6StaticAddresses"] = addresses
+	}
+	if changes.IPv6DefaultGateway != "" {
+		patch["IPv6DefaultGateway"] = changes.IPv6DefaultGateway
+	}
+	if changes.StaticNameServers != nil {
+		patch["StaticNameServers"] = changes.StaticNameServers
+	}
+	if changes.VLAN != nil {
+		settings := make(map[string]interface{})
+		addSetting(settings, "VLANEnable", changes.VLAN.VLANEnable)
+		addSetting(settings, "VLANId", changes.VLAN.VLANID)
+		if len(settings) > 0 {
+			patch["VLAN"] = settings
+		}
+	}
+	if len(patch) == 0 {
+		return fmt.Errorf("no ethernet interface changes")
+	}
+	_, err := cli.patchResource(cli.rootPath+"Managers/"+managerID+"/EthernetInterfaces/"+interfaceID, patch)
+	return err
+}
+
+// ChangeManagerEthernetInterface changes a network interface of a manager
+// and confirms the changes by reconnecting to the manager. The client
+// reconnects to the host, or, when the host is empty, to the first static
+// IPv4 address of the changes, or to the current host. The changes are
+// confirmed once the interface responds on the host with the settings of the
+// changes, e.g. the static addresses, the DHCP mode, the host name, the DNS
+// servers, and the VLAN. The function returns the client of the host along
+// with the interface.
+func (cli *Client) ChangeManagerEthernetInterface(ctx context.Context, managerID, interfaceID string, changes *EthernetInterface, host string) (*Client, *EthernetInterface, error) {
+	if err := cli.SetManagerEthernetInterface(managerID, interfaceID, changes); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			return nil, nil, err
+		}
+		// The change of the address cuts the connection before the response.
+		log.Debugf("ethernet interface %s of %s: %s", interfaceID, cli.host, err)
+	}
+	if host == "" && len(changes.IPv4StaticAddresses) > 0 {
+		host = changes.IPv4StaticAddresses[0].Address
+	}
+	reconnected := cli.Clone()
+	if host != "" {
+		if err := reconnected.SetHost(host); err != nil {
+			return nil, nil, err
+		}
+	}
+	var lastErr error
+	for {
+		iface, err := reconnected.GetManagerEthernetInterface(managerID, interfaceID)
+		if err == nil {
+			pending := iface.pendingSettings(changes)
+			if len(pending) == 0 {
+				return reconnected, iface, nil
+			}
+			err = fmt.Errorf("the interface does not use %s yet", strings.Join(pending, ", "))
+		}
+		lastErr = err
+		log.Debugf("ethernet interface %s of %s: %s", interfaceID, reconnected.host, err)
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("failed confirming the changes of ethernet interface %s on %s: %s", interfaceID, reconnected.host, lastErr)
+		case <-time.After(cli.taskPollInterval):
+		}
+	}
+}
+
+// pendingSettings returns the names of the settings of the changes the
+// interface does not use yet. The nil fields and the empty strings of the
+// changes are not compared.
+func (iface *EthernetInterface) pendingSettings(changes *EthernetInterface) []string {
+	pending := []string{}
+	compare := func(name string, current, desired interface{}) {
+		d := formatProtocolValue(desired)
+		if d == "" || d == formatProtocolValue(current) {
+			return
+		}
+		pending = append(pending, name)
+	}
+	compare("interface_enabled", iface.InterfaceEnabled, changes.InterfaceEnabled)
+	compare("host_name", iface.HostName, changes.HostName)
+	compare("fqdn", iface.FQDN, changes.FQDN)
+	if changes.DHCPv4 != nil {
+		current := iface.DHCPv4
+		if current == nil {
+			current = &DHCPv4Configuration{}
+		}
+		compare("dhcpv4.dhcp_enabled", current.DHCPEnabled, changes.DHCPv4.DHCPEnabled)
+		compare("dhcpv4.use_dns_servers", current.UseDNSServers, changes.DHCPv4.UseDNSServers)
+		compare("dhcpv4.use_domain_name", current.UseDomainName, changes.DHCPv4.UseDomainName)
+		compare("dhcpv4.use_gateway", current.UseGateway, changes.DHCPv4.UseGateway)
+		compare("dhcpv4.use_ntp_servers", current.UseNTPServers, changes.DHCPv4.UseNTPServers)
+	}
+	if changes.DHCPv6 != nil {
+		current := iface.DHCPv6
+		if current == nil {
+			current = &DHCPv6Configuration{}
+		}
+		compare("dhcpv6.operating_mode", current.OperatingMode, changes.DHCPv6.OperatingMode)
+		compare("dhcpv6.use_dns_servers", current.UseDNSServers, changes.DHCPv6.UseDNSServers)
+		compare("dhcpv6.use_domain_name", current.UseDomainName, changes.DHCPv6.UseDomainName)
+		compare("dhcpv6.use_ntp_servers", current.UseNTPServers, changes.DHCPv6.UseNTPServers)
+	}
+	for _, address := range changes.IPv4StaticAddresses {
+		if address.Address != "" && !iface.hasIPv4Address(address.Address) {
+			pending = append(pending, "ipv4_static_addresses")
+			break
+		}
+	}
+	for _, address := range changes.IPv6StaticAddresses {
+		if address.Address != "" && !iface.hasIPv6Address(address.Address) {
+			pending = append(pending, "ipv6_static_addresses")
+			break
+		}
+	}
+	compare("ipv6_default_gateway", iface.IPv6DefaultGateway, changes.IPv6DefaultGateway)
+	if changes.StaticNameServers != nil {
+		if !equalStrings(trimUnsetAddresses(iface.StaticNameServers), trimUnsetAddresses(changes.StaticNameServers)) {
+			pending = append(pending, "static_name_servers")
+		}
+	}
+	if changes.VLAN != nil {
+		current := iface.VLAN
+		if current == nil {
+			current = &VLAN{}
+		}
+		compare("vlan.vlan_enable", current.VLANEnable, changes.VLAN.VLANEnable)
+		compare("vlan.vlan_id", current.VLANID, changes.VLAN.VLANID)
+	}
+	return pending
+}
+
+// hasIPv4Address returns true when the interface uses the IPv4 address.
+func (iface *EthernetInterface) hasIPv4Address(address string) bool {
+	for _, entry := range iface.IPv4Addresses {
+		if entry.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+// hasIPv6Address returns true when the interface uses the IPv6 address.
+func (iface *EthernetInterface) hasIPv6Address(address string) bool {
+	for _, entry := range iface.IPv6Addresses {
+		if entry.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+// trimUnsetAddresses returns the addresses without the unused slots, e.g.
+// iDRAC reports unused DNS servers as "::" or "0.0.0.0".
+func trimUnsetAddresses(addresses []string) []string {
+	result := []string{}
+	for _, address := range addresses {
+		if address != "" && address != "::" && address != "0.0.0.0" {
+			result = append(result, address)
+		}
+	}
+	return result
+}
+
+// newEthernetInterfaceFromString returns EthernetInterface instance from an
+// input string.
+func newEthernetInterfaceFromString(s string) (*EthernetInterface, error) {
+	return newEthernetInterfaceFromBytes([]byte(s))
+}
+
+// newEthernetInterfaceFromBytes returns EthernetInterface instance from an
+// input byte array.
+func newEthernetInterfaceFromBytes(s []byte) (*EthernetInterface, error) {
+	response := &ethernetInterfaceResponse{}
+	if err := json.Unmarshal(s, response); err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the ethernet interface is empty, server response: %s", string(s[:]))
+	}
+	iface := &EthernetInterface{
+		ID: response.ID,
+		OData: &ODataAnnotation{
+			Context: response.Context,
+			ID:      response.ODataAnnotation.ID,
+			Type:    response.Type,
+		},
+		Name:                response.Name,
+		InterfaceEnabled:    response.InterfaceEnabled,
+		MACAddress:          response.MACAddress,
+		HostName:            response.HostName,
+		FQDN:                response.FQDN,
+		SpeedMbps:           response.SpeedMbps,
+		IPv4Addresses:       newIPv4Addresses(response.IPv4Addresses),
+		IPv4StaticAddresses: newIPv4Addresses(response.IPv4StaticAddresses),
+		IPv6Addresses:       newIPv6Addresses(response.IPv6Addresses),
+		IPv6StaticAddresses: newIPv6Addresses(response.IPv6StaticAddresses),
+		IPv6DefaultGateway:  response.IPv6DefaultGateway,
+		NameServers:         nonNilStrings(response.NameServers),
+		StaticNameServers:   nonNilStrings(response.StaticNameServers),
+		Status:              response.Status,
+	}
+	if response.DHCPv4 != nil {
+		iface.DHCPv4 = &DHCPv4Configuration{
+			DHCPEnabled:   response.DHCPv4.DHCPEnabled,
+			UseDNSServers: response.DHCPv4.UseDNSServers,
+			UseDomainName: response.DHCPv4.UseDomainName,
+			UseGateway:    response.DHCPv4.UseGateway,
+			UseNTPServers: response.DHCPv4.UseNTPServers,
+		}
+	}
+	if response.DHCPv6 != nil {
+		iface.DHCPv6 = &DHCPv6Configuration{
+			OperatingMode: response.DHCPv6.OperatingMode,
+			UseDNSServers: response.DHCPv6.UseDNSServers,
+			UseDomainName: response.DHCPv6.UseDomainName,
+			UseNTPServers: response.DHCPv6.UseNTPServers,
+		}
+	}
+	if response.VLAN != nil {
+		iface.VLAN = &VLAN{
+			VLANEnable: response.VLAN.VLANEnable,
+			VLANID:     response.VLAN.VLANID,
+		}
+	}
+	return iface, nil
+}
+
+// newIPv4Addresses returns the addresses of a response without nil
+// entries.
+func newIPv4Addresses(response []*ipv4AddressResponse) []*IPv4Address {
+	addresses := []*IPv4Address{}
+	for _, entry := range response {
+		if entry == nil {
+			continue
+		}
+		addresses = append(addresses, &IPv4Address{
+			Address:       entry.Address,
+			SubnetMask:    entry.SubnetMask,
+			Gateway:       entry.Gateway,
+			AddressOrigin: entry.AddressOrigin,
+		})
+	}
+	return addresses
+}
+
+// newIPv6Addresses returns the addresses of a response without nil
+// entries.
+func newIPv6Addresses(response []*ipv6AddressResponse) []*IPv6Address {
+	addresses := []*IPv6Address{}
+	for _, entry := range response {
+		if entry == nil {
+			continue
+		}
+		addresses = append(addresses, &IPv6Address{
+			Address:       entry.Address,
+			PrefixLength:  entry.PrefixLength,
+			AddressOrigin: entry.AddressOrigin,
+			AddressState:  entry.AddressState,
+		})
+	}
+	return addresses
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"context"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestManagerEthernetInterfaces(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+	cli.SetTaskPollInterval(time.Millisecond)
+
+	interfaces, err := cli.GetManagerEthernetInterfaces("iDRAC.Embedded.1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(interfaces) != 1 {
+		t.Fatalf("client: expected 1 ethernet interface, but got %d", len(interfaces))
+	}
+	iface := interfaces[0]
+	if iface.ID != "NIC.1" || iface.HostName != "idrac-24A8VC9" || iface.MACAddress != "14:02:ec:3f:a1:b2" || iface.SpeedMbps != 1000 {
+		t.Fatalf("client: unexpected ethernet interface: %+v", *iface)
+	}
+	expAddresses := []*IPv4Address{
+		{Address: "192.168.0.120", SubnetMask: "255.255.255.0", Gateway: "192.168.0.1", AddressOrigin: "Static"},
+	}
+	if !reflect.DeepEqual(iface.IPv4Addresses, expAddresses) || !reflect.DeepEqual(iface.IPv4StaticAddresses, expAddresses) {
+		t.Fatalf("client: unexpected IPv4 addresses: %+v", iface.IPv4Addresses[0])
+	}
+	if *iface.DHCPv4.DHCPEnabled || iface.DHCPv6.OperatingMode != "Disabled" {
+		t.Fatalf("client: unexpected DHCP settings: %+v, %+v", *iface.DHCPv4, *iface.DHCPv6)
+	}
+	if len(iface.IPv6Addresses) != 1 || *iface.IPv6Addresses[0].PrefixLength != 64 || iface.IPv6Addresses[0].AddressOrigin != "LinkLocal" {
+		t.Fatalf("client: unexpected IPv6 addresses: %+v", iface.IPv6Addresses)
+	}
+	if *iface.VLAN.VLANEnable || *iface.VLAN.VLANID != 1 || iface.StaticNameServers[0] != "192.168.0.53" {
+		t.Fatalf("client: unexpected VLAN and DNS settings: %+v, %v", *iface.VLAN, iface.StaticNameServers)
+	}
+	for _, resource := range []interface{}{iface.IPv4Addresses[0], iface.IPv6Addresses[0], iface.DHCPv4} {
+		complianceMessages, compliant := isStructCompliant(resource)
+		if !compliant {
+			for _, entry := range complianceMessages {
+				t.Logf("%s", entry)
+			}
+			t.Fatalf("client: %T is not compliant", resource)
+		}
+	}
+	if _, err := cli.GetManagerEthernetInterface("iDRAC.Embedded.1", "NIC.1"); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if _, err := newEthernetInterfaceFromString(`{"HostName": "idrac"}`); err == nil {
+		t.Fatalf("client: expected failure due to empty Id, but got non-error response")
+	}
+	if err := cli.SetManagerEthernetInterface("iDRAC.Embedded.1", "NIC.1", &EthernetInterface{VLAN: &VLAN{}}); err == nil {
+		t.Fatalf("client: expected failure due to no changes, but got non-error response")
+	}
+
+	disabled, vlanID, defaultVLANID := false, 100, 1
+	testFailed := 0
+	for i, test := range []struct {
+		changes   *EthernetInterface
+		host      string
+		shouldErr bool
+	}{
+		{
+			// The host is the address of the changes, i.e. the interface
+			// responds on the address.
+			changes: &EthernetInterface{
+				DHCPv4:              &DHCPv4Configuration{DHCPEnabled: &disabled},
+				IPv4StaticAddresses: []*IPv4Address{{Address: "192.168.0.120", SubnetMask: "255.255.255.0", Gateway: "192.168.0.1"}},
+				StaticNameServers:   []string{"192.168.0.53", "192.168.1.53"},
+			},
+			host: server.NonTLS.Hostname,
+		},
+		{
+			// The interface uses the host name and the VLAN of the changes.
+			changes: &EthernetInterface{
+				HostName: "idrac-24A8VC9",
+				VLAN:     &VLAN{VLANEnable: &disabled, VLANID: &defaultVLANID},
+			},
+		},
+		{
+			// The interface does not use the host name and the VLAN of the
+			// changes, e.g. the manager has not applied them yet.
+			changes: &EthernetInterface{
+				HostName: "idrac-web01",
+				VLAN:     &VLAN{VLANID: &vlanID},
+			},
+			shouldErr: true,
+		},
+		{
+			// The interface does not use the DNS servers of the changes.
+			changes: &EthernetInterface{
+				StaticNameServers: []string{"10.0.0.53"},
+			},
+			shouldErr: true,
+		},
+		{
+			// The interface does not use the DHCP mode of the changes.
+			changes: &EthernetInterface{
+				DHCPv6: &DHCPv6Configuration{OperatingMode: "Stateful"},
+			},
+			shouldErr: true,
+		},
+		{
+			// The interface does not use the address of the changes.
+			changes: &EthernetInterface{
+				IPv4StaticAddresses: []*IPv4Address{{Address: "192.168.0.121"}},
+			},
+			host:      server.NonTLS.Hostname,
+			shouldErr: true,
+		},
+		{
+			// The interface does not respond on the address.
+			changes: &EthernetInterface{
+				IPv4StaticAddresses: []*IPv4Address{{Address: "127.0.0.2"}},
+			},
+			shouldErr: true,
+		},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		reconnected, iface, err := cli.ChangeManagerEthernetInterface(ctx, "iDRAC.Embedded.1", "NIC.1", test.changes, test.host)
+		cancel()
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: expected success, but got error: %s", i, err)
+				testFailed++
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: expected failure, but got non-error response", i)
+			testFailed++
+			continue
+		}
+		if reconnected == nil || iface == nil || iface.ID != "NIC.1" {
+			t.Logf("FAIL: Test %d: unexpected reconnection result", i)
+			testFailed++
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
@@ -0,0 +1,374 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MessageRegistryDataLimit is the limit of data in bytes the client will
+// read from a server when fetching a message registry. The iDRAC message
+// registry exceeds ReceiverDataLimit.
+const MessageRegistryDataLimit int64 = 64e6
+
+// Message is an entry of Redfish @Message.ExtendedInfo, i.e. the details
+// of an error, or of the outcome of an operation.
+type Message struct {
+	MessageID   string   `yaml:"message_id" json:"message_id" xml:"message_id"`
+	Message     string   `yaml:"message" json:"message" xml:"message"`
+	MessageArgs []string `yaml:"message_args" json:"message_args" xml:"message_args"`
+	Severity    string   `yaml:"severity" json:"severity" xml:"severity"`
+	Resolution  string   `yaml:"resolution" json:"resolution" xml:"resolution"`
+}
+
+type messageRegistryFileResponse struct {
+	ODataAnnotation
+	ID       string `json:"Id"`
+	Registry string
+	Location []struct {
+		Language       string
+		URI            string `json:"Uri"`
+		PublicationURI string `json:"PublicationUri"`
+	}
+}
+
+type messageRegistryResponse struct {
+	ODataAnnotation
+	ID              string `json:"Id"`
+	Name            string
+	Language        string
+	RegistryPrefix  string
+	RegistryVersion string
+	Messages        map[string]struct {
+		Description  string
+		Message      string
+		Severity     string
+		Resolution   string
+		NumberOfArgs int
+		ParamTypes   []string
+	}
+}
+
+// MessageRegistry represents an instance of Redfish MessageRegistry
+// resource, i.e. the messages of a registry prefix, e.g. IDRAC or Base.
+type MessageRegistry struct {
+	ID              string                        `yaml:"id" json:"id" xml:"id"`
+	Name            string                        `yaml:"name" json:"name" xml:"name"`
+	Language        string                        `yaml:"language" json:"language" xml:"language"`
+	RegistryPrefix  string                        `yaml:"registry_prefix" json:"registry_prefix" xml:"registry_prefix"`
+	RegistryVersion string                        `yaml:"registry_version" json:"registry_version" xml:"registry_version"`
+	Messages        map[string]*MessageDefinition `yaml:"messages" json:"messages" xml:"-"`
+}
+
+// MessageDefinition is a message of a message registry. The message has
+// placeholders, i.e. %1, %2, for the arguments.
+type MessageDefinition struct {
+	Description  string   `yaml:"description" json:"description" xml:"description"`
+	Message      string   `yaml:"message" json:"message" xml:"message"`
+	Severity     string   `yaml:"severity" json:"severity" xml:"severity"`
+	Resolution   string   `yaml:"resolution" json:"resolution" xml:"resolution"`
+	NumberOfArgs int      `yaml:"number_of_args" json:"number_of_args" xml:"number_of_args"`
+	ParamTypes   []string `yaml:"param_types" json:"param_types" xml:"param_types"`
+}
+
+// MessageRegistries resolves message ids, e.g. IDRAC.2.1.SYS403, into the
+// messages of message registries. The registries fetched from hosts are
+// cached in a directory, i.e. a registry is fetched once per version.
+type MessageRegistries struct {
+	cacheDir   string
+	mu         sync.RWMutex
+	registries map[string]*MessageRegistry
+}
+
+// DefaultMessageRegistryCacheDir returns the directory caching message
+// registries, i.e. go-redfish-api-idrac/registries in the user cache
+// directory.
+func DefaultMessageRegistryCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "go-redfish-api-idrac", "registries")
+}
+
+// NewMessageRegistries returns an instance of MessageRegistries with the
+// registries cached in a directory. The empty directory disables caching.
+func NewMessageRegistries(cacheDir string) (*MessageRegistries, error) {
+	r := &MessageRegistries{
+		cacheDir:   cacheDir,
+		registries: make(map[string]*MessageRegistry),
+	}
+	if cacheDir == "" {
+		return r, nil
+	}
+	files, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, fp := range files {
+		b, err := ioutil.ReadFile(fp)
+		if err != nil {
+			return nil, err
+		}
+		registry, err := newMessageRegistryFromBytes(b)
+		if err != nil {
+			log.Warnf("skipped cached message registry %s: %s", fp, err)
+			continue
+		}
+		r.registries[strings.TrimSuffix(filepath.Base(fp), ".json")] = registry
+	}
+	return r, nil
+}
+
+// Add adds a message registry under its id, e.g. IDRAC.2.1.
+func (r *MessageRegistries) Add(id string, registry *MessageRegistry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registries[id] = registry
+}
+
+// Has returns true when the registry with the id is known.
+func (r *MessageRegistries) Has(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, exists := r.registries[id]
+	return exists
+}
+
+// GetMessageRegistries returns the message registries of the host. The
+// registries missing from the cache directory are fetched from the host
+// and cached.
+func (cli *Client) GetMessageRegistries(cacheDir string) (*MessageRegistries, error) {
+	r, err := NewMessageRegistries(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := cli.FetchMessageRegistries(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// FetchMessageRegistries fetches the message registries of the host
+// missing from the registries.
+func (cli *Client) FetchMessageRegistries(r *MessageRegistries) error {
+	members, err := cli.getCollectionMembers(cli.rootPath + "Registries")
+	if err != nil {
+		return err
+	}
+	fetcher := cli.Clone()
+	fetcher.dataLimit = MessageRegistryDataLimit
+	for _, member := range members {
+		resp, err := cli.callAPI("GET", "", member, []byte{})
+		if err != nil {
+			return err
+		}
+		file := &messageRegistryFileResponse{}
+		if err := json.Unmarshal(resp, file); err != nil {
+			return fmt.Errorf("parsing error: %s, server response: %s", err, string(resp))
+		}
+		if file.Registry == "" || r.Has(file.Registry) {
+			continue
+		}
+		location := ""
+		for _, entry := range file.Location {
+			if entry.URI == "" {
+				continue
+			}
+			if location == "" || strings.EqualFold(entry.Language, "en") {
+				location = entry.URI
+			}
+		}
+		if location == "" {
+			log.Debugf("message registry %s of %s has no local copy", file.Registry, cli.host)
+			continue
+		}
+		resp, err = fetcher.callAPI("GET", "", location, []byte{})
+		if err != nil {
+			return err
+		}
+		registry, err := newMessageRegistryFromBytes(resp)
+		if err != nil {
+			return err
+		}
+		r.Add(file.Registry, registry)
+		if err := r.store(file.Registry, resp); err != nil {
+			log.Warnf("failed caching message registry %s: %s", file.Registry, err)
+		}
+	}
+	return nil
+}
+
+// store writes a registry to the cache directory.
+func (r *MessageRegistries) store(id string, b []byte) error {
+	if r.cacheDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(r.cacheDir, 0700); err != nil {
+		return err
+	}
+	fp := filepath.Join(r.cacheDir, filepath.Base(id)+".json")
+	tmp := fp + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fp)
+}
+
+// lookup returns the message definition of a message id. The message ids
+// are either qualified, i.e. prefix, major and minor version, and key,
+// e.g. IDRAC.2.1.SYS403, or a key only, e.g. SYS403. A qualified id
+// resolves against the registry of the prefix with the closest version.
+func (r *MessageRegistries) lookup(messageID string) (*MessageDefinition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := []string{}
+	for id := range r.registries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parts := strings.Split(messageID, ".")
+	key := parts[len(parts)-1]
+	if len(parts) == 1 {
+		for _, id := range ids {
+			if m, exists := r.registries[id].Messages[key]; exists {
+				return m, nil
+			}
+		}
+		return nil, fmt.Errorf("message %s not found in message registries", messageID)
+	}
+
+	prefix := parts[0]
+	version := strings.Join(parts[1:len(parts)-1], ".")
+	var fallback *MessageDefinition
+	for _, id := range ids {
+		registry := r.registries[id]
+		if registry.RegistryPrefix != prefix {
+			continue
+		}
+		m, exists := registry.Messages[key]
+		if !exists {
+			continue
+		}
+		if version != "" && (registry.RegistryVersion == version || strings.HasPrefix(registry.RegistryVersion, version+".")) {
+			return m, nil
+		}
+		if fallback == nil {
+			fallback = m
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("message %s not found in message registries", messageID)
+	}
+	return fallback, nil
+}
+
+// Resolve returns the message of a message id with the arguments.
+func (r *MessageRegistries) Resolve(messageID string, args []string) (*Message, error) {
+	m, err := r.lookup(messageID)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		MessageID:   messageID,
+		Message:     formatMessage(m.Message, args),
+		MessageArgs: nonNilStrings(args),
+		Severity:    m.Severity,
+		Resolution:  m.Resolution,
+	}, nil
+}
+
+// ResolveMessage sets the message, the severity, and the resolution of
+// the message, when they are empty.
+func (r *MessageRegistries) ResolveMessage(m *Message) error {
+	resolved, err := r.Resolve(m.MessageID, m.MessageArgs)
+	if err != nil {
+		return err
+	}
+	if m.Message == "" {
+		m.Message = resolved.Message
+	}
+	if m.Severity == "" {
+		m.Severity = resolved.Severity
+	}
+	if m.Resolution == "" {
+		m.Resolution = resolved.Resolution
+	}
+	return nil
+}
+
+// ResolveEvent sets the message, the severity, and the resolution of the
+// records of the event, when they are empty. The records with unknown
+// message ids remain unchanged.
+func (r *MessageRegistries) ResolveEvent(event *Event) {
+	for _, record := range event.Events {
+		if record.MessageID == "" {
+			continue
+		}
+		resolved, err := r.Resolve(record.MessageID, record.MessageArgs)
+		if err != nil {
+			log.Debugf("failed resolving event %s: %s", record.EventID, err)
+			continue
+		}
+		if record.Message == "" {
+			record.Message = resolved.Message
+		}
+		if record.Severity == "" {
+			record.Severity = resolved.Severity
+		}
+		if record.Resolution == "" {
+			record.Resolution = resolved.Resolution
+		}
+	}
+}
+
+// formatMessage replaces the placeholders of a message, i.e. %1, %2, with
+// the arguments.
+func formatMessage(s string, args []string) string {
+	// The placeholders with more digits go first, i.e. %10 before %1.
+	for i := len(args); i > 0; i-- {
+		s = strings.Replace(s, "%"+strconv.Itoa(i), args[i-1], -1)
+	}
+	return s
+}
+
+// newMessageRegistryFromBytes returns MessageRegistry instance from an input byte array.
+func newMessageRegistryFromBytes(s []byte) (*MessageRegistry, error) {
+	response := &messageRegistryResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.RegistryPrefix == "" || len(response.Messages) == 0 {
+		return nil, fmt.Errorf("parsing error: the message registry has no prefix or messages, server response: %s", string(s[:]))
+	}
+	registry := &MessageRegistry{
+		ID:              response.ID,
+		Name:            response.Name,
+		Language:        response.Language,
+		RegistryPrefix:  response.RegistryPrefix,
+		RegistryVersion: response.RegistryVersion,
+		Messages:        make(map[string]*MessageDefinition),
+	}
+	for key, m := range response.Messages {
+		registry.Messages[key] = &MessageDefinition{
+			Description:  m.Description,
+			Message:      m.Message,
+			Severity:     m.Severity,
+			Resolution:   m.Resolution,
+			NumberOfArgs: m.NumberOfArgs,
+			ParamTypes:   nonNilStrings(m.ParamTypes),
+		}
+	}
+	return registry, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"errors"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolveMessages(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	cacheDir := filepath.Join(t.TempDir(), "registries")
+	registries, err := cli.GetMessageRegistries(cacheDir)
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	for _, id := range []string{"Base.1.5", "IDRAC.2.1"} {
+		if _, err := os.Stat(filepath.Join(cacheDir, id+".json")); err != nil {
+			t.Fatalf("client: expected cached message registry %s, but got error: %s", id, err)
+		}
+	}
+
+	// The cached registries resolve messages without a host.
+	cached, err := NewMessageRegistries(cacheDir)
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+
+	testFailed := 0
+	for _, r := range []*MessageRegistries{registries, cached} {
+		for i, test := range []struct {
+			messageID string
+			args      []string
+			exp       *Message
+			shouldErr bool
+		}{
+			{
+				messageID: "IDRAC.2.1.SYS403",
+				args:      []string{"/redfish/v1/Unknown"},
+				exp: &Message{
+					MessageID:   "IDRAC.2.1.SYS403",
+					Message:     "Unable to complete the operation because the resource /redfish/v1/Unknown entered in not found.",
+					MessageArgs: []string{"/redfish/v1/Unknown"},
+					Severity:    "Critical",
+					Resolution:  "Enter the correct resource and retry the operation. For information about valid resource, see the Redfish Users Guide available on the support site.",
+				},
+			},
+			{
+				messageID: "Base.1.5.PropertyValueNotInList",
+				args:      []string{"Sometimes", "IndicatorLED"},
+				exp: &Message{
+					MessageID:   "Base.1.5.PropertyValueNotInList",
+					Message:     "The value Sometimes for the property IndicatorLED is not in the list of acceptable values.",
+					MessageArgs: []string{"Sometimes", "IndicatorLED"},
+					Severity:    "Warning",
+					Resolution:  "Choose a value from the enumeration list that the implementation can support and resubmit the request if the operation failed.",
+				},
+			},
+			{
+				// The registry with other minor version resolves the message.
+				messageID: "Base.1.0.Success",
+				exp: &Message{
+					MessageID:   "Base.1.0.Success",
+					Message:     "Successfully Completed Request",
+					MessageArgs: []string{},
+					Severity:    "OK",
+					Resolution:  "None",
+				},
+			},
+			{
+				messageID: "PSU0001",
+				args:      []string{"PS1"},
+				exp: &Message{
+					MessageID:   "PSU0001",
+					Message:     "Power supply PS1 is operating normally.",
+					MessageArgs: []string{"PS1"},
+					Severity:    "OK",
+					Resolution:  "No response action is required.",
+				},
+			},
+			{messageID: "IDRAC.2.1.UNKNOWN", shouldErr: true},
+			{messageID: "Unknown.1.0.SYS403", shouldErr: true},
+		} {
+			m, err := r.Resolve(test.messageID, test.args)
+			if err != nil {
+				if !test.shouldErr {
+					t.Logf("FAIL: Test %d: message %s, expected to pass, but threw error: %v", i, test.messageID, err)
+					testFailed++
+				}
+				continue
+			}
+			if test.shouldErr {
+				t.Logf("FAIL: Test %d: message %s, expected to throw error, but passed: %v", i, test.messageID, *m)
+				testFailed++
+				continue
+			}
+			if !reflect.DeepEqual(m, test.exp) {
+				t.Logf("FAIL: Test %d: message %s, expected to pass, but got value mismatch: '%v' (actual) vs. '%v' (expected)",
+					i, test.messageID, *m, *test.exp)
+				testFailed++
+			}
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+
+	// The events carry no resolution.
+	content, err := ioutil.ReadFile("../../assets/responses/event_1.json")
+	if err != nil {
+		t.Fatalf("failed reading event: %s", err)
+	}
+	event, err := NewEventFromBytes(content)
+	if err != nil {
+		t.Fatalf("failed parsing event: %s", err)
+	}
+	registries.ResolveEvent(event)
+	if event.Events[1].Resolution != "No response action is required." {
+		t.Fatalf("client: unexpected event resolution: %q", event.Events[1].Resolution)
+	}
+	if event.Events[1].Message != "The power supply is operating normally." {
+		t.Fatalf("client: expected event message unchanged, but got %q", event.Events[1].Message)
+	}
+
+	// The error responses carry the message ids of the errors.
+	_, err = cli.GetResource("/redfish/v12/")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("client: expected APIError, but got %v", err)
+	}
+	if apiErr.StatusCode != 404 || apiErr.Code != "Base.1.5.GeneralError" ||
+		len(apiErr.Messages) != 1 || apiErr.Messages[0].MessageID != "IDRAC.2.1.SYS420" {
+		t.Fatalf("client: unexpected APIError: %+v", *apiErr)
+	}
+	apiErr.Messages[0].Resolution = ""
+	registries.ResolveError(err)
+	if apiErr.Messages[0].Resolution == "" {
+		t.Fatalf("client: expected resolution of error message, but got none")
+	}
+}
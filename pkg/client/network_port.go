@@ -0,0 +1,131 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type networkInterfaceResponse struct {
+	ODataAnnotation
+	ID          string `json:"Id"`
+	Name        string
+	Description string
+	Links       struct {
+		NetworkAdapter ODataAnnotation
+	}
+	NetworkDeviceFunctions ODataAnnotation
+	NetworkPorts           ODataAnnotation
+	Status                 HealthStatus
+}
+
+type networkPortResponse struct {
+	ODataAnnotation
+	ID                                 string `json:"Id"`
+	Name                               string
+	Description                        string
+	ActiveLinkTechnology               string
+	AssociatedNetworkAddresses         []string
+	CurrentLinkSpeedMbps               uint64
+	EEEEnabled                         bool
+	FlowControlConfiguration           string
+	FlowControlStatus                  string
+	LinkStatus                         string
+	PhysicalPortNumber                 string
+	VendorID                           string `json:"VendorId"`
+	WakeOnLANEnabled                   bool
+	SupportedEthernetCapabilities      []string
+	SupportedEthernetCapabilitiesCount uint64 `json:"SupportedEthernetCapabilities@odata.count"`
+	Status                             HealthStatus
+}
+
+// NetworkPort represents an instance of Redfish NetworkPort, i.e. a physical
+// port of a network adapter.
+type NetworkPort struct {
+	ID                         string           `yaml:"id" json:"id" xml:"id"`
+	OData                      *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name                       string           `yaml:"name" json:"name" xml:"name"`
+	Description                string           `yaml:"description" json:"description" xml:"description"`
+	PhysicalPortNumber         string           `yaml:"physical_port_number" json:"physical_port_number" xml:"physical_port_number"`
+	ActiveLinkTechnology       string           `yaml:"active_link_technology" json:"active_link_technology" xml:"active_link_technology"`
+	AssociatedNetworkAddresses []string         `yaml:"associated_network_addresses" json:"associated_network_addresses" xml:"associated_network_addresses"`
+	CurrentLinkSpeedMbps       uint64           `yaml:"current_link_speed_mbps" json:"current_link_speed_mbps" xml:"current_link_speed_mbps"`
+	LinkStatus                 string           `yaml:"link_status" json:"link_status" xml:"link_status"`
+	Status                     HealthStatus     `yaml:"status" json:"status" xml:"status"`
+}
+
+// GetNetworkPorts returns the network ports of the network interfaces of
+// a computer system, e.g. System.Embedded.1.
+func (cli *Client) GetNetworkPorts(systemID string) ([]*NetworkPort, error) {
+	ports := []*NetworkPort{}
+	interfaces, err := cli.getCollectionMembers(cli.rootPath + "Systems/" + systemID + "/NetworkInterfaces/")
+	if err != nil {
+		return nil, err
+	}
+	for _, interfacePath := range interfaces {
+		resp, err := cli.callAPI("GET", "", interfacePath, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		response := &networkInterfaceResponse{}
+		if err := json.Unmarshal(resp, response); err != nil {
+			return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+		}
+		if response.NetworkPorts.ID == "" {
+			continue
+		}
+		portPaths, err := cli.getCollectionMembers(response.NetworkPorts.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, portPath := range portPaths {
+			port, err := cli.GetNetworkPortByResourceID(portPath)
+			if err != nil {
+				return nil, err
+			}
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
+
+// GetNetworkPortByResourceID returns an instance of Redfish NetworkPort.
+func (cli *Client) GetNetworkPortByResourceID(s string) (*NetworkPort, error) {
+	resp, err := cli.callAPI("GET", "", s, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newNetworkPortFromBytes(resp)
+}
+
+// newNetworkPortFromString returns NetworkPort instance from an input string.
+func newNetworkPortFromString(s string) (*NetworkPort, error) {
+	return newNetworkPortFromBytes([]byte(s))
+}
+
+// newNetworkPortFromBytes returns NetworkPort instance from an input byte array.
+func newNetworkPortFromBytes(s []byte) (*NetworkPort, error) {
+	response := &networkPortResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	port := &NetworkPort{
+		ID: response.ID,
+		OData: &ODataAnnotation{
+			Context: response.Context,
+			ID:      response.ODataAnnotation.ID,
+			Type:    response.Type,
+		},
+		Name:                       response.Name,
+		Description:                response.Description,
+		PhysicalPortNumber:         response.PhysicalPortNumber,
+		ActiveLinkTechnology:       response.ActiveLinkTechnology,
+		AssociatedNetworkAddresses: response.AssociatedNetworkAddresses,
+		CurrentLinkSpeedMbps:       response.CurrentLinkSpeedMbps,
+		LinkStatus:                 response.LinkStatus,
+		Status:                     response.Status,
+	}
+	return port, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"reflect"
+	"testing"
+)
+
+func TestGetNetworkPorts(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	ports, err := cli.GetNetworkPorts("System.Embedded.1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(ports) != 6 {
+		t.Fatalf("client: expected 6 network ports, but got %d", len(ports))
+	}
+
+	exp := &NetworkPort{
+		ID: "NIC.Integrated.1-1",
+		OData: NewODataAnnotation(
+			"/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Integrated.1/NetworkPorts/NIC.Integrated.1-1",
+			"#NetworkPort.v1_2_0.NetworkPort",
+			"/redfish/v1/$metadata#NetworkPort.NetworkPort",
+		),
+		Name:                       "Network Port View",
+		Description:                "Network Port View",
+		PhysicalPortNumber:         "1",
+		ActiveLinkTechnology:       "Ethernet",
+		AssociatedNetworkAddresses: []string{"B0:35:12:3A:21:8C"},
+		CurrentLinkSpeedMbps:       10000,
+		LinkStatus:                 "Up",
+		Status: HealthStatus{
+			Health:       "OK",
+			HealthRollup: "OK",
+			State:        "Enabled",
+		},
+	}
+	if !reflect.DeepEqual(ports[0], exp) {
+		t.Fatalf("client: value mismatch: '%v' (actual) vs. '%v' (expected)", *ports[0], *exp)
+	}
+	for _, port := range ports {
+		t.Logf("Port: %s | Link: %s | Speed: %d Mbps", port.ID, port.LinkStatus, port.CurrentLinkSpeedMbps)
+	}
+
+	complianceMessages, compliant := isStructCompliant(ports[0])
+	if !compliant {
+		for _, entry := range complianceMessages {
+			t.Logf("%s", entry)
+		}
+		t.Fatalf("client: NetworkPort struct is not compliant")
+	}
+
+	if _, err := newNetworkPortFromString("{"); err == nil {
+		t.Fatalf("client: expected failure, but got non-error response")
+	}
+	if _, err := cli.GetNetworkPorts("System.Embedded.2"); err == nil {
+		t.Fatalf("client: expected failure, but got non-error response")
+	}
+}
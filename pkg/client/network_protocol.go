@@ -0,0 +1,391 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+type networkProtocolServiceResponse struct {
+	ProtocolEnabled *bool
+	Port            *int
+}
+
+type networkProtocolResponse struct {
+	ODataAnnotation
+	ID           string `json:"Id"`
+	Name         string
+	HostName     string
+	FQDN         string
+	HTTP         *networkProtocolServiceResponse
+	HTTPS        *networkProtocolServiceResponse
+	SSH          *networkProtocolServiceResponse
+	IPMI         *networkProtocolServiceResponse
+	VirtualMedia *networkProtocolServiceResponse
+	KVMIP        *networkProtocolServiceResponse
+	Telnet       *networkProtocolServiceResponse
+	SNMP         *struct {
+		ProtocolEnabled *bool
+		Port            *int
+		EnableSNMPv1    *bool
+		EnableSNMPv2c   *bool
+		EnableSNMPv3    *bool
+	}
+	NTP *struct {
+		ProtocolEnabled *bool
+		NTPServers      []string
+	}
+	SSDP *struct {
+		ProtocolEnabled                *bool
+		Port                           *int
+		NotifyIPv6Scope                string
+		NotifyMulticastIntervalSeconds *int
+		NotifyTTL                      *int
+	}
+}
+
+// ManagerNetworkProtocol represents an instance of Redfish
+// ManagerNetworkProtocol, i.e. the network services of a manager, e.g.
+// iDRAC. In the changes of the settings, and in the policies, the nil
+// fields and the empty strings remain unchanged, or unchecked. The host
+// name and the FQDN are read-only.
+type ManagerNetworkProtocol struct {
+	ID           string                  `yaml:"id" json:"id" xml:"id"`
+	OData        *ODataAnnotation        `yaml:"odata" json:"odata" xml:"odata"`
+	HostName     string                  `yaml:"host_name" json:"host_name" xml:"host_name"`
+	FQDN         string                  `yaml:"fqdn" json:"fqdn" xml:"fqdn"`
+	HTTP         *NetworkProtocolService `yaml:"http" json:"http" xml:"http"`
+	HTTPS        *NetworkProtocolService `yaml:"https" json:"https" xml:"https"`
+	SSH          *NetworkProtocolService `yaml:"ssh" json:"ssh" xml:"ssh"`
+	IPMI         *NetworkProtocolService `yaml:"ipmi" json:"ipmi" xml:"ipmi"`
+	VirtualMedia *NetworkProtocolService `yaml:"virtual_media" json:"virtual_media" xml:"virtual_media"`
+	KVMIP        *NetworkProtocolService `yaml:"kvmip" json:"kvmip" xml:"kvmip"`
+	Telnet       *NetworkProtocolService `yaml:"telnet" json:"telnet" xml:"telnet"`
+	SNMP         *SNMPProtocol           `yaml:"snmp" json:"snmp" xml:"snmp"`
+	NTP          *NTPProtocol            `yaml:"ntp" json:"ntp" xml:"ntp"`
+	SSDP         *SSDPProtocol           `yaml:"ssdp" json:"ssdp" xml:"ssdp"`
+}
+
+// NetworkProtocolService is the state and the port of a network service,
+// e.g. SSH.
+type NetworkProtocolService struct {
+	ProtocolEnabled *bool `yaml:"protocol_enabled" json:"protocol_enabled" xml:"protocol_enabled"`
+	Port            *int  `yaml:"port" json:"port" xml:"port"`
+}
+
+// SNMPProtocol is the state, the port, and the versions of SNMP agent.
+type SNMPProtocol struct {
+	ProtocolEnabled *bool `yaml:"protocol_enabled" json:"protocol_enabled" xml:"protocol_enabled"`
+	Port            *int  `yaml:"port" json:"port" xml:"port"`
+	EnableSNMPv1    *bool `yaml:"enable_snmp_v1" json:"enable_snmp_v1" xml:"enable_snmp_v1"`
+	EnableSNMPv2c   *bool `yaml:"enable_snmp_v2c" json:"enable_snmp_v2c" xml:"enable_snmp_v2c"`
+	EnableSNMPv3    *bool `yaml:"enable_snmp_v3" json:"enable_snmp_v3" xml:"enable_snmp_v3"`
+}
+
+// NTPProtocol is the state and the servers of NTP. The nil servers remain
+// unchanged, while the empty servers clear the servers.
+type NTPProtocol struct {
+	ProtocolEnabled *bool    `yaml:"protocol_enabled" json:"protocol_enabled" xml:"protocol_enabled"`
+	NTPServers      []string `yaml:"ntp_servers" json:"ntp_servers" xml:"ntp_servers"`
+}
+
+// SSDPProtocol is the state, the port, and the notification settings of
+// Simple Service Discovery Protocol.
+type SSDPProtocol struct {
+	ProtocolEnabled                *bool  `yaml:"protocol_enabled" json:"protocol_enabled" xml:"protocol_enabled"`
+	Port                           *int   `yaml:"port" json:"port" xml:"port"`
+	NotifyIPv6Scope                string `yaml:"notify_ipv6_scope" json:"notify_ipv6_scope" xml:"notify_ipv6_scope"`
+	NotifyMulticastIntervalSeconds *int   `yaml:"notify_multicast_interval_seconds" json:"notify_multicast_interval_seconds" xml:"notify_multicast_interval_seconds"`
+	NotifyTTL                      *int   `yaml:"notify_ttl" json:"notify_ttl" xml:"notify_ttl"`
+}
+
+// NetworkProtocolViolation is a setting of the network services of a
+// manager violating a policy.
+type NetworkProtocolViolation struct {
+	Protocol string `yaml:"protocol" json:"protocol" xml:"protocol"`
+	Field    string `yaml:"field" json:"field" xml:"field"`
+	Current  string `yaml:"current" json:"current" xml:"current"`
+	Expected string `yaml:"expected" json:"expected" xml:"expected"`
+}
+
+// UnknownProtocolValue is the current value of a setting absent from the
+// network services of a manager.
+const UnknownProtocolValue = "unknown"
+
+// DefaultNetworkProtocolPolicy returns the policy of the network services
+// of iDRAC, i.e. IPMI over LAN and SSDP disabled, and NTP enabled. Telnet
+// is not part of the policy, because iDRAC9 no longer provides it.
+func DefaultNetworkProtocolPolicy() *ManagerNetworkProtocol {
+	enabled, disabled := true, false
+	return &ManagerNetworkProtocol{
+		IPMI: &NetworkProtocolService{ProtocolEnabled: &disabled},
+		SSDP: &SSDPProtocol{ProtocolEnabled: &disabled},
+		NTP:  &NTPProtocol{ProtocolEnabled: &enabled},
+	}
+}
+
+// GetManagerNetworkProtocol returns the network services of a manager,
+// e.g. iDRAC.Embedded.1.
+func (cli *Client) GetManagerNetworkProtocol(managerID string) (*ManagerNetworkProtocol, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"Managers/"+managerID+"/NetworkProtocol", []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newManagerNetworkProtocolFromBytes(resp)
+}
+
+// SetManagerNetworkProtocol changes the network services of a manager.
+// The nil fields and the empty strings of the changes remain unchanged.
+func (cli *Client) SetManagerNetworkProtocol(managerID string, changes *ManagerNetworkProtocol) error {
+	patch := make(map[string]interface{})
+	for name, service := range map[string]*NetworkProtocolService{
+		"HTTP":         changes.HTTP,
+		"HTTPS":        changes.HTTPS,
+		"SSH":          changes.SSH,
+		"IPMI":         changes.IPMI,
+		"VirtualMedia": changes.VirtualMedia,
+		"KVMIP":        changes.KVMIP,
+		"Telnet":       changes.Telnet,
+	} {
+		if service == nil {
+			continue
+		}
+		settings := make(map[string]interface{})
+		if service.ProtocolEnabled != nil {
+			settings["ProtocolEnabled"] = *service.ProtocolEnabled
+		}
+		if service.Port != nil {
+			settings["Port"] = *service.Port
+		}
+		if len(settings) > 0 {
+			patch[name] = settings
+		}
+	}
+	if changes.SNMP != nil {
+		settings := make(map[string]interface{})
+		addSetting(settings, "ProtocolEnabled", changes.SNMP.ProtocolEnabled)
+		addSetting(settings, "Port", changes.SNMP.Port)
+		addSetting(settings, "EnableSNMPv1", changes.SNMP.EnableSNMPv1)
+		addSetting(settings, "EnableSNMPv2c", changes.SNMP.EnableSNMPv2c)
+		addSetting(settings, "EnableSNMPv3", changes.SNMP.EnableSNMPv3)
+		if len(settings) > 0 {
+			patch["SNMP"] = settings
+		}
+	}
+	if changes.NTP != nil {
+		settings := make(map[string]interface{})
+		addSetting(settings, "ProtocolEnabled", changes.NTP.ProtocolEnabled)
+		if changes.NTP.NTPServers != nil {
+			settings["NTPServers"] = changes.NTP.NTPServers
+		}
+		if len(settings) > 0 {
+			patch["NTP"] = settings
+		}
+	}
+	if changes.SSDP != nil {
+		settings := make(map[string]interface{})
+		addSetting(settings, "ProtocolEnabled", changes.SSDP.ProtocolEnabled)
+		addSetting(settings, "Port", changes.SSDP.Port)
+		addSetting(settings, "NotifyMulticastIntervalSeconds", changes.SSDP.NotifyMulticastIntervalSeconds)
+		addSetting(settings, "NotifyTTL", changes.SSDP.NotifyTTL)
+		if changes.SSDP.NotifyIPv6Scope != "" {
+			settings["NotifyIPv6Scope"] = changes.SSDP.NotifyIPv6Scope
+		}
+		if len(settings) > 0 {
+			patch["SSDP"] = settings
+		}
+	}
+	if len(patch) == 0 {
+		return fmt.Errorf("no network protocol changes")
+	}
+	_, err := cli.patchResource(cli.rootPath+"Managers/"+managerID+"/NetworkProtocol", patch)
+	return err
+}
+
+// addSetting adds the value of a non-nil pointer to the settings.
+func addSetting(settings map[string]interface{}, name string, v interface{}) {
+	switch value := v.(type) {
+	case *bool:
+		if value != nil {
+			settings[name] = *value
+		}
+	case *int:
+		if value != nil {
+			settings[name] = *value
+		}
+	}
+}
+
+// CheckNetworkProtocol returns the settings of the network services of a
+// manager violating the policy. The nil fields and the empty strings of the
+// policy are not checked. The NTP servers of the policy must be the
+// configured servers. The settings, and the services, absent from the
+// manager violate the policy with the UnknownProtocolValue current value.
+func CheckNetworkProtocol(current, policy *ManagerNetworkProtocol) []*NetworkProtocolViolation {
+	violations := []*NetworkProtocolViolation{}
+	check := func(protocol, field string, currentValue, expectedValue interface{}) {
+		if reflect.ValueOf(expectedValue).IsNil() {
+			return
+		}
+		c, e := formatProtocolValue(currentValue), formatProtocolValue(expectedValue)
+		if v := reflect.ValueOf(currentValue); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+			c = UnknownProtocolValue
+		}
+		if c == e {
+			return
+		}
+		violations = append(violations, &NetworkProtocolViolation{
+			Protocol: protocol,
+			Field:    field,
+			Current:  c,
+			Expected: e,
+		})
+	}
+	for _, entry := range []struct {
+		protocol string
+		current  *NetworkProtocolService
+		policy   *NetworkProtocolService
+	}{
+		{"http", current.HTTP, policy.HTTP},
+		{"https", current.HTTPS, policy.HTTPS},
+		{"ssh", current.SSH, policy.SSH},
+		{"ipmi", current.IPMI, policy.IPMI},
+		{"virtual_media", current.VirtualMedia, policy.VirtualMedia},
+		{"kvmip", current.KVMIP, policy.KVMIP},
+		{"telnet", current.Telnet, policy.Telnet},
+	} {
+		if entry.policy == nil {
+			continue
+		}
+		service := entry.current
+		if service == nil {
+			service = &NetworkProtocolService{}
+		}
+		check(entry.protocol, "protocol_enabled", service.ProtocolEnabled, entry.policy.ProtocolEnabled)
+		check(entry.protocol, "port", service.Port, entry.policy.Port)
+	}
+	if policy.SNMP != nil {
+		snmp := current.SNMP
+		if snmp == nil {
+			snmp = &SNMPProtocol{}
+		}
+		check("snmp", "protocol_enabled", snmp.ProtocolEnabled, policy.SNMP.ProtocolEnabled)
+		check("snmp", "port", snmp.Port, policy.SNMP.Port)
+		check("snmp", "enable_snmp_v1", snmp.EnableSNMPv1, policy.SNMP.EnableSNMPv1)
+		check("snmp", "enable_snmp_v2c", snmp.EnableSNMPv2c, policy.SNMP.EnableSNMPv2c)
+		check("snmp", "enable_snmp_v3", snmp.EnableSNMPv3, policy.SNMP.EnableSNMPv3)
+	}
+	if policy.NTP != nil {
+		ntp := current.NTP
+		if ntp == nil {
+			ntp = &NTPProtocol{}
+		}
+		check("ntp", "protocol_enabled", ntp.ProtocolEnabled, policy.NTP.ProtocolEnabled)
+		if policy.NTP.NTPServers != nil {
+			var servers interface{}
+			if current.NTP != nil {
+				servers = trimEmpty(ntp.NTPServers)
+			}
+			check("ntp", "ntp_servers", servers, trimEmpty(policy.NTP.NTPServers))
+		}
+	}
+	if policy.SSDP != nil {
+		ssdp := current.SSDP
+		if ssdp == nil {
+			ssdp = &SSDPProtocol{}
+		}
+		check("ssdp", "protocol_enabled", ssdp.ProtocolEnabled, policy.SSDP.ProtocolEnabled)
+		check("ssdp", "port", ssdp.Port, policy.SSDP.Port)
+	}
+	return violations
+}
+
+// formatProtocolValue returns the string representation of a setting, or
+// an empty string when the setting is absent.
+func formatProtocolValue(v interface{}) string {
+	switch value := v.(type) {
+	case *bool:
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprintf("%t", *value)
+	case *int:
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprintf("%d", *value)
+	}
+	return formatDirectoryValue(v)
+}
+
+// newManagerNetworkProtocolFromString returns ManagerNetworkProtocol
+// instance from an input string.
+func newManagerNetworkProtocolFromString(s string) (*ManagerNetworkProtocol, error) {
+	return newManagerNetworkProtocolFromBytes([]byte(s))
+}
+
+// newManagerNetworkProtocolFromBytes returns ManagerNetworkProtocol
+// instance from an input byte array.
+func newManagerNetworkProtocolFromBytes(s []byte) (*ManagerNetworkProtocol, error) {
+	response := &networkProtocolResponse{}
+	if err := json.Unmarshal(s, response); err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the manager network protocol is empty, server response: %s", string(s[:]))
+	}
+	protocol := &ManagerNetworkProtocol{
+		ID: response.ID,
+		OData: &ODataAnnotation{
+			Context: response.Context,
+			ID:      response.ODataAnnotation.ID,
+			Type:    response.Type,
+		},
+		HostName:     response.HostName,
+		FQDN:         response.FQDN,
+		HTTP:         newNetworkProtocolService(response.HTTP),
+		HTTPS:        newNetworkProtocolService(response.HTTPS),
+		SSH:          newNetworkProtocolService(response.SSH),
+		IPMI:         newNetworkProtocolService(response.IPMI),
+		VirtualMedia: newNetworkProtocolService(response.VirtualMedia),
+		KVMIP:        newNetworkProtocolService(response.KVMIP),
+		Telnet:       newNetworkProtocolService(response.Telnet),
+	}
+	if response.SNMP != nil {
+		protocol.SNMP = &SNMPProtocol{
+			ProtocolEnabled: response.SNMP.ProtocolEnabled,
+			Port:            response.SNMP.Port,
+			EnableSNMPv1:    response.SNMP.EnableSNMPv1,
+			EnableSNMPv2c:   response.SNMP.EnableSNMPv2c,
+			EnableSNMPv3:    response.SNMP.EnableSNMPv3,
+		}
+	}
+	if response.NTP != nil {
+		protocol.NTP = &NTPProtocol{
+			ProtocolEnabled: response.NTP.ProtocolEnabled,
+			NTPServers:      nonNilStrings(response.NTP.NTPServers),
+		}
+	}
+	if response.SSDP != nil {
+		protocol.SSDP = &SSDPProtocol{
+			ProtocolEnabled:                response.SSDP.ProtocolEnabled,
+			Port:                           response.SSDP.Port,
+			NotifyIPv6Scope:                response.SSDP.NotifyIPv6Scope,
+			NotifyMulticastIntervalSeconds: response.SSDP.NotifyMulticastIntervalSeconds,
+			NotifyTTL:                      response.SSDP.NotifyTTL,
+		}
+	}
+	return protocol, nil
+}
+
+func newNetworkProtocolService(response *networkProtocolServiceResponse) *NetworkProtocolService {
+	if response == nil {
+		return nil
+	}
+	return &NetworkProtocolService{
+		ProtocolEnabled: response.ProtocolEnabled,
+		Port:            response.Port,
+	}
+}
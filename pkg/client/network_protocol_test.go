@@ -0,0 +1,130 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"reflect"
+	"testing"
+)
+
+func TestManagerNetworkProtocol(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	protocol, err := cli.GetManagerNetworkProtocol("iDRAC.Embedded.1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if protocol.ID != "iDRAC.Embedded.1" || protocol.HostName != "idrac-24A8VC9" || protocol.FQDN != "idrac-24A8VC9.example.com" {
+		t.Fatalf("client: unexpected manager network protocol: %+v", *protocol)
+	}
+	if protocol.IPMI == nil || *protocol.IPMI.ProtocolEnabled || *protocol.IPMI.Port != 623 {
+		t.Fatalf("client: unexpected IPMI settings: %+v", protocol.IPMI)
+	}
+	if protocol.Telnet != nil || protocol.SNMP.EnableSNMPv1 != nil {
+		t.Fatalf("client: expected no Telnet and SNMP version settings")
+	}
+	if !reflect.DeepEqual(protocol.NTP.NTPServers, []string{"ntp1.example.com", "", ""}) {
+		t.Fatalf("client: unexpected NTP servers: %v", protocol.NTP.NTPServers)
+	}
+	for _, resource := range []interface{}{protocol, protocol.IPMI, protocol.NTP} {
+		complianceMessages, compliant := isStructCompliant(resource)
+		if !compliant {
+			for _, entry := range complianceMessages {
+				t.Logf("%s", entry)
+			}
+			t.Fatalf("client: %T is not compliant", resource)
+		}
+	}
+
+	disabled, port := false, 2222
+	if err := cli.SetManagerNetworkProtocol("iDRAC.Embedded.1", &ManagerNetworkProtocol{
+		SSH:  &NetworkProtocolService{Port: &port},
+		SSDP: &SSDPProtocol{ProtocolEnabled: &disabled},
+		NTP:  &NTPProtocol{NTPServers: []string{"ntp1.example.com", "ntp2.example.com"}},
+	}); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.SetManagerNetworkProtocol("iDRAC.Embedded.1", &ManagerNetworkProtocol{IPMI: &NetworkProtocolService{}}); err == nil {
+		t.Fatalf("client: expected failure due to no changes, but got non-error response")
+	}
+	if _, err := newManagerNetworkProtocolFromString(`{"HostName": "idrac"}`); err == nil {
+		t.Fatalf("client: expected failure due to empty Id, but got non-error response")
+	}
+
+	enabled := true
+	testFailed := 0
+	for i, test := range []struct {
+		current *ManagerNetworkProtocol
+		policy  *ManagerNetworkProtocol
+		exp     []*NetworkProtocolViolation
+	}{
+		{
+			policy: DefaultNetworkProtocolPolicy(),
+			exp: []*NetworkProtocolViolation{
+				{Protocol: "ssdp", Field: "protocol_enabled", Current: "true", Expected: "false"},
+			},
+		},
+		{
+			policy: &ManagerNetworkProtocol{
+				HTTP:   &NetworkProtocolService{ProtocolEnabled: &disabled},
+				SSH:    &NetworkProtocolService{ProtocolEnabled: &enabled, Port: &port},
+				Telnet: &NetworkProtocolService{ProtocolEnabled: &disabled},
+				SNMP:   &SNMPProtocol{EnableSNMPv1: &disabled},
+				NTP:    &NTPProtocol{NTPServers: []string{"ntp1.example.com", "ntp2.example.com"}},
+			},
+			exp: []*NetworkProtocolViolation{
+				{Protocol: "http", Field: "protocol_enabled", Current: "true", Expected: "false"},
+				{Protocol: "ssh", Field: "port", Current: "22", Expected: "2222"},
+				{Protocol: "telnet", Field: "protocol_enabled", Current: "unknown", Expected: "false"},
+				{Protocol: "snmp", Field: "enable_snmp_v1", Current: "unknown", Expected: "false"},
+				{Protocol: "ntp", Field: "ntp_servers", Current: "ntp1.example.com", Expected: "ntp1.example.com,ntp2.example.com"},
+			},
+		},
+		{
+			policy: &ManagerNetworkProtocol{
+				IPMI: &NetworkProtocolService{ProtocolEnabled: &disabled},
+				NTP:  &NTPProtocol{ProtocolEnabled: &enabled, NTPServers: []string{"ntp1.example.com"}},
+			},
+			exp: []*NetworkProtocolViolation{},
+		},
+		{
+			current: &ManagerNetworkProtocol{ID: protocol.ID},
+			policy: &ManagerNetworkProtocol{
+				IPMI: &NetworkProtocolService{ProtocolEnabled: &disabled},
+				NTP:  &NTPProtocol{NTPServers: []string{"ntp1.example.com"}},
+			},
+			exp: []*NetworkProtocolViolation{
+				{Protocol: "ipmi", Field: "protocol_enabled", Current: "unknown", Expected: "false"},
+				{Protocol: "ntp", Field: "ntp_servers", Current: "unknown", Expected: "ntp1.example.com"},
+			},
+		},
+	} {
+		current := test.current
+		if current == nil {
+			current = protocol
+		}
+		violations := CheckNetworkProtocol(current, test.policy)
+		if !reflect.DeepEqual(violations, test.exp) {
+			t.Logf("FAIL: Test %d: unexpected violations", i)
+			for _, violation := range violations {
+				t.Logf("FAIL: Test %d: %+v", i, *violation)
+			}
+			testFailed++
+		}
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"fmt"
+)
+
+// PasswordRotation is the outcome of the rotation of the password of an
+// account. It holds no secrets.
+type PasswordRotation struct {
+	UserName   string `yaml:"user_name" json:"user_name" xml:"user_name"`
+	AccountID  string `yaml:"account_id" json:"account_id" xml:"account_id"`
+	Changed    bool   `yaml:"changed" json:"changed" xml:"changed"`
+	Verified   bool   `yaml:"verified" json:"verified" xml:"verified"`
+	RolledBack bool   `yaml:"rolled_back" json:"rolled_back" xml:"rolled_back"`
+}
+
+// RotateAccountPassword changes the password of the account with the
+// UserName, and verifies the login with the new password. When the
+// verification fails, the function changes the password back to the old
+// one. The old password defaults to the password of the client, when
+// the client authenticates as the account being rotated. The function
+// refuses to change the password without the old password. The client
+// keeps working after the rotation of its own account, i.e. it switches
+// to the password in effect.
+func (cli *Client) RotateAccountPassword(userName, oldPassword, newPassword string) (*PasswordRotation, error) {
+	rotation := &PasswordRotation{UserName: userName}
+	if newPassword == "" {
+		return rotation, fmt.Errorf("empty new password")
+	}
+	self := userName == cli.username
+	if self && oldPassword == "" {
+		oldPassword = cli.password
+	}
+	if oldPassword == "" {
+		// The password cannot be rolled back when the login with the new
+		// password fails.
+		return rotation, fmt.Errorf("the old password of %s is required for rollback", userName)
+	}
+	if newPassword == oldPassword {
+		return rotation, fmt.Errorf("the new password is the same as the old password")
+	}
+
+	account, err := cli.GetAccountByUserName(userName)
+	if err != nil {
+		return rotation, err
+	}
+	rotation.AccountID = account.ID
+
+	if err := cli.SetAccountPassword(account.ID, newPassword); err != nil {
+		return rotation, fmt.Errorf("failed changing password: %s", err)
+	}
+	rotation.Changed = true
+	if self {
+		cli.password = newPassword
+	}
+
+	verifier := cli.Clone()
+	verifier.username = userName
+	verifier.password = newPassword
+	verifyErr := verifier.verifyAccountLogin(account.ID)
+	if verifyErr == nil {
+		rotation.Verified = true
+		return rotation, nil
+	}
+
+	err = cli.SetAccountPassword(account.ID, oldPassword)
+	if err != nil && self {
+		// The new password may not be in effect, i.e. the client still
+		// authenticates with the old password.
+		cli.password = oldPassword
+		err = cli.SetAccountPassword(account.ID, oldPassword)
+	}
+	if err != nil {
+		return rotation, fmt.Errorf("failed verifying login with new password: %s; failed rolling back: %s", verifyErr, err)
+	}
+	rotation.RolledBack = true
+	if self {
+		cli.password = oldPassword
+	}
+	return rotation, fmt.Errorf("failed verifying login with new password: %s; rolled back to old password", verifyErr)
+}
+
+// verifyAccountLogin verifies the client logs in by fetching the account
+// it authenticates as.
+func (cli *Client) verifyAccountLogin(accountID string) error {
+	account, err := cli.GetAccount(accountID)
+	if err != nil {
+		return err
+	}
+	if account.UserName != cli.username {
+		return fmt.Errorf("account %s belongs to %s", accountID, account.UserName)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"testing"
+)
+
+func TestRotateAccountPassword(t *testing.T) {
+	testFailed := 0
+	for i, test := range []struct {
+		userName    string
+		oldPassword string
+		newPassword string
+		pathMap     map[string]string
+		exp         *PasswordRotation
+		shouldErr   bool
+	}{
+		{
+			userName:    "operator",
+			oldPassword: "opsecret",
+			newPassword: "opsecret2",
+			exp:         &PasswordRotation{UserName: "operator", AccountID: "3", Changed: true, Verified: true},
+		},
+		{
+			userName:    "admin",
+			newPassword: "secret2",
+			exp:         &PasswordRotation{UserName: "admin", AccountID: "2", Changed: true, Verified: true},
+		},
+		{
+			// The server accepts the change, but not the new password.
+			userName:    "operator",
+			oldPassword: "opsecret",
+			newPassword: "opsecret2",
+			pathMap: map[string]string{
+				"PATCH /redfish/v1/AccountService/Accounts/3": "success_1.json",
+			},
+			exp:       &PasswordRotation{UserName: "operator", AccountID: "3", Changed: true, RolledBack: true},
+			shouldErr: true,
+		},
+		{
+			// The password of another account is not changed without the
+			// old password, i.e. the rollback is not possible.
+			userName:    "operator",
+			newPassword: "opsecret2",
+			exp:         &PasswordRotation{UserName: "operator"},
+			shouldErr:   true,
+		},
+		{
+			userName:    "nobody",
+			newPassword: "secret2",
+			exp:         &PasswordRotation{UserName: "nobody"},
+			shouldErr:   true,
+		},
+		{
+			userName:    "admin",
+			newPassword: "secret",
+			exp:         &PasswordRotation{UserName: "admin"},
+			shouldErr:   true,
+		},
+	} {
+		server, err := NewMockTestServer(test.pathMap, false)
+		if err != nil {
+			t.Fatalf("Failed to start mock test server: %s", err)
+		}
+		server.SetUser("operator", "opsecret")
+
+		cli := NewClient()
+		cli.SetHost(server.NonTLS.Hostname)
+		cli.SetPort(server.NonTLS.Port)
+		cli.SetProtocol(server.NonTLS.Protocol)
+		cli.SetUsername("admin")
+		cli.SetPassword("secret")
+
+		rotation, err := cli.RotateAccountPassword(test.userName, test.oldPassword, test.newPassword)
+		if (err != nil) != test.shouldErr {
+			t.Logf("FAIL: Test %d: expected error %t, but got: %v", i, test.shouldErr, err)
+			testFailed++
+		} else if *rotation != *test.exp {
+			t.Logf("FAIL: Test %d: value mismatch: '%+v' (actual) vs. '%+v' (expected)", i, *rotation, *test.exp)
+			testFailed++
+		} else if _, err := cli.GetAccount("2"); err != nil {
+			t.Logf("FAIL: Test %d: expected client to keep working after rotation, but got error: %v", i, err)
+			testFailed++
+		} else {
+			t.Logf("PASS: Test %d: rotation %+v, error: %v", i, *rotation, err)
+		}
+		server.Close()
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
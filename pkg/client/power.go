@@ -0,0 +1,176 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type powerResponse struct {
+	ODataAnnotation
+	ID                   string `json:"Id"`
+	Name                 string
+	Description          string
+	PowerControl         []powerControlResponse
+	PowerControlCounter  uint64 `json:"PowerControl@odata.count"`
+	PowerSupplies        []powerSupplyResponse
+	PowerSuppliesCounter uint64 `json:"PowerSupplies@odata.count"`
+	Redundancy           []interface{}
+	RedundancyCounter    uint64 `json:"Redundancy@odata.count"`
+	Voltages             []interface{}
+	VoltagesCounter      uint64 `json:"Voltages@odata.count"`
+}
+
+type powerControlResponse struct {
+	ODataAnnotation
+	MemberID            string `json:"MemberId"`
+	Name                string
+	PowerAllocatedWatts float64
+	PowerAvailableWatts float64
+	PowerCapacityWatts  float64
+	PowerConsumedWatts  float64
+	PowerRequestedWatts float64
+	PowerLimit          struct {
+		CorrectionInMs uint64
+		LimitException string
+		LimitInWatts   float64
+	}
+	PowerMetrics struct {
+		AverageConsumedWatts float64
+		IntervalInMin        uint64
+		MaxConsumedWatts     float64
+		MinConsumedWatts     float64
+	}
+	RelatedItem        []ODataAnnotation
+	RelatedItemCounter uint64 `json:"RelatedItem@odata.count"`
+}
+
+type powerSupplyResponse struct {
+	ODataAnnotation
+	MemberID             string `json:"MemberId"`
+	Name                 string
+	Manufacturer         string
+	Model                string
+	PartNumber           string
+	SparePartNumber      string
+	SerialNumber         string
+	FirmwareVersion      string
+	HotPluggable         bool
+	PowerSupplyType      string
+	LineInputVoltage     float64
+	LineInputVoltageType string
+	PowerCapacityWatts   float64
+	PowerInputWatts      float64
+	PowerOutputWatts     float64
+	LastPowerOutputWatts float64
+	InputRanges          []interface{}
+	Status               HealthStatus
+}
+
+// Power represents an instance of Redfish Power resource of a chassis.
+type Power struct {
+	ID            string           `yaml:"id" json:"id" xml:"id"`
+	OData         *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name          string           `yaml:"name" json:"name" xml:"name"`
+	Description   string           `yaml:"description" json:"description" xml:"description"`
+	PowerControl  []*PowerControl  `yaml:"power_control" json:"power_control" xml:"power_control"`
+	PowerSupplies []*PowerSupply   `yaml:"power_supplies" json:"power_supplies" xml:"power_supplies"`
+}
+
+// PowerControl represents power consumption and limits of a chassis.
+type PowerControl struct {
+	MemberID             string  `yaml:"member_id" json:"member_id" xml:"member_id"`
+	Name                 string  `yaml:"name" json:"name" xml:"name"`
+	PowerConsumedWatts   float64 `yaml:"power_consumed_watts" json:"power_consumed_watts" xml:"power_consumed_watts"`
+	PowerCapacityWatts   float64 `yaml:"power_capacity_watts" json:"power_capacity_watts" xml:"power_capacity_watts"`
+	PowerAllocatedWatts  float64 `yaml:"power_allocated_watts" json:"power_allocated_watts" xml:"power_allocated_watts"`
+	PowerRequestedWatts  float64 `yaml:"power_requested_watts" json:"power_requested_watts" xml:"power_requested_watts"`
+	PowerAvailableWatts  float64 `yaml:"power_available_watts" json:"power_available_watts" xml:"power_available_watts"`
+	PowerLimitWatts      float64 `yaml:"power_limit_watts" json:"power_limit_watts" xml:"power_limit_watts"`
+	AverageConsumedWatts float64 `yaml:"average_consumed_watts" json:"average_consumed_watts" xml:"average_consumed_watts"`
+	MinConsumedWatts     float64 `yaml:"min_consumed_watts" json:"min_consumed_watts" xml:"min_consumed_watts"`
+	MaxConsumedWatts     float64 `yaml:"max_consumed_watts" json:"max_consumed_watts" xml:"max_consumed_watts"`
+}
+
+// PowerSupply represents a power supply unit of a chassis.
+type PowerSupply struct {
+	MemberID           string       `yaml:"member_id" json:"member_id" xml:"member_id"`
+	Name               string       `yaml:"name" json:"name" xml:"name"`
+	Model              string       `yaml:"model" json:"model" xml:"model"`
+	SerialNumber       string       `yaml:"serial_number" json:"serial_number" xml:"serial_number"`
+	FirmwareVersion    string       `yaml:"firmware_version" json:"firmware_version" xml:"firmware_version"`
+	PowerSupplyType    string       `yaml:"power_supply_type" json:"power_supply_type" xml:"power_supply_type"`
+	LineInputVoltage   float64      `yaml:"line_input_voltage" json:"line_input_voltage" xml:"line_input_voltage"`
+	PowerCapacityWatts float64      `yaml:"power_capacity_watts" json:"power_capacity_watts" xml:"power_capacity_watts"`
+	PowerInputWatts    float64      `yaml:"power_input_watts" json:"power_input_watts" xml:"power_input_watts"`
+	PowerOutputWatts   float64      `yaml:"power_output_watts" json:"power_output_watts" xml:"power_output_watts"`
+	Status             HealthStatus `yaml:"status" json:"status" xml:"status"`
+}
+
+// GetPower returns an instance of Redfish Power resource for a chassis,
+// e.g. System.Embedded.1.
+func (cli *Client) GetPower(chassisID string) (*Power, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"Chassis/"+chassisID+"/Power/", []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newPowerFromBytes(resp)
+}
+
+// newPowerFromString returns Power instance from an input string.
+func newPowerFromString(s string) (*Power, error) {
+	return newPowerFromBytes([]byte(s))
+}
+
+// newPowerFromBytes returns Power instance from an input byte array.
+func newPowerFromBytes(s []byte) (*Power, error) {
+	p := &Power{
+		PowerControl:  []*PowerControl{},
+		PowerSupplies: []*PowerSupply{},
+	}
+	response := &powerResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	p.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	p.ID = response.ID
+	p.Name = response.Name
+	p.Description = response.Description
+	for _, entry := range response.PowerControl {
+		p.PowerControl = append(p.PowerControl, &PowerControl{
+			MemberID:             entry.MemberID,
+			Name:                 entry.Name,
+			PowerConsumedWatts:   entry.PowerConsumedWatts,
+			PowerCapacityWatts:   entry.PowerCapacityWatts,
+			PowerAllocatedWatts:  entry.PowerAllocatedWatts,
+			PowerRequestedWatts:  entry.PowerRequestedWatts,
+			PowerAvailableWatts:  entry.PowerAvailableWatts,
+			PowerLimitWatts:      entry.PowerLimit.LimitInWatts,
+			AverageConsumedWatts: entry.PowerMetrics.AverageConsumedWatts,
+			MinConsumedWatts:     entry.PowerMetrics.MinConsumedWatts,
+			MaxConsumedWatts:     entry.PowerMetrics.MaxConsumedWatts,
+		})
+	}
+	for _, entry := range response.PowerSupplies {
+		p.PowerSupplies = append(p.PowerSupplies, &PowerSupply{
+			MemberID:           entry.MemberID,
+			Name:               entry.Name,
+			Model:              entry.Model,
+			SerialNumber:       entry.SerialNumber,
+			FirmwareVersion:    entry.FirmwareVersion,
+			PowerSupplyType:    entry.PowerSupplyType,
+			LineInputVoltage:   entry.LineInputVoltage,
+			PowerCapacityWatts: entry.PowerCapacityWatts,
+			PowerInputWatts:    entry.PowerInputWatts,
+			PowerOutputWatts:   entry.PowerOutputWatts,
+			Status:             entry.Status,
+		})
+	}
+	return p, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"testing"
+)
+
+func TestGetPower(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	power, err := cli.GetPower("System.Embedded.1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+
+	if len(power.PowerControl) != 1 {
+		t.Fatalf("client: expected 1 power control entry, but got %d", len(power.PowerControl))
+	}
+	if power.PowerControl[0].PowerConsumedWatts != 246 {
+		t.Fatalf("client: expected 246 consumed watts, but got %.0f", power.PowerControl[0].PowerConsumedWatts)
+	}
+	if power.PowerControl[0].MaxConsumedWatts != 402 {
+		t.Fatalf("client: expected 402 max consumed watts, but got %.0f", power.PowerControl[0].MaxConsumedWatts)
+	}
+	if len(power.PowerSupplies) != 2 {
+		t.Fatalf("client: expected 2 power supplies, but got %d", len(power.PowerSupplies))
+	}
+	for _, psu := range power.PowerSupplies {
+		t.Logf("PSU: %s | Input: %.0f W | Health: %s", psu.Name, psu.PowerInputWatts, psu.Status.Health)
+	}
+
+	for _, resource := range []interface{}{power, power.PowerControl[0], power.PowerSupplies[0]} {
+		complianceMessages, compliant := isStructCompliant(resource)
+		if !compliant {
+			for _, entry := range complianceMessages {
+				t.Logf("%s", entry)
+			}
+			t.Fatalf("client: struct is not compliant")
+		}
+	}
+
+	if _, err := newPowerFromString("{"); err == nil {
+		t.Fatalf("client: expected failure, but got non-error response")
+	}
+}
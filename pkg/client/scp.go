@@ -0,0 +1,244 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The components of Server Configuration Profile.
+var scpTargets = []string{
+	"ALL", "IDRAC", "BIOS", "NIC", "RAID", "FC", "InfiniBand", "SupportAssist",
+	"EventFilters", "System", "LifecycleController", "AHCI", "PCIeSSD",
+}
+
+// SCPChange is a change of an attribute by the import of Server
+// Configuration Profile, i.e. either the applied change, or, in preview
+// mode, the change the import would apply.
+type SCPChange struct {
+	FQDD      string `yaml:"fqdd" json:"fqdd" xml:"fqdd"`
+	Name      string `yaml:"name" json:"name" xml:"name"`
+	OldValue  string `yaml:"old_value" json:"old_value" xml:"old_value"`
+	NewValue  string `yaml:"new_value" json:"new_value" xml:"new_value"`
+	Status    string `yaml:"status" json:"status" xml:"status"`
+	MessageID string `yaml:"message_id" json:"message_id" xml:"message_id"`
+	Message   string `yaml:"message" json:"message" xml:"message"`
+}
+
+// SCPImportResult is the result of the import of Server Configuration
+// Profile, or of its preview.
+type SCPImportResult struct {
+	JobID      string       `yaml:"job_id" json:"job_id" xml:"job_id"`
+	Preview    bool         `yaml:"preview" json:"preview" xml:"preview"`
+	TaskState  string       `yaml:"task_state" json:"task_state" xml:"task_state"`
+	TaskStatus string       `yaml:"task_status" json:"task_status" xml:"task_status"`
+	Message    string       `yaml:"message" json:"message" xml:"message"`
+	Changes    []*SCPChange `yaml:"changes" json:"changes" xml:"changes"`
+}
+
+type scpTaskResponse struct {
+	Messages []struct {
+		MessageID string `json:"MessageId"`
+		Message   string
+		Oem       struct {
+			Dell struct {
+				FQDD     string
+				Name     string
+				OldValue string
+				NewValue string
+				Status   string
+			}
+		}
+	}
+}
+
+// ExportSCP exports Server Configuration Profile of the targets, e.g. BIOS
+// and IDRAC, or ALL when the targets are empty. The format is either XML or
+// JSON, and the export use is either Default, Clone, or Replace. The
+// function waits for the export job and returns the profile.
+func (cli *Client) ExportSCP(ctx context.Context, targets []string, format, exportUse string) ([]byte, error) {
+	target, err := scpTarget(targets)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = "XML"
+	}
+	format = strings.ToUpper(format)
+	if format != "XML" && format != "JSON" {
+		return nil, fmt.Errorf("unsupported profile format %s, expecting XML or JSON", format)
+	}
+	if exportUse == "" {
+		exportUse = "Default"
+	}
+	if err := validateSCPValue("export use", &exportUse, []string{"Default", "Clone", "Replace"}); err != nil {
+		return nil, err
+	}
+	req := map[string]interface{}{
+		"ExportFormat": format,
+		"ExportUse":    exportUse,
+		"ShareParameters": map[string]interface{}{
+			"Target": target,
+		},
+	}
+	_, header, err := cli.postResource(cli.rootPath+ManagerResource+"/Actions/Oem/EID_674_Manager.ExportSystemConfiguration", req)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := newTaskHandle(header)
+	if err != nil {
+		return nil, err
+	}
+	// The task monitor responds with the profile once the export completed.
+	isProfile := func(resp []byte) bool {
+		_, err := scpFormat(resp)
+		return err == nil
+	}
+	_, resp, err := cli.waitForTask(ctx, handle, nil, isProfile)
+	if err != nil {
+		return nil, err
+	}
+	if s, err := scpFormat(resp); err != nil || s != format {
+		return nil, fmt.Errorf("the export job %s completed, but the service returned no %s profile", handle.JobID, format)
+	}
+	return resp, nil
+}
+
+// ImportSCP imports Server Configuration Profile, i.e. the profile exported
+// by ExportSCP, in either XML or JSON format. The shutdown type is either
+// Graceful, Forced, or NoReboot, and the host power state after the import
+// is either On or Off. The function waits for the import job and returns
+// the applied changes. The result is returned along with the error when
+// the import failed.
+func (cli *Client) ImportSCP(ctx context.Context, profile []byte, shutdownType, hostPowerState string) (*SCPImportResult, error) {
+	if shutdownType == "" {
+		shutdownType = "Graceful"
+	}
+	if err := validateSCPValue("shutdown type", &shutdownType, []string{"Graceful", "Forced", "NoReboot"}); err != nil {
+		return nil, err
+	}
+	if hostPowerState == "" {
+		hostPowerState = "On"
+	}
+	if err := validateSCPValue("host power state", &hostPowerState, []string{"On", "Off"}); err != nil {
+		return nil, err
+	}
+	req := map[string]interface{}{
+		"ShutdownType":   shutdownType,
+		"HostPowerState": hostPowerState,
+	}
+	return cli.importSCP(ctx, "EID_674_Manager.ImportSystemConfiguration", profile, req)
+}
+
+// PreviewSCP previews the import of Server Configuration Profile, i.e. it
+// reports the changes ImportSCP would apply, without applying the changes.
+func (cli *Client) PreviewSCP(ctx context.Context, profile []byte) (*SCPImportResult, error) {
+	result, err := cli.importSCP(ctx, "EID_674_Manager.ImportSystemConfigurationPreview", profile, map[string]interface{}{})
+	if result != nil {
+		result.Preview = true
+	}
+	return result, err
+}
+
+func (cli *Client) importSCP(ctx context.Context, action string, profile []byte, req map[string]interface{}) (*SCPImportResult, error) {
+	if _, err := scpFormat(profile); err != nil {
+		return nil, err
+	}
+	req["ImportBuffer"] = string(profile)
+	req["ShareParameters"] = map[string]interface{}{
+		"Target": "ALL",
+	}
+	_, header, err := cli.postResource(cli.rootPath+ManagerResource+"/Actions/Oem/"+action, req)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := newTaskHandle(header)
+	if err != nil {
+		return nil, err
+	}
+	task, resp, taskErr := cli.waitForTask(ctx, handle, nil, nil)
+	if task == nil {
+		return nil, taskErr
+	}
+	result := &SCPImportResult{
+		JobID:      task.ID,
+		TaskState:  task.TaskState,
+		TaskStatus: task.TaskStatus,
+		Changes:    []*SCPChange{},
+	}
+	if len(resp) > 0 {
+		response := &scpTaskResponse{}
+		if err := json.Unmarshal(resp, response); err != nil {
+			return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+		}
+		for _, entry := range response.Messages {
+			if entry.Oem.Dell.Name == "" {
+				// The messages without attribute are the messages of the job.
+				result.Message = entry.Message
+				continue
+			}
+			result.Changes = append(result.Changes, &SCPChange{
+				FQDD:      entry.Oem.Dell.FQDD,
+				Name:      entry.Oem.Dell.Name,
+				OldValue:  entry.Oem.Dell.OldValue,
+				NewValue:  entry.Oem.Dell.NewValue,
+				Status:    entry.Oem.Dell.Status,
+				MessageID: entry.MessageID,
+				Message:   entry.Message,
+			})
+		}
+	}
+	return result, taskErr
+}
+
+// scpTarget returns the comma-separated targets of an export, or ALL when
+// the targets are empty.
+func scpTarget(targets []string) (string, error) {
+	if len(targets) == 0 {
+		return "ALL", nil
+	}
+	normalized := []string{}
+	for _, target := range targets {
+		if err := validateSCPValue("profile target", &target, scpTargets); err != nil {
+			return "", err
+		}
+		normalized = append(normalized, target)
+	}
+	return strings.Join(normalized, ","), nil
+}
+
+// validateSCPValue returns an error when the value is not one of the
+// supported values, and, otherwise, normalizes the case of the value.
+func validateSCPValue(name string, value *string, supported []string) error {
+	for _, s := range supported {
+		if strings.EqualFold(s, *value) {
+			*value = s
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported %s %s, expecting %s", name, *value, strings.Join(supported, ", "))
+}
+
+// scpFormat returns the format of Server Configuration Profile, i.e. either
+// XML or JSON.
+func scpFormat(profile []byte) (string, error) {
+	s := bytes.TrimSpace(bytes.TrimPrefix(profile, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(s, []byte("<")):
+		if bytes.Contains(s, []byte("<SystemConfiguration")) {
+			return "XML", nil
+		}
+	case bytes.HasPrefix(s, []byte("{")):
+		doc := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(s, &doc); err == nil {
+			if _, exists := doc["SystemConfiguration"]; exists {
+				return "JSON", nil
+			}
+		}
+	}
+	return "", fmt.Errorf("the profile is neither XML nor JSON Server Configuration Profile")
+}
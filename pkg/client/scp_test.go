@@ -0,0 +1,167 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"bytes"
+	"context"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSCP(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+	if err := cli.SetTaskPollInterval(time.Millisecond); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	ctx := context.Background()
+
+	expProfile, err := ioutil.ReadFile("../../assets/responses/scp_1.xml")
+	if err != nil {
+		t.Fatalf("failed reading profile: %s", err)
+	}
+	for i, test := range []struct {
+		targets   []string
+		format    string
+		exportUse string
+	}{
+		{targets: []string{"ipmi"}, format: "xml"},
+		{format: "yaml"},
+		{exportUse: "Backup"},
+	} {
+		if _, err := cli.ExportSCP(ctx, test.targets, test.format, test.exportUse); err == nil {
+			t.Fatalf("client: Test %d: expected failure, but got non-error response", i)
+		}
+	}
+	// The export job is running, and, then, the task monitor responds with
+	// the profile.
+	profile, err := cli.ExportSCP(ctx, []string{"idrac", "BIOS"}, "xml", "clone")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if !bytes.Equal(profile, expProfile) {
+		t.Fatalf("client: unexpected profile: %s", profile)
+	}
+	if _, err := cli.ExportSCP(ctx, nil, "JSON", ""); err == nil {
+		t.Fatalf("client: expected failure due to XML profile, but got non-error response")
+	}
+
+	expChanges := []*SCPChange{
+		{
+			FQDD:      "iDRAC.Embedded.1",
+			Name:      "NTPConfigGroup.1#NTPEnable",
+			OldValue:  "Disabled",
+			NewValue:  "Enabled",
+			Status:    "Success",
+			MessageID: "IDRAC.2.1.RAC0613",
+		},
+		{
+			FQDD:      "iDRAC.Embedded.1",
+			Name:      "NTPConfigGroup.1#NTP1",
+			NewValue:  "10.10.10.1",
+			Status:    "Success",
+			MessageID: "IDRAC.2.1.RAC0613",
+		},
+		{
+			FQDD:      "BIOS.Setup.1-1",
+			Name:      "BootMode",
+			OldValue:  "Bios",
+			NewValue:  "Uefi",
+			Status:    "Success",
+			MessageID: "IDRAC.2.1.BIOS006",
+		},
+	}
+
+	preview, err := cli.PreviewSCP(ctx, profile)
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if !preview.Preview || preview.JobID != "JID_467800000003" || preview.TaskState != "Completed" {
+		t.Fatalf("client: unexpected preview: %+v", *preview)
+	}
+	for _, change := range expChanges {
+		change.Message = "The attribute value will be changed."
+	}
+	if !reflect.DeepEqual(preview.Changes, expChanges) {
+		t.Fatalf("client: unexpected preview changes: %+v", preview.Changes)
+	}
+
+	for i, test := range []struct {
+		profile        []byte
+		shutdownType   string
+		hostPowerState string
+	}{
+		{profile: []byte("BootMode=Uefi")},
+		{profile: []byte(`{"Components": []}`)},
+		{profile: profile, shutdownType: "Immediate"},
+		{profile: profile, hostPowerState: "Standby"},
+	} {
+		if _, err := cli.ImportSCP(ctx, test.profile, test.shutdownType, test.hostPowerState); err == nil {
+			t.Fatalf("client: Test %d: expected failure, but got non-error response", i)
+		}
+	}
+	result, err := cli.ImportSCP(ctx, profile, "forced", "")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if result.Preview || result.JobID != "JID_467800000002" || result.Message != "Successfully imported and applied Server Configuration Profile." {
+		t.Fatalf("client: unexpected import result: %+v", *result)
+	}
+	for _, change := range expChanges {
+		change.Message = "The attribute value was successfully changed."
+	}
+	if !reflect.DeepEqual(result.Changes, expChanges) {
+		t.Fatalf("client: unexpected import changes: %+v", result.Changes)
+	}
+	for _, resource := range []interface{}{result, &SCPChange{}} {
+		complianceMessages, compliant := isStructCompliant(resource)
+		if !compliant {
+			for _, entry := range complianceMessages {
+				t.Logf("%s", entry)
+			}
+			t.Fatalf("client: %T is not compliant", resource)
+		}
+	}
+}
+
+func TestSCPFormat(t *testing.T) {
+	for i, test := range []struct {
+		profile   string
+		exp       string
+		shouldErr bool
+	}{
+		{profile: "\xef\xbb\xbf\n<SystemConfiguration Model=\"PowerEdge R640\"></SystemConfiguration>", exp: "XML"},
+		{profile: `{"SystemConfiguration": {"Components": []}}`, exp: "JSON"},
+		{profile: `{"Components": []}`, shouldErr: true},
+		{profile: "<Component FQDD=\"BIOS.Setup.1-1\"></Component>", shouldErr: true},
+		{shouldErr: true},
+	} {
+		format, err := scpFormat([]byte(test.profile))
+		if err != nil {
+			if !test.shouldErr {
+				t.Fatalf("Test %d: expected success, but got error: %s", i, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Fatalf("Test %d: expected failure, but got non-error response", i)
+		}
+		if format != test.exp {
+			t.Fatalf("Test %d: expected format %s, but got %s", i, test.exp, format)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DefaultSystemID is the computer system of iDRAC.
+const DefaultSystemID = "System.Embedded.1"
+
+// The apply times of the settings requiring a reboot of the host, e.g. BIOS
+// attributes. The Immediate settings reboot the host right away, while the
+// OnReset settings are applied on the next reboot.
+const (
+	ApplyTimeImmediate = "Immediate"
+	ApplyTimeOnReset   = "OnReset"
+)
+
+type bootResponse struct {
+	Boot struct {
+		BootOrder []string
+	}
+}
+
+// GetManagerAttributes returns iDRAC attributes, e.g.
+// NTPConfigGroup.1.NTPEnable.
+func (cli *Client) GetManagerAttributes() (map[string]interface{}, error) {
+	return cli.getAttributes(cli.rootPath + managerAttributesPath)
+}
+
+// SetManagerAttributes changes iDRAC attributes. The changes apply
+// immediately.
+func (cli *Client) SetManagerAttributes(attributes map[string]interface{}) error {
+	if len(attributes) == 0 {
+		return fmt.Errorf("no attribute changes")
+	}
+	return cli.setAttributes(cli.rootPath+managerAttributesPath, attributes)
+}
+
+// GetBIOSAttributes returns the current BIOS attributes of a computer
+// system, e.g. System.Embedded.1.
+func (cli *Client) GetBIOSAttributes(systemID string) (map[string]interface{}, error) {
+	return cli.getAttributes(cli.rootPath + "Systems/" + systemID + "/Bios")
+}
+
+// GetPendingBIOSAttributes returns the BIOS attributes of a computer system
+// pending the next reboot of the system.
+func (cli *Client) GetPendingBIOSAttributes(systemID string) (map[string]interface{}, error) {
+	return cli.getAttributes(cli.rootPath + "Systems/" + systemID + "/Bios/Settings")
+}
+
+// SetBIOSAttributes changes the BIOS attributes of a computer system with
+// the apply time, i.e. Immediate or OnReset, and returns the configuration
+// job, if the service created one.
+func (cli *Client) SetBIOSAttributes(systemID string, attributes map[string]interface{}, applyTime string) (*TaskHandle, error) {
+	return cli.setPendingAttributes(cli.rootPath+"Systems/"+systemID+"/Bios/Settings", attributes, applyTime)
+}
+
+// GetNICFunctions returns the network device functions of the network
+// interfaces of a computer system, e.g. NIC.Integrated.1-1-1.
+func (cli *Client) GetNICFunctions(systemID string) ([]string, error) {
+	functions := []string{}
+	interfaces, err := cli.getCollectionMembers(cli.rootPath + "Systems/" + systemID + "/NetworkInterfaces/")
+	if err != nil {
+		return nil, err
+	}
+	for _, interfacePath := range interfaces {
+		resp, err := cli.callAPI("GET", "", interfacePath, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		response := &networkInterfaceResponse{}
+		if err := json.Unmarshal(resp, response); err != nil {
+			return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+		}
+		if response.NetworkDeviceFunctions.ID == "" {
+			continue
+		}
+		functionPaths, err := cli.getCollectionMembers(response.NetworkDeviceFunctions.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, functionPath := range functionPaths {
+			functions = append(functions, path.Base(functionPath))
+		}
+	}
+	return functions, nil
+}
+
+// nicAttributesPath returns the path of the attributes of a network device
+// function, e.g. NIC.Integrated.1-1-1.
+func (cli *Client) nicAttributesPath(fqdd string) (string, error) {
+	i := strings.Index(fqdd, "-")
+	if !strings.HasPrefix(fqdd, "NIC.") || i < 0 {
+		return "", fmt.Errorf("network device function %s is invalid, expecting e.g. NIC.Integrated.1-1-1", fqdd)
+	}
+	return cli.rootPath + "Chassis/System.Embedded.1/NetworkAdapters/" + fqdd[:i] +
+		"/NetworkDeviceFunctions/" + fqdd + "/Oem/Dell/DellNetworkAttributes/" + fqdd, nil
+}
+
+// GetNICAttributes returns the current attributes of a network device
+// function, e.g. NIC.Integrated.1-1-1.
+func (cli *Client) GetNICAttributes(fqdd string) (map[string]interface{}, error) {
+	s, err := cli.nicAttributesPath(fqdd)
+	if err != nil {
+		return nil, err
+	}
+	return cli.getAttributes(s)
+}
+
+// GetPendingNICAttributes returns the attributes of a network device
+// function pending the next reboot of the system.
+func (cli *Client) GetPendingNICAttributes(fqdd string) (map[string]interface{}, error) {
+	s, err := cli.nicAttributesPath(fqdd)
+	if err != nil {
+		return nil, err
+	}
+	return cli.getAttributes(s + "/Settings")
+}
+
+// SetNICAttributes changes the attributes of a network device function
+// with the apply time, i.e. Immediate or OnReset, and returns the
+// configuration job, if the service created one.
+func (cli *Client) SetNICAttributes(fqdd string, attributes map[string]interface{}, applyTime string) (*TaskHandle, error) {
+	s, err := cli.nicAttributesPath(fqdd)
+	if err != nil {
+		return nil, err
+	}
+	return cli.setPendingAttributes(s+"/Settings", attributes, applyTime)
+}
+
+// setPendingAttributes changes the attributes of a settings resource, i.e.
+// the attributes applied by a configuration job.
+func (cli *Client) setPendingAttributes(urlPath string, attributes map[string]interface{}, applyTime string) (*TaskHandle, error) {
+	if len(attributes) == 0 {
+		return nil, fmt.Errorf("no attribute changes")
+	}
+	properties := map[string]interface{}{
+		"Attributes": attributes,
+	}
+	switch applyTime {
+	case "":
+	case ApplyTimeImmediate, ApplyTimeOnReset:
+		properties["@Redfish.SettingsApplyTime"] = map[string]interface{}{
+			"ApplyTime": applyTime,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported apply time %s, expecting %s or %s", applyTime, ApplyTimeImmediate, ApplyTimeOnReset)
+	}
+	payload, err := json.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+	_, header, err := cli.callAPIWithHeaders("PATCH", "application/json", urlPath, payload)
+	if err != nil {
+		return nil, err
+	}
+	if header.Get("Location") == "" {
+		return nil, nil
+	}
+	return newTaskHandle(header)
+}
+
+// GetBootOrder returns the boot order of a computer system, e.g.
+// Boot0001 and Boot0002.
+func (cli *Client) GetBootOrder(systemID string) ([]string, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"Systems/"+systemID, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	response := &bootResponse{}
+	if err := json.Unmarshal(resp, response); err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+	}
+	return nonNilStrings(response.Boot.BootOrder), nil
+}
+
+// GetPendingBootOrder returns the boot order of a computer system pending
+// the next reboot of the system. It returns nil when the service has no
+// pending settings of the system.
+func (cli *Client) GetPendingBootOrder(systemID string) ([]string, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"Systems/"+systemID+"/Settings", []byte{})
+	if err != nil {
+		if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	response := &bootResponse{}
+	if err := json.Unmarshal(resp, response); err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+	}
+	if len(response.Boot.BootOrder) == 0 {
+		return nil, nil
+	}
+	return response.Boot.BootOrder, nil
+}
+
+// SetBootOrder changes the boot order of a computer system. The boot order
+// applies on the next reboot of the system.
+func (cli *Client) SetBootOrder(systemID string, bootOrder []string) error {
+	if len(bootOrder) == 0 {
+		return fmt.Errorf("boot order is empty")
+	}
+	_, err := cli.patchResource(cli.rootPath+"Systems/"+systemID, map[string]interface{}{
+		"Boot": map[string]interface{}{
+			"BootOrder": bootOrder,
+		},
+	})
+	return err
+}
+
+// FormatAttributeValue returns the string representation of an attribute
+// value, or an empty string when the attribute is absent. The numbers of
+// the service are float64, while the numbers of YAML documents are int,
+// and both format the same, e.g. 60. The lists and the objects are JSON.
+func FormatAttributeValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) < 1<<63 {
+			return strconv.FormatInt(int64(value), 10)
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool, int, int64, uint64:
+		return fmt.Sprint(value)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
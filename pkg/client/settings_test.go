@@ -0,0 +1,150 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"reflect"
+	"testing"
+)
+
+func TestSettings(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	attributes, err := cli.GetBIOSAttributes("System.Embedded.1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if attributes["BootMode"] != "Bios" || attributes["AcPwrRcvryUserDelay"] != float64(60) {
+		t.Fatalf("client: unexpected BIOS attributes: %v", attributes)
+	}
+	pending, err := cli.GetPendingBIOSAttributes("System.Embedded.1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if !reflect.DeepEqual(pending, map[string]interface{}{"LogicalProc": "Disabled"}) {
+		t.Fatalf("client: unexpected pending BIOS attributes: %v", pending)
+	}
+
+	for i, test := range []struct {
+		attributes map[string]interface{}
+		applyTime  string
+		exp        *TaskHandle
+		shouldErr  bool
+	}{
+		{
+			attributes: map[string]interface{}{"BootMode": "Uefi"},
+			applyTime:  ApplyTimeOnReset,
+			exp: &TaskHandle{
+				TaskURI: "/redfish/v1/TaskService/Tasks/JID_467799000000",
+				JobID:   "JID_467799000000",
+			},
+		},
+		{attributes: map[string]interface{}{"BootMode": "Uefi"}, applyTime: "AtMaintenanceWindowStart", shouldErr: true},
+		{attributes: map[string]interface{}{}, shouldErr: true},
+	} {
+		handle, err := cli.SetBIOSAttributes("System.Embedded.1", test.attributes, test.applyTime)
+		if err != nil {
+			if !test.shouldErr {
+				t.Fatalf("client: Test %d: expected success, but got error: %s", i, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Fatalf("client: Test %d: expected failure, but got non-error response", i)
+		}
+		if !reflect.DeepEqual(handle, test.exp) {
+			t.Fatalf("client: Test %d: unexpected task handle: %+v", i, handle)
+		}
+	}
+
+	functions, err := cli.GetNICFunctions("System.Embedded.1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	expFunctions := []string{
+		"NIC.Integrated.1-1-1", "NIC.Integrated.1-2-1", "NIC.Integrated.1-3-1", "NIC.Integrated.1-4-1",
+		"NIC.Slot.2-1-1", "NIC.Slot.2-2-1",
+	}
+	if !reflect.DeepEqual(functions, expFunctions) {
+		t.Fatalf("client: unexpected network device functions: %v", functions)
+	}
+
+	nic, err := cli.GetNICAttributes("NIC.Integrated.1-1-1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if nic["LegacyBootProto"] != "NONE" {
+		t.Fatalf("client: unexpected NIC attributes: %v", nic)
+	}
+	if _, err := cli.GetPendingNICAttributes("NIC.Integrated.1-1-1"); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	handle, err := cli.SetNICAttributes("NIC.Integrated.1-1-1", map[string]interface{}{"LegacyBootProto": "PXE"}, ApplyTimeImmediate)
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if handle == nil || handle.JobID != "JID_467799000001" {
+		t.Fatalf("client: unexpected task handle: %+v", handle)
+	}
+	if _, err := cli.GetNICAttributes("BIOS.Setup.1-1"); err == nil {
+		t.Fatalf("client: expected failure due to invalid network device function, but got non-error response")
+	}
+
+	bootOrder, err := cli.GetBootOrder("System.Embedded.1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if !reflect.DeepEqual(bootOrder, []string{"Boot0001", "Boot0002"}) {
+		t.Fatalf("client: unexpected boot order: %v", bootOrder)
+	}
+	if err := cli.SetBootOrder("System.Embedded.1", []string{"Boot0002", "Boot0001"}); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if err := cli.SetBootOrder("System.Embedded.1", nil); err == nil {
+		t.Fatalf("client: expected failure due to empty boot order, but got non-error response")
+	}
+
+	idrac, err := cli.GetManagerAttributes()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if idrac["NTPConfigGroup.1.NTPEnable"] != "Enabled" {
+		t.Fatalf("client: unexpected iDRAC attributes: %v", idrac)
+	}
+	if err := cli.SetManagerAttributes(map[string]interface{}{"IPMILan.1.Enable": "Disabled"}); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+}
+
+func TestFormatAttributeValue(t *testing.T) {
+	for i, test := range []struct {
+		value interface{}
+		exp   string
+	}{
+		{value: float64(60), exp: "60"},
+		{value: 60, exp: "60"},
+		{value: 1.5, exp: "1.5"},
+		{value: float64(1e21), exp: "1000000000000000000000"},
+		{value: true, exp: "true"},
+		{value: nil, exp: ""},
+		{value: "Enabled", exp: "Enabled"},
+		{value: []interface{}{"NIC.Integrated.1-1-1", "HardDisk.List.1-1"}, exp: `["NIC.Integrated.1-1-1","HardDisk.List.1-1"]`},
+		{value: map[string]interface{}{"b": 1, "a": "x"}, exp: `{"a":"x","b":1}`},
+	} {
+		if got := FormatAttributeValue(test.value); got != test.exp {
+			t.Fatalf("Test %d: expected %q, but got %q", i, test.exp, got)
+		}
+	}
+}
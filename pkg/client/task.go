@@ -0,0 +1,291 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// DefaultTaskPollInterval is the default interval between the requests
+// checking the state of a task.
+const DefaultTaskPollInterval = 5 * time.Second
+
+// TaskHandle identifies a long-running operation, i.e. the task monitor
+// returned by the service, and the id of Dell job, if the task is a job.
+type TaskHandle struct {
+	TaskURI string `yaml:"task_uri" json:"task_uri" xml:"task_uri"`
+	JobID   string `yaml:"job_id" json:"job_id" xml:"job_id"`
+}
+
+// newTaskHandle returns TaskHandle instance from the Location header of
+// the response accepting a long-running operation.
+func newTaskHandle(header http.Header) (*TaskHandle, error) {
+	location := header.Get("Location")
+	if location == "" {
+		return nil, fmt.Errorf("the service accepted the operation, but returned no task location")
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("task location %s is invalid: %s", location, err)
+	}
+	handle := &TaskHandle{
+		TaskURI: u.Path,
+	}
+	if id := path.Base(u.Path); strings.HasPrefix(id, "JID_") || strings.HasPrefix(id, "RID_") {
+		handle.JobID = id
+	}
+	return handle, nil
+}
+
+type taskResponse struct {
+	ODataAnnotation
+	ID              string `json:"Id"`
+	Name            string
+	Description     string
+	TaskState       string
+	TaskStatus      string
+	PercentComplete int
+	StartTime       string
+	EndTime         string
+	TaskMonitor     string
+	Messages        []struct {
+		MessageID   string `json:"MessageId"`
+		Message     string
+		MessageArgs []string
+		Severity    string
+		Resolution  string
+	}
+}
+
+// Task represents an instance of Redfish Task resource, i.e. the state of a
+// long-running operation.
+type Task struct {
+	ID              string           `yaml:"id" json:"id" xml:"id"`
+	OData           *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name            string           `yaml:"name" json:"name" xml:"name"`
+	Description     string           `yaml:"description" json:"description" xml:"description"`
+	TaskState       string           `yaml:"task_state" json:"task_state" xml:"task_state"`
+	TaskStatus      string           `yaml:"task_status" json:"task_status" xml:"task_status"`
+	PercentComplete int              `yaml:"percent_complete" json:"percent_complete" xml:"percent_complete"`
+	StartTime       string           `yaml:"start_time" json:"start_time" xml:"start_time"`
+	EndTime         string           `yaml:"end_time" json:"end_time" xml:"end_time"`
+	TaskMonitor     string           `yaml:"task_monitor" json:"task_monitor" xml:"task_monitor"`
+	Messages        []*Message       `yaml:"messages" json:"messages" xml:"messages"`
+}
+
+// IsDone returns true when the task is in a final state, i.e. Completed,
+// Killed, Exception, or Cancelled.
+func (t *Task) IsDone() bool {
+	switch t.TaskState {
+	case "Completed", "Killed", "Exception", "Cancelled":
+		return true
+	}
+	return false
+}
+
+// IsFailed returns true when the task is done, but did not complete
+// successfully.
+func (t *Task) IsFailed() bool {
+	if !t.IsDone() {
+		return false
+	}
+	return t.TaskState != "Completed" || t.TaskStatus == "Critical"
+}
+
+// failure returns the error of a failed task, i.e. the last message of the
+// task.
+func (t *Task) failure() error {
+	reason := t.TaskStatus
+	if len(t.Messages) > 0 {
+		reason = t.Messages[len(t.Messages)-1].Message
+	}
+	return fmt.Errorf("task %s failed with state %s: %s", t.ID, t.TaskState, reason)
+}
+
+// TaskProgressFunc receives a task when its state or its percent complete
+// changes.
+type TaskProgressFunc func(task *Task)
+
+// taskURI returns the path of a task, i.e. either the path itself, or the
+// path of a task id in the task service.
+func (cli *Client) taskURI(id string) string {
+	if strings.HasPrefix(id, "/") {
+		return id
+	}
+	return cli.rootPath + "TaskService/Tasks/" + id
+}
+
+// GetTask returns an instance of Redfish Task resource. The id is either
+// the id of the task, e.g. JID_467762674724, or the path of the task.
+func (cli *Client) GetTask(id string) (*Task, error) {
+	if id == "" {
+		return nil, fmt.Errorf("task id is empty")
+	}
+	task, _, err := cli.getTask(cli.taskURI(id))
+	return task, err
+}
+
+// getTask returns a task and the response of the service. The response is
+// returned along with the parsing error when the response is not a task,
+// e.g. the task monitor responds with the result of the operation once the
+// operation completed.
+func (cli *Client) getTask(s string) (*Task, []byte, error) {
+	resp, err := cli.callAPI("GET", "", s, []byte{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(resp) == 0 {
+		// The task monitors respond with no content once the operation
+		// completed.
+		return newCompletedTask(s), resp, nil
+	}
+	task, err := newTaskFromBytes(resp)
+	if err != nil {
+		return nil, resp, err
+	}
+	return task, resp, nil
+}
+
+// newCompletedTask returns the task of a completed operation, whose task
+// monitor responded with the result of the operation.
+func newCompletedTask(s string) *Task {
+	return &Task{
+		ID:              path.Base(s),
+		OData:           &ODataAnnotation{ID: s},
+		TaskState:       "Completed",
+		PercentComplete: 100,
+		Messages:        []*Message{},
+	}
+}
+
+// ListTasks returns the tasks of the task service.
+func (cli *Client) ListTasks() ([]*Task, error) {
+	members, err := cli.getCollectionMembers(cli.rootPath + "TaskService/Tasks")
+	if err != nil {
+		return nil, err
+	}
+	tasks := []*Task{}
+	for _, member := range members {
+		task, err := cli.GetTask(member)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// WaitForTask polls the state of a task until the task is done, or the
+// context is done, and returns the last state of the task. The progress
+// function, if any, receives the task when its state or its percent
+// complete changes. The function returns an error when the task failed.
+// The connection errors are retried, because the service may be
+// unavailable for a while, e.g. during iDRAC firmware update.
+func (cli *Client) WaitForTask(ctx context.Context, handle *TaskHandle, progress TaskProgressFunc) (*Task, error) {
+	task, _, err := cli.waitForTask(ctx, handle, progress, nil)
+	return task, err
+}
+
+// waitForTask polls the state of a task, see WaitForTask, and returns the
+// last response of the service, i.e. either the task, or the result of the
+// operation the task monitor responded with. The isResult function, if any,
+// tells the result of the operation from the other responses.
+func (cli *Client) waitForTask(ctx context.Context, handle *TaskHandle, progress TaskProgressFunc, isResult func([]byte) bool) (*Task, []byte, error) {
+	id := handle.TaskURI
+	if id == "" {
+		id = handle.JobID
+	}
+	if id == "" {
+		return nil, nil, fmt.Errorf("task handle has neither task uri nor job id")
+	}
+	s := cli.taskURI(id)
+	var last *Task
+	for {
+		task, resp, err := cli.getTask(s)
+		if err != nil && resp != nil {
+			if isResult != nil && isResult(resp) {
+				// The response is not a task, but the result of the
+				// operation, e.g. the exported profile.
+				task, err = newCompletedTask(s), nil
+			}
+			// Otherwise, the response is neither a task nor a result, e.g.
+			// an error page during iDRAC reset, and the task is polled again.
+		}
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				return last, nil, err
+			}
+			log.Debugf("task %s: %s", id, err)
+		} else {
+			if progress != nil && (last == nil || last.TaskState != task.TaskState || last.PercentComplete != task.PercentComplete) {
+				progress(task)
+			}
+			last = task
+			if task.IsFailed() {
+				return task, resp, task.failure()
+			}
+			if task.IsDone() {
+				return task, resp, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return last, nil, ctx.Err()
+		case <-time.After(cli.taskPollInterval):
+		}
+	}
+}
+
+// newTaskFromString returns Task instance from an input string.
+func newTaskFromString(s string) (*Task, error) {
+	return newTaskFromBytes([]byte(s))
+}
+
+// newTaskFromBytes returns Task instance from an input byte array.
+func newTaskFromBytes(s []byte) (*Task, error) {
+	response := &taskResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the task is empty, server response: %s", string(s[:]))
+	}
+	task := &Task{
+		ID:              response.ID,
+		Name:            response.Name,
+		Description:     response.Description,
+		TaskState:       response.TaskState,
+		TaskStatus:      response.TaskStatus,
+		PercentComplete: response.PercentComplete,
+		StartTime:       response.StartTime,
+		EndTime:         response.EndTime,
+		TaskMonitor:     response.TaskMonitor,
+		Messages:        []*Message{},
+	}
+	for _, entry := range response.Messages {
+		task.Messages = append(task.Messages, &Message{
+			MessageID:   entry.MessageID,
+			Message:     entry.Message,
+			MessageArgs: nonNilStrings(entry.MessageArgs),
+			Severity:    entry.Severity,
+			Resolution:  entry.Resolution,
+		})
+	}
+	task.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return task, nil
+}
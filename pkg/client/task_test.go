@@ -0,0 +1,198 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"context"
+	"fmt"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTaskJsonOutput(t *testing.T) {
+	testFailed := 0
+	dataDir := "../../assets/responses"
+	for i, test := range []struct {
+		input      string
+		exp        *Task
+		shouldFail bool // Whether test should result in a failure
+		shouldErr  bool // Whether parsing of a response should result in error
+	}{
+		{
+			input: "task_4",
+			exp: &Task{
+				ID: "JID_467762000001",
+				OData: NewODataAnnotation(
+					"/redfish/v1/TaskService/Tasks/JID_467762000001",
+					"#Task.v1_4_2.Task",
+					"/redfish/v1/$metadata#Task.Task",
+				),
+				Name:            "Firmware Update: BIOS",
+				Description:     "Server Configuration and other Tasks running on iDRAC are listed here",
+				TaskState:       "Exception",
+				TaskStatus:      "Critical",
+				PercentComplete: 100,
+				StartTime:       "2020-11-12T15:10:02-06:00",
+				EndTime:         "2020-11-12T14:02:19-06:00",
+				TaskMonitor:     "/redfish/v1/TaskService/TaskMonitors/JID_467762000001",
+				Messages: []*Message{
+					{
+						MessageID:   "IDRAC.2.1.RED004",
+						Message:     "Unable to update the firmware because the package is not compatible with the component.",
+						MessageArgs: []string{},
+					},
+				},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			input:      "root_2",
+			shouldFail: false,
+			shouldErr:  true,
+		},
+	} {
+		// Read response file
+		fp := fmt.Sprintf("%s/%s.json", dataDir, test.input)
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			t.Logf("FAIL: Test %d: failed reading '%s', error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+
+		// Parse API response
+		task, err := newTaskFromBytes(content)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input '%s', expected to pass, but threw error: %v", i, fp, err)
+				testFailed++
+			} else {
+				t.Logf("PASS: Test %d: input '%s', expected to throw error, threw error: %v", i, fp, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input '%s', expected to throw error, but passed: %v", i, fp, *task)
+			testFailed++
+			continue
+		}
+
+		taskFromString, err := newTaskFromString(string(content))
+		if err != nil {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(taskFromString, task) {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (newTaskFromString) vs. '%v' (newTaskFromBytes)",
+				i, fp, *taskFromString, *task)
+			testFailed++
+			continue
+		}
+
+		if !reflect.DeepEqual(task, test.exp) && !test.shouldFail {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (actual) vs. '%v' (expected)",
+				i, fp, *task, *test.exp)
+			testFailed++
+			continue
+		}
+
+		if !task.IsDone() || !task.IsFailed() {
+			t.Logf("FAIL: Test %d: input '%s', expected the task to be done and failed", i, fp)
+			testFailed++
+			continue
+		}
+
+		complianceMessages, compliant := isStructCompliant(task)
+		if !compliant {
+			testFailed++
+			for _, entry := range complianceMessages {
+				t.Logf("%s", entry)
+			}
+		}
+
+		t.Logf("PASS: Test %d: input '%s', expected to pass, passed", i, fp)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestWaitForTask(t *testing.T) {
+	server, err := NewMockTestServer(map[string]string{
+		// The service responds with an error page while iDRAC resets.
+		"/redfish/v1/TaskService/Tasks/JID_467762000002": "task_1.json,error_page_1.html,task_3.json",
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+	if err := cli.SetTaskPollInterval(time.Millisecond); err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+
+	// The task is starting, running, and, then, completed.
+	states := []string{}
+	progress := func(task *Task) {
+		states = append(states, fmt.Sprintf("%s %d%%", task.TaskState, task.PercentComplete))
+	}
+	handle := &TaskHandle{
+		TaskURI: "/redfish/v1/TaskService/Tasks/JID_467762674724",
+		JobID:   "JID_467762674724",
+	}
+	task, err := cli.WaitForTask(context.Background(), handle, progress)
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if task.TaskState != "Completed" {
+		t.Fatalf("client: expected completed task, but got %s", task.TaskState)
+	}
+	expStates := []string{"Starting 0%", "Running 50%", "Completed 100%"}
+	if !reflect.DeepEqual(states, expStates) {
+		t.Fatalf("client: unexpected task progress: %v", states)
+	}
+
+	// The error page is not the result of the task, i.e. the task is polled
+	// until it completes.
+	task, err = cli.WaitForTask(context.Background(), &TaskHandle{JobID: "JID_467762000002"}, nil)
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if task.ID != "JID_467762674724" || task.TaskState != "Completed" {
+		t.Fatalf("client: expected the completed task, but got %s %s", task.ID, task.TaskState)
+	}
+
+	task, err = cli.WaitForTask(context.Background(), &TaskHandle{JobID: "JID_467762000001"}, nil)
+	if err == nil {
+		t.Fatalf("client: expected failure of the task, but got non-error response")
+	}
+	if task == nil || task.TaskState != "Exception" {
+		t.Fatalf("client: expected the last state of the failed task, but got %+v", task)
+	}
+
+	if _, err := cli.WaitForTask(context.Background(), &TaskHandle{}, nil); err == nil {
+		t.Fatalf("client: expected failure due to empty task handle, but got non-error response")
+	}
+	if _, err := cli.WaitForTask(context.Background(), &TaskHandle{JobID: "JID_000000000000"}, nil); err == nil {
+		t.Fatalf("client: expected failure due to unknown task, but got non-error response")
+	}
+
+	tasks, err := cli.ListTasks()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("client: expected 2 tasks, but got %d", len(tasks))
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type thermalResponse struct {
+	ODataAnnotation
+	ID                  string `json:"Id"`
+	Name                string
+	Description         string
+	Fans                []thermalFanResponse
+	FansCounter         uint64 `json:"Fans@odata.count"`
+	Temperatures        []thermalTemperatureResponse
+	TemperaturesCounter uint64 `json:"Temperatures@odata.count"`
+	Redundancy          []interface{}
+	RedundancyCounter   uint64 `json:"Redundancy@odata.count"`
+}
+
+type thermalFanResponse struct {
+	ODataAnnotation
+	MemberID                  string `json:"MemberId"`
+	Name                      string
+	FanName                   string
+	PhysicalContext           string
+	Reading                   float64
+	ReadingUnits              string
+	MinReadingRange           float64
+	MaxReadingRange           float64
+	LowerThresholdNonCritical float64
+	LowerThresholdCritical    float64
+	LowerThresholdFatal       float64
+	UpperThresholdNonCritical float64
+	UpperThresholdCritical    float64
+	UpperThresholdFatal       float64
+	Status                    HealthStatus
+}
+
+type thermalTemperatureResponse struct {
+	ODataAnnotation
+	MemberID                  string `json:"MemberId"`
+	Name                      string
+	SensorNumber              uint64
+	PhysicalContext           string
+	ReadingCelsius            float64
+	MinReadingRangeTemp       float64
+	MaxReadingRangeTemp       float64
+	LowerThresholdNonCritical float64
+	LowerThresholdCritical    float64
+	LowerThresholdFatal       float64
+	UpperThresholdNonCritical float64
+	UpperThresholdCritical    float64
+	UpperThresholdFatal       float64
+	Status                    HealthStatus
+}
+
+// Thermal represents an instance of Redfish Thermal resource of a chassis.
+type Thermal struct {
+	ID           string           `yaml:"id" json:"id" xml:"id"`
+	OData        *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name         string           `yaml:"name" json:"name" xml:"name"`
+	Description  string           `yaml:"description" json:"description" xml:"description"`
+	Temperatures []*Temperature   `yaml:"temperatures" json:"temperatures" xml:"temperatures"`
+	Fans         []*Fan           `yaml:"fans" json:"fans" xml:"fans"`
+}
+
+// Temperature represents a temperature sensor of a chassis.
+type Temperature struct {
+	MemberID                  string       `yaml:"member_id" json:"member_id" xml:"member_id"`
+	Name                      string       `yaml:"name" json:"name" xml:"name"`
+	SensorNumber              uint64       `yaml:"sensor_number" json:"sensor_number" xml:"sensor_number"`
+	PhysicalContext           string       `yaml:"physical_context" json:"physical_context" xml:"physical_context"`
+	ReadingCelsius            float64      `yaml:"reading_celsius" json:"reading_celsius" xml:"reading_celsius"`
+	LowerThresholdNonCritical float64      `yaml:"lower_threshold_non_critical" json:"lower_threshold_non_critical" xml:"lower_threshold_non_critical"`
+	LowerThresholdCritical    float64      `yaml:"lower_threshold_critical" json:"lower_threshold_critical" xml:"lower_threshold_critical"`
+	UpperThresholdNonCritical float64      `yaml:"upper_threshold_non_critical" json:"upper_threshold_non_critical" xml:"upper_threshold_non_critical"`
+	UpperThresholdCritical    float64      `yaml:"upper_threshold_critical" json:"upper_threshold_critical" xml:"upper_threshold_critical"`
+	Status                    HealthStatus `yaml:"status" json:"status" xml:"status"`
+}
+
+// Fan represents a fan of a chassis.
+type Fan struct {
+	MemberID                  string       `yaml:"member_id" json:"member_id" xml:"member_id"`
+	Name                      string       `yaml:"name" json:"name" xml:"name"`
+	PhysicalContext           string       `yaml:"physical_context" json:"physical_context" xml:"physical_context"`
+	Reading                   float64      `yaml:"reading" json:"reading" xml:"reading"`
+	ReadingUnits              string       `yaml:"reading_units" json:"reading_units" xml:"reading_units"`
+	LowerThresholdNonCritical float64      `yaml:"lower_threshold_non_critical" json:"lower_threshold_non_critical" xml:"lower_threshold_non_critical"`
+	LowerThresholdCritical    float64      `yaml:"lower_threshold_critical" json:"lower_threshold_critical" xml:"lower_threshold_critical"`
+	Status                    HealthStatus `yaml:"status" json:"status" xml:"status"`
+}
+
+// GetThermal returns an instance of Redfish Thermal resource for a chassis,
+// e.g. System.Embedded.1.
+func (cli *Client) GetThermal(chassisID string) (*Thermal, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath+"Chassis/"+chassisID+"/Thermal/", []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newThermalFromBytes(resp)
+}
+
+// newThermalFromString returns Thermal instance from an input string.
+func newThermalFromString(s string) (*Thermal, error) {
+	return newThermalFromBytes([]byte(s))
+}
+
+// newThermalFromBytes returns Thermal instance from an input byte array.
+func newThermalFromBytes(s []byte) (*Thermal, error) {
+	t := &Thermal{
+		Temperatures: []*Temperature{},
+		Fans:         []*Fan{},
+	}
+	response := &thermalResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	t.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	t.ID = response.ID
+	t.Name = response.Name
+	t.Description = response.Description
+	for _, entry := range response.Temperatures {
+		t.Temperatures = append(t.Temperatures, &Temperature{
+			MemberID:                  entry.MemberID,
+			Name:                      entry.Name,
+			SensorNumber:              entry.SensorNumber,
+			PhysicalContext:           entry.PhysicalContext,
+			ReadingCelsius:            entry.ReadingCelsius,
+			LowerThresholdNonCritical: entry.LowerThresholdNonCritical,
+			LowerThresholdCritical:    entry.LowerThresholdCritical,
+			UpperThresholdNonCritical: entry.UpperThresholdNonCritical,
+			UpperThresholdCritical:    entry.UpperThresholdCritical,
+			Status:                    entry.Status,
+		})
+	}
+	for _, entry := range response.Fans {
+		name := entry.Name
+		if name == "" {
+			name = entry.FanName
+		}
+		t.Fans = append(t.Fans, &Fan{
+			MemberID:                  entry.MemberID,
+			Name:                      name,
+			PhysicalContext:           entry.PhysicalContext,
+			Reading:                   entry.Reading,
+			ReadingUnits:              entry.ReadingUnits,
+			LowerThresholdNonCritical: entry.LowerThresholdNonCritical,
+			LowerThresholdCritical:    entry.LowerThresholdCritical,
+			Status:                    entry.Status,
+		})
+	}
+	return t, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"fmt"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseThermalJsonOutput(t *testing.T) {
+	testFailed := 0
+	dataDir := "../../assets/responses"
+	for i, test := range []struct {
+		input        string
+		temperatures int
+		fans         int
+		exp          *Temperature
+		shouldFail   bool // Whether test should result in a failure
+		shouldErr    bool // Whether parsing of a response should result in error
+	}{
+		{
+			input:        "thermal_1",
+			temperatures: 4,
+			fans:         2,
+			exp: &Temperature{
+				MemberID:                  "iDRAC.Embedded.1#SystemBoardInletTemp",
+				Name:                      "System Board Inlet Temp",
+				SensorNumber:              4,
+				PhysicalContext:           "SystemBoard",
+				ReadingCelsius:            22,
+				LowerThresholdNonCritical: 8,
+				LowerThresholdCritical:    3,
+				UpperThresholdNonCritical: 42,
+				UpperThresholdCritical:    47,
+				Status: HealthStatus{
+					Health: "OK",
+					State:  "Enabled",
+				},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			input:      "root_2",
+			shouldFail: false,
+			shouldErr:  true,
+		},
+	} {
+		// Read response file
+		fp := fmt.Sprintf("%s/%s.json", dataDir, test.input)
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			t.Logf("FAIL: Test %d: failed reading '%s', error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+
+		// Parse API response
+		thermal, err := newThermalFromBytes(content)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input '%s', expected to pass, but threw error: %v", i, fp, err)
+				testFailed++
+			} else {
+				t.Logf("PASS: Test %d: input '%s', expected to throw error, threw error: %v", i, fp, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input '%s', expected to throw error, but passed: %v", i, fp, *thermal)
+			testFailed++
+			continue
+		}
+
+		thermalFromString, err := newThermalFromString(string(content))
+		if err != nil {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(thermalFromString, thermal) {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (newThermalFromString) vs. '%v' (newThermalFromBytes)",
+				i, fp, *thermalFromString, *thermal)
+			testFailed++
+			continue
+		}
+
+		if len(thermal.Temperatures) != test.temperatures {
+			t.Logf("FAIL: Test %d: input '%s', expected %d temperature sensors, but got %d", i, fp, test.temperatures, len(thermal.Temperatures))
+			testFailed++
+			continue
+		}
+		if len(thermal.Fans) != test.fans {
+			t.Logf("FAIL: Test %d: input '%s', expected %d fans, but got %d", i, fp, test.fans, len(thermal.Fans))
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(thermal.Temperatures[0], test.exp) && !test.shouldFail {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch in '%s' field: '%v' (actual) vs. '%v' (expected)",
+				i, fp, "Temperatures", *thermal.Temperatures[0], *test.exp)
+			testFailed++
+			continue
+		}
+
+		for _, resource := range []interface{}{thermal, thermal.Temperatures[0], thermal.Fans[0]} {
+			complianceMessages, compliant := isStructCompliant(resource)
+			if !compliant {
+				testFailed++
+				for _, entry := range complianceMessages {
+					t.Logf("%s", entry)
+				}
+			}
+		}
+
+		t.Logf("PASS: Test %d: input '%s', expected to pass, passed", i, fp)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestGetThermal(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	thermal, err := cli.GetThermal("System.Embedded.1")
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	for _, fan := range thermal.Fans {
+		t.Logf("Fan: %s | Reading: %.0f %s | Health: %s", fan.Name, fan.Reading, fan.ReadingUnits, fan.Status.Health)
+	}
+
+	if _, err := cli.GetThermal("System.Embedded.2"); err == nil {
+		t.Fatalf("client: expected failure, but got non-error response")
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+type updateServiceResponse struct {
+	ODataAnnotation
+	ID                   string `json:"Id"`
+	Name                 string
+	Description          string
+	ServiceEnabled       bool
+	HTTPPushURI          string `json:"HttpPushUri"`
+	MultipartHTTPPushURI string `json:"MultipartHttpPushUri"`
+	MaxImageSizeBytes    *uint64
+	FirmwareInventory    ODataAnnotation
+	Status               HealthStatus
+	Actions              struct {
+		SimpleUpdate struct {
+			Target            string   `json:"target"`
+			TransferProtocols []string `json:"TransferProtocol@Redfish.AllowableValues"`
+			ApplyTimeSupport  struct {
+				SupportedValues []string
+			} `json:"@Redfish.OperationApplyTimeSupport"`
+		} `json:"#UpdateService.SimpleUpdate"`
+	}
+}
+
+// UpdateService represents an instance of Redfish UpdateService resource,
+// i.e. the firmware inventory and the firmware updates.
+type UpdateService struct {
+	ID                   string           `yaml:"id" json:"id" xml:"id"`
+	OData                *ODataAnnotation `yaml:"odata" json:"odata" xml:"odata"`
+	Name                 string           `yaml:"name" json:"name" xml:"name"`
+	Description          string           `yaml:"description" json:"description" xml:"description"`
+	ServiceEnabled       bool             `yaml:"service_enabled" json:"service_enabled" xml:"service_enabled"`
+	HTTPPushURI          string           `yaml:"http_push_uri" json:"http_push_uri" xml:"http_push_uri"`
+	MultipartHTTPPushURI string           `yaml:"multipart_http_push_uri" json:"multipart_http_push_uri" xml:"multipart_http_push_uri"`
+	MaxImageSizeBytes    uint64           `yaml:"max_image_size_bytes" json:"max_image_size_bytes" xml:"max_image_size_bytes"`
+	FirmwareInventoryURI string           `yaml:"firmware_inventory_uri" json:"firmware_inventory_uri" xml:"firmware_inventory_uri"`
+	SimpleUpdateURI      string           `yaml:"simple_update_uri" json:"simple_update_uri" xml:"simple_update_uri"`
+	TransferProtocols    []string         `yaml:"transfer_protocols" json:"transfer_protocols" xml:"transfer_protocols"`
+	ApplyTimes           []string         `yaml:"apply_times" json:"apply_times" xml:"apply_times"`
+	Status               HealthStatus     `yaml:"status" json:"status" xml:"status"`
+}
+
+type softwareInventoryResponse struct {
+	ODataAnnotation
+	ID                     string `json:"Id"`
+	Name                   string
+	Description            string
+	Version                string
+	Updateable             bool
+	ReleaseDate            string
+	SoftwareID             string `json:"SoftwareId"`
+	LowestSupportedVersion string
+	Manufacturer           string
+	RelatedItem            []ODataAnnotation
+	Status                 HealthStatus
+	Oem                    struct {
+		Dell struct {
+			DellSoftwareInventory struct {
+				ComponentID string
+				FQDD        string
+			}
+		}
+	}
+}
+
+// SoftwareInventory represents an instance of Redfish SoftwareInventory
+// resource, i.e. a firmware component of a server. The install state is
+// the prefix of the id Dell assigns to the component, i.e. Installed,
+// Previous, or Available.
+type SoftwareInventory struct {
+	ID                     string                 `yaml:"id" json:"id" xml:"id"`
+	OData                  *ODataAnnotation       `yaml:"odata" json:"odata" xml:"odata"`
+	Name                   string                 `yaml:"name" json:"name" xml:"name"`
+	Description            string                 `yaml:"description" json:"description" xml:"description"`
+	Version                string                 `yaml:"version" json:"version" xml:"version"`
+	Updateable             bool                   `yaml:"updateable" json:"updateable" xml:"updateable"`
+	ReleaseDate            string                 `yaml:"release_date" json:"release_date" xml:"release_date"`
+	ComponentID            string                 `yaml:"component_id" json:"component_id" xml:"component_id"`
+	FQDD                   string                 `yaml:"fqdd" json:"fqdd" xml:"fqdd"`
+	InstallState           string                 `yaml:"install_state" json:"install_state" xml:"install_state"`
+	LowestSupportedVersion string                 `yaml:"lowest_supported_version" json:"lowest_supported_version" xml:"lowest_supported_version"`
+	Manufacturer           string                 `yaml:"manufacturer" json:"manufacturer" xml:"manufacturer"`
+	RelatedItems           []*SoftwareRelatedItem `yaml:"related_items" json:"related_items" xml:"related_items"`
+	Status                 HealthStatus           `yaml:"status" json:"status" xml:"status"`
+}
+
+// SoftwareRelatedItem is a resource a firmware component applies to, e.g.
+// a network device function of a network adapter.
+type SoftwareRelatedItem struct {
+	FQDD string `yaml:"fqdd" json:"fqdd" xml:"fqdd"`
+	URI  string `yaml:"uri" json:"uri" xml:"uri"`
+}
+
+// getUpdateServiceURI returns the path of UpdateService resource linked
+// from the service root.
+func (cli *Client) getUpdateServiceURI() (string, error) {
+	resp, err := cli.callAPI("GET", "", cli.rootPath, []byte{})
+	if err != nil {
+		return "", err
+	}
+	response := &infoResponse{}
+	if err := json.Unmarshal(resp, response); err != nil {
+		return "", fmt.Errorf("parsing error: %s, server response: %s", err, string(resp[:]))
+	}
+	if response.UpdateService.ID == "" {
+		return "", fmt.Errorf("the service root has no UpdateService")
+	}
+	return response.UpdateService.ID, nil
+}
+
+// GetUpdateService returns an instance of Redfish UpdateService resource.
+func (cli *Client) GetUpdateService() (*UpdateService, error) {
+	s, err := cli.getUpdateServiceURI()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cli.callAPI("GET", "", s, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return newUpdateServiceFromBytes(resp)
+}
+
+// GetFirmwareInventory returns the firmware components of a server, i.e.
+// the installed, the previous, and the available firmware.
+func (cli *Client) GetFirmwareInventory() ([]*SoftwareInventory, error) {
+	svc, err := cli.GetUpdateService()
+	if err != nil {
+		return nil, err
+	}
+	if svc.FirmwareInventoryURI == "" {
+		return nil, fmt.Errorf("update service %s has no firmware inventory", svc.ID)
+	}
+	members, err := cli.getCollectionMembers(svc.FirmwareInventoryURI)
+	if err != nil {
+		return nil, err
+	}
+	inventory := []*SoftwareInventory{}
+	for _, member := range members {
+		resp, err := cli.callAPI("GET", "", member, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		item, err := newSoftwareInventoryFromBytes(resp)
+		if err != nil {
+			return nil, err
+		}
+		inventory = append(inventory, item)
+	}
+	return inventory, nil
+}
+
+// newUpdateServiceFromString returns UpdateService instance from an input string.
+func newUpdateServiceFromString(s string) (*UpdateService, error) {
+	return newUpdateServiceFromBytes([]byte(s))
+}
+
+// newUpdateServiceFromBytes returns UpdateService instance from an input byte array.
+func newUpdateServiceFromBytes(s []byte) (*UpdateService, error) {
+	response := &updateServiceResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the update service is empty, server response: %s", string(s[:]))
+	}
+	svc := &UpdateService{
+		ID:                   response.ID,
+		Name:                 response.Name,
+		Description:          response.Description,
+		ServiceEnabled:       response.ServiceEnabled,
+		HTTPPushURI:          response.HTTPPushURI,
+		MultipartHTTPPushURI: response.MultipartHTTPPushURI,
+		FirmwareInventoryURI: response.FirmwareInventory.ID,
+		SimpleUpdateURI:      response.Actions.SimpleUpdate.Target,
+		TransferProtocols:    nonNilStrings(response.Actions.SimpleUpdate.TransferProtocols),
+		ApplyTimes:           nonNilStrings(response.Actions.SimpleUpdate.ApplyTimeSupport.SupportedValues),
+		Status:               response.Status,
+	}
+	if response.MaxImageSizeBytes != nil {
+		svc.MaxImageSizeBytes = *response.MaxImageSizeBytes
+	}
+	svc.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return svc, nil
+}
+
+// newSoftwareInventoryFromString returns SoftwareInventory instance from an input string.
+func newSoftwareInventoryFromString(s string) (*SoftwareInventory, error) {
+	return newSoftwareInventoryFromBytes([]byte(s))
+}
+
+// newSoftwareInventoryFromBytes returns SoftwareInventory instance from an input byte array.
+func newSoftwareInventoryFromBytes(s []byte) (*SoftwareInventory, error) {
+	response := &softwareInventoryResponse{}
+	err := json.Unmarshal(s, response)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %s, server response: %s", err, string(s[:]))
+	}
+	if response.ID == "" {
+		return nil, fmt.Errorf("parsing error: the Id of the software inventory is empty, server response: %s", string(s[:]))
+	}
+	item := &SoftwareInventory{
+		ID:                     response.ID,
+		Name:                   response.Name,
+		Description:            response.Description,
+		Version:                response.Version,
+		Updateable:             response.Updateable,
+		ComponentID:            response.SoftwareID,
+		FQDD:                   response.Oem.Dell.DellSoftwareInventory.FQDD,
+		LowestSupportedVersion: response.LowestSupportedVersion,
+		Manufacturer:           response.Manufacturer,
+		RelatedItems:           []*SoftwareRelatedItem{},
+		Status:                 response.Status,
+	}
+	if item.ComponentID == "" {
+		item.ComponentID = response.Oem.Dell.DellSoftwareInventory.ComponentID
+	}
+	// iDRAC reports unknown release dates as time without date, e.g.
+	// 00:00:00Z.
+	if _, err := parseRedfishTime(response.ReleaseDate); err == nil {
+		item.ReleaseDate = response.ReleaseDate
+	}
+	if i := strings.Index(response.ID, "-"); i > 0 {
+		item.InstallState = response.ID[:i]
+	}
+	for _, related := range response.RelatedItem {
+		if related.ID == "" {
+			continue
+		}
+		// The related resources are named after their FQDD, e.g.
+		// NIC.Integrated.1-1-1, except for singletons, e.g. Bios.
+		fqdd := path.Base(related.ID)
+		if !strings.Contains(fqdd, ".") {
+			fqdd = item.FQDD
+		}
+		item.RelatedItems = append(item.RelatedItems, &SoftwareRelatedItem{
+			FQDD: fqdd,
+			URI:  related.ID,
+		})
+	}
+	item.OData = &ODataAnnotation{
+		Context: response.Context,
+		ID:      response.ODataAnnotation.ID,
+		Type:    response.Type,
+	}
+	return item, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package client
+
+import (
+	"fmt"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseSoftwareInventoryJsonOutput(t *testing.T) {
+	testFailed := 0
+	dataDir := "../../assets/responses"
+	for i, test := range []struct {
+		input      string
+		exp        *SoftwareInventory
+		shouldFail bool // Whether test should result in a failure
+		shouldErr  bool // Whether parsing of a response should result in error
+	}{
+		{
+			input: "software_inventory_3",
+			exp: &SoftwareInventory{
+				ID: "Installed-101548-20.5.13",
+				OData: NewODataAnnotation(
+					"/redfish/v1/UpdateService/FirmwareInventory/Installed-101548-20.5.13",
+					"#SoftwareInventory.v1_2_0.SoftwareInventory",
+					"/redfish/v1/$metadata#SoftwareInventory.SoftwareInventory",
+				),
+				Name:         "Intel(R) Ethernet 10G X710 rNDC",
+				Description:  "Represents Firmware Inventory",
+				Version:      "20.5.13",
+				Updateable:   true,
+				ReleaseDate:  "2020-05-12T00:00:00Z",
+				ComponentID:  "101548",
+				FQDD:         "NIC.Integrated.1-1-1",
+				InstallState: "Installed",
+				RelatedItems: []*SoftwareRelatedItem{
+					{
+						FQDD: "NIC.Integrated.1-1-1",
+						URI:  "/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Integrated.1/NetworkDeviceFunctions/NIC.Integrated.1-1-1",
+					},
+					{
+						FQDD: "NIC.Integrated.1-2-1",
+						URI:  "/redfish/v1/Chassis/System.Embedded.1/NetworkAdapters/NIC.Integrated.1/NetworkDeviceFunctions/NIC.Integrated.1-2-1",
+					},
+				},
+				Status: HealthStatus{
+					Health: "OK",
+					State:  "Enabled",
+				},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			// The release date is unknown, and the component is not
+			// updateable.
+			input: "software_inventory_5",
+			exp: &SoftwareInventory{
+				ID: "Installed-18765-20.08.10",
+				OData: NewODataAnnotation(
+					"/redfish/v1/UpdateService/FirmwareInventory/Installed-18765-20.08.10",
+					"#SoftwareInventory.v1_2_0.SoftwareInventory",
+					"/redfish/v1/$metadata#SoftwareInventory.SoftwareInventory",
+				),
+				Name:         "Dell OS Driver Pack, 20.08.10, A00",
+				Description:  "Represents Firmware Inventory",
+				Version:      "20.08.10",
+				ComponentID:  "18765",
+				FQDD:         "DriverPack.Embedded.1:LC.Embedded.1",
+				InstallState: "Installed",
+				RelatedItems: []*SoftwareRelatedItem{},
+				Status: HealthStatus{
+					Health: "OK",
+					State:  "Enabled",
+				},
+			},
+			shouldFail: false,
+			shouldErr:  false,
+		},
+		{
+			input:      "root_2",
+			shouldFail: false,
+			shouldErr:  true,
+		},
+	} {
+		// Read response file
+		fp := fmt.Sprintf("%s/%s.json", dataDir, test.input)
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			t.Logf("FAIL: Test %d: failed reading '%s', error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+
+		// Parse API response
+		item, err := newSoftwareInventoryFromBytes(content)
+		if err != nil {
+			if !test.shouldErr {
+				t.Logf("FAIL: Test %d: input '%s', expected to pass, but threw error: %v", i, fp, err)
+				testFailed++
+			} else {
+				t.Logf("PASS: Test %d: input '%s', expected to throw error, threw error: %v", i, fp, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Logf("FAIL: Test %d: input '%s', expected to throw error, but passed: %v", i, fp, *item)
+			testFailed++
+			continue
+		}
+
+		itemFromString, err := newSoftwareInventoryFromString(string(content))
+		if err != nil {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got error: %v", i, fp, err)
+			testFailed++
+			continue
+		}
+		if !reflect.DeepEqual(itemFromString, item) {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (newSoftwareInventoryFromString) vs. '%v' (newSoftwareInventoryFromBytes)",
+				i, fp, *itemFromString, *item)
+			testFailed++
+			continue
+		}
+
+		if !reflect.DeepEqual(item, test.exp) && !test.shouldFail {
+			t.Logf("FAIL: Test %d: input '%s', expected to pass, but got value mismatch: '%v' (actual) vs. '%v' (expected)",
+				i, fp, *item, *test.exp)
+			testFailed++
+			continue
+		}
+
+		for _, resource := range []interface{}{item, &SoftwareRelatedItem{}, &UpdateService{}} {
+			complianceMessages, compliant := isStructCompliant(resource)
+			if !compliant {
+				testFailed++
+				for _, entry := range complianceMessages {
+					t.Logf("%s", entry)
+				}
+			}
+		}
+
+		t.Logf("PASS: Test %d: input '%s', expected to pass, passed", i, fp)
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestGetFirmwareInventory(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	svc, err := cli.GetUpdateService()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if svc.FirmwareInventoryURI != "/redfish/v1/UpdateService/FirmwareInventory" {
+		t.Fatalf("client: unexpected firmware inventory uri: %s", svc.FirmwareInventoryURI)
+	}
+	if svc.MultipartHTTPPushURI != "/redfish/v1/UpdateService/MultipartUpload" {
+		t.Fatalf("client: unexpected multipart push uri: %s", svc.MultipartHTTPPushURI)
+	}
+	if svc.SimpleUpdateURI != "/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate" {
+		t.Fatalf("client: unexpected simple update uri: %s", svc.SimpleUpdateURI)
+	}
+	if !reflect.DeepEqual(svc.ApplyTimes, []string{"Immediate", "OnReset"}) {
+		t.Fatalf("client: unexpected apply times: %v", svc.ApplyTimes)
+	}
+
+	inventory, err := cli.GetFirmwareInventory()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+	if len(inventory) != 6 {
+		t.Fatalf("client: expected 6 firmware components, but got %d", len(inventory))
+	}
+	versions := make(map[string]string)
+	for _, item := range inventory {
+		versions[item.InstallState+" "+item.ComponentID] = item.Version
+	}
+	for k, v := range map[string]string{
+		"Installed 25227":  "4.22.00.00",
+		"Installed 159":    "2.8.2",
+		"Previous 159":     "2.7.7",
+		"Installed 104356": "50.9.4-3025",
+	} {
+		if versions[k] != v {
+			t.Fatalf("client: expected %s version %s, but got %q", k, v, versions[k])
+		}
+	}
+	bios := inventory[1]
+	if len(bios.RelatedItems) != 1 || bios.RelatedItems[0].FQDD != "BIOS.Setup.1-1" {
+		t.Fatalf("client: unexpected related items of BIOS: %+v", bios.RelatedItems)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package drift
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The resources of the differences.
+const (
+	ResourceIDRAC = "idrac"
+	ResourceNIC   = "nic"
+	ResourceBIOS  = "bios"
+	ResourceBoot  = "boot"
+)
+
+// The states of the keys of the differences.
+const (
+	StatusChanged = "changed"
+	StatusAdded   = "added"
+	StatusRemoved = "removed"
+)
+
+// DefaultDenylist are the keys changing without a change of the
+// configuration, e.g. the current IP address of iDRAC obtained via DHCP.
+var DefaultDenylist = Denylist{
+	"idrac.CurrentIPv4.1.*",
+	"idrac.CurrentIPv6.1.*",
+	"idrac.CurrentNIC.1.*",
+	"idrac.Info.1.*",
+	"idrac.ServerOS.1.ServerPoweredOnTime",
+	"idrac.SysInfo.1.POSTCode",
+	"nic.LinkStatus",
+}
+
+// Difference is a key whose live value differs from the value of the
+// baseline. The added keys are absent from the baseline, and the removed
+// keys are absent from the live configuration.
+type Difference struct {
+	Resource string `yaml:"resource" json:"resource" xml:"resource"`
+	Target   string `yaml:"target" json:"target" xml:"target"`
+	Name     string `yaml:"name" json:"name" xml:"name"`
+	Baseline string `yaml:"baseline" json:"baseline" xml:"baseline"`
+	Current  string `yaml:"current" json:"current" xml:"current"`
+	Status   string `yaml:"status" json:"status" xml:"status"`
+}
+
+// Key returns the key of the difference matched by a denylist, i.e. the
+// resource and the name, e.g. idrac.Time.1.Timezone or nic.VLanId.
+func (d *Difference) Key() string {
+	return d.Resource + "." + d.Name
+}
+
+// Denylist are the glob patterns of the keys ignored by Compare, e.g.
+// idrac.CurrentIPv4.1.* or nic.LinkStatus.
+type Denylist []string
+
+// NewDenylist returns Denylist instance from the patterns.
+func NewDenylist(patterns []string) (Denylist, error) {
+	denylist := Denylist{}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("denylist pattern %s is invalid: %s", pattern, err)
+		}
+		denylist = append(denylist, pattern)
+	}
+	return denylist, nil
+}
+
+// LoadDenylist returns Denylist instance from a file with a pattern per
+// line. The empty lines and the lines starting with # are ignored.
+func LoadDenylist(fp string) (Denylist, error) {
+	s, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	patterns := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(s))
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	denylist, err := NewDenylist(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", fp, err)
+	}
+	return denylist, nil
+}
+
+// Match returns true when a pattern of the denylist matches the key.
+func (d Denylist) Match(key string) bool {
+	for _, pattern := range d {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare returns the differences between the baseline and the live
+// configuration of a server, except for the keys matched by the denylist.
+// The differences are ordered by resource, target, and name.
+func Compare(baseline, current *Snapshot, denylist Denylist) []*Difference {
+	differences := []*Difference{}
+	add := func(resource, target string, old, new map[string]interface{}) {
+		for _, name := range keys(old, new) {
+			oldValue, inOld := old[name]
+			newValue, inNew := new[name]
+			if inOld && inNew && reflect.DeepEqual(oldValue, newValue) {
+				continue
+			}
+			difference := &Difference{
+				Resource: resource,
+				Target:   target,
+				Name:     name,
+				Baseline: client.FormatAttributeValue(oldValue),
+				Current:  client.FormatAttributeValue(newValue),
+				Status:   StatusChanged,
+			}
+			switch {
+			case !inOld:
+				difference.Status = StatusAdded
+			case !inNew:
+				difference.Status = StatusRemoved
+			}
+			if denylist.Match(difference.Key()) {
+				continue
+			}
+			differences = append(differences, difference)
+		}
+	}
+	add(ResourceIDRAC, "iDRAC.Embedded.1", baseline.IDRAC, current.IDRAC)
+	fqdds := []string{}
+	for fqdd := range baseline.NICs {
+		fqdds = append(fqdds, fqdd)
+	}
+	for fqdd := range current.NICs {
+		if _, exists := baseline.NICs[fqdd]; !exists {
+			fqdds = append(fqdds, fqdd)
+		}
+	}
+	sort.Strings(fqdds)
+	for _, fqdd := range fqdds {
+		add(ResourceNIC, fqdd, baseline.NICs[fqdd], current.NICs[fqdd])
+	}
+	add(ResourceBIOS, current.SystemID, baseline.BIOS, current.BIOS)
+	if strings.Join(baseline.BootOrder, ",") != strings.Join(current.BootOrder, ",") {
+		difference := &Difference{
+			Resource: ResourceBoot,
+			Target:   current.SystemID,
+			Name:     "order",
+			Baseline: strings.Join(baseline.BootOrder, ","),
+			Current:  strings.Join(current.BootOrder, ","),
+			Status:   StatusChanged,
+		}
+		if !denylist.Match(difference.Key()) {
+			differences = append(differences, difference)
+		}
+	}
+	return differences
+}
+
+// keys returns the sorted keys of either of the maps.
+func keys(a, b map[string]interface{}) []string {
+	names := []string{}
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, exists := a[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
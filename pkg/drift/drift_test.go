@@ -0,0 +1,134 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package drift
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := client.NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	snapshot, err := NewSnapshot(cli, "127.0.0.1", "")
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if snapshot.Version != SnapshotVersion || snapshot.SystemID != client.DefaultSystemID {
+		t.Fatalf("unexpected snapshot: %+v", *snapshot)
+	}
+	if snapshot.BIOS["LogicalProc"] != "Enabled" || snapshot.IDRAC["Time.1.Timezone"] != "UTC" {
+		t.Fatalf("unexpected snapshot attributes: %+v", *snapshot)
+	}
+	if !reflect.DeepEqual(snapshot.BootOrder, []string{"Boot0001", "Boot0002"}) {
+		t.Fatalf("unexpected snapshot boot order: %v", snapshot.BootOrder)
+	}
+	// The network device functions without attributes are skipped.
+	if len(snapshot.NICs) != 1 || snapshot.NICs["NIC.Integrated.1-1-1"]["LegacyBootProto"] != "NONE" {
+		t.Fatalf("unexpected snapshot network device functions: %v", snapshot.NICs)
+	}
+
+	s, err := snapshot.Bytes()
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	restored, err := NewSnapshotFromBytes(s)
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if differences := Compare(snapshot, restored, nil); len(differences) != 0 {
+		t.Fatalf("expected no differences after restore, but got %d", len(differences))
+	}
+	for i, s := range []string{
+		`{"version": 2, "bios": {}}`,
+		`{"bios": {}}`,
+		`[]`,
+	} {
+		if _, err := NewSnapshotFromBytes([]byte(s)); err == nil {
+			t.Fatalf("Test %d: expected failure, but got non-error response", i)
+		}
+	}
+
+	baseline, err := LoadSnapshot("../../assets/snapshots/baseline_1.json")
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	testFailed := 0
+	for i, test := range []struct {
+		denylist Denylist
+		exp      []*Difference
+	}{
+		{
+			denylist: DefaultDenylist,
+			exp: []*Difference{
+				{Resource: "idrac", Target: "iDRAC.Embedded.1", Name: "IPMILan.1.Enable", Baseline: "Enabled", Current: "Disabled", Status: "changed"},
+				{Resource: "nic", Target: "NIC.Integrated.1-1-1", Name: "VLanId", Baseline: "100", Current: "1", Status: "changed"},
+				{Resource: "nic", Target: "NIC.Slot.2-1-1", Name: "LegacyBootProto", Baseline: "NONE", Current: "", Status: "removed"},
+				{Resource: "bios", Target: "System.Embedded.1", Name: "LogicalProc", Baseline: "Disabled", Current: "Enabled", Status: "changed"},
+				{Resource: "bios", Target: "System.Embedded.1", Name: "MemTest", Baseline: "", Current: "Disabled", Status: "added"},
+				{Resource: "boot", Target: "System.Embedded.1", Name: "order", Baseline: "Boot0002,Boot0001", Current: "Boot0001,Boot0002", Status: "changed"},
+			},
+		},
+		{
+			denylist: Denylist{"idrac.*", "nic.*", "bios.LogicalProc", "boot.order"},
+			exp: []*Difference{
+				{Resource: "bios", Target: "System.Embedded.1", Name: "MemTest", Baseline: "", Current: "Disabled", Status: "added"},
+			},
+		},
+	} {
+		differences := Compare(baseline, snapshot, test.denylist)
+		if !reflect.DeepEqual(differences, test.exp) {
+			t.Logf("FAIL: Test %d: unexpected differences", i)
+			for _, difference := range differences {
+				t.Logf("FAIL: Test %d: %+v", i, *difference)
+			}
+			testFailed++
+		}
+	}
+	if differences := Compare(baseline, snapshot, nil); len(differences) != 8 {
+		t.Logf("FAIL: expected 8 differences without denylist, but got %d", len(differences))
+		testFailed++
+	}
+	if testFailed > 0 {
+		t.Fatalf("Failed %d tests", testFailed)
+	}
+}
+
+func TestDenylist(t *testing.T) {
+	denylist, err := NewDenylist([]string{"# volatile keys", "", " idrac.CurrentIPv4.1.* ", "nic.LinkStatus"})
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if !reflect.DeepEqual(denylist, Denylist{"idrac.CurrentIPv4.1.*", "nic.LinkStatus"}) {
+		t.Fatalf("unexpected denylist: %v", denylist)
+	}
+	for i, test := range []struct {
+		key string
+		exp bool
+	}{
+		{key: "idrac.CurrentIPv4.1.Address", exp: true},
+		{key: "idrac.IPv4.1.Address", exp: false},
+		{key: "nic.LinkStatus", exp: true},
+		{key: "bios.LinkStatus", exp: false},
+	} {
+		if matched := denylist.Match(test.key); matched != test.exp {
+			t.Fatalf("Test %d: key %s: expected match %t, but got %t", i, test.key, test.exp, matched)
+		}
+	}
+	if _, err := NewDenylist([]string{"idrac.[Current"}); err == nil {
+		t.Fatalf("expected failure due to invalid pattern, but got non-error response")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// SnapshotVersion is the version of the format of the snapshots. The
+// snapshots of other versions are rejected.
+const SnapshotVersion = 1
+
+// Snapshot is the configuration of a server at a point in time, i.e. the
+// BIOS attributes, the iDRAC attributes, the boot order, and the attributes
+// of the network device functions.
+type Snapshot struct {
+	Version   int                               `yaml:"version" json:"version"`
+	Host      string                            `yaml:"host" json:"host"`
+	SystemID  string                            `yaml:"system_id" json:"system_id"`
+	Timestamp time.Time                         `yaml:"timestamp" json:"timestamp"`
+	BIOS      map[string]interface{}            `yaml:"bios" json:"bios"`
+	IDRAC     map[string]interface{}            `yaml:"idrac" json:"idrac"`
+	BootOrder []string                          `yaml:"boot_order" json:"boot_order"`
+	NICs      map[string]map[string]interface{} `yaml:"nics" json:"nics"`
+}
+
+// NewSnapshot returns the current configuration of a server. The network
+// device functions without attributes, e.g. Fibre Channel ports, are
+// skipped.
+func NewSnapshot(cli *client.Client, host, systemID string) (*Snapshot, error) {
+	if systemID == "" {
+		systemID = client.DefaultSystemID
+	}
+	snapshot := &Snapshot{
+		Version:   SnapshotVersion,
+		Host:      host,
+		SystemID:  systemID,
+		Timestamp: time.Now().UTC(),
+		NICs:      make(map[string]map[string]interface{}),
+	}
+	var err error
+	if snapshot.BIOS, err = cli.GetBIOSAttributes(systemID); err != nil {
+		return nil, fmt.Errorf("failed reading BIOS attributes: %s", err)
+	}
+	if snapshot.IDRAC, err = cli.GetManagerAttributes(); err != nil {
+		return nil, fmt.Errorf("failed reading iDRAC attributes: %s", err)
+	}
+	if snapshot.BootOrder, err = cli.GetBootOrder(systemID); err != nil {
+		return nil, fmt.Errorf("failed reading boot order: %s", err)
+	}
+	functions, err := cli.GetNICFunctions(systemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading network device functions: %s", err)
+	}
+	for _, fqdd := range functions {
+		attributes, err := cli.GetNICAttributes(fqdd)
+		if err != nil {
+			if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed reading %s attributes: %s", fqdd, err)
+		}
+		snapshot.NICs[fqdd] = attributes
+	}
+	return snapshot, nil
+}
+
+// NewSnapshotFromBytes returns Snapshot instance from JSON document.
+func NewSnapshotFromBytes(s []byte) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(s, snapshot); err != nil {
+		return nil, fmt.Errorf("snapshot parsing error: %s", err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("snapshot version %d is unsupported, expecting %d", snapshot.Version, SnapshotVersion)
+	}
+	if snapshot.NICs == nil {
+		snapshot.NICs = make(map[string]map[string]interface{})
+	}
+	return snapshot, nil
+}
+
+// LoadSnapshot returns Snapshot instance from JSON file.
+func LoadSnapshot(fp string) (*Snapshot, error) {
+	s, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := NewSnapshotFromBytes(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", fp, err)
+	}
+	return snapshot, nil
+}
+
+// Bytes returns the snapshot as JSON document.
+func (s *Snapshot) Bytes() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package events
+
+import (
+	"encoding/json"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io"
+	"os"
+	"sync"
+)
+
+// Handler processes the events accepted by Receiver.
+type Handler interface {
+	HandleEvent(event *client.Event) error
+}
+
+// HandlerFunc is a callback processing the events.
+type HandlerFunc func(event *client.Event) error
+
+// HandleEvent calls the function.
+func (f HandlerFunc) HandleEvent(event *client.Event) error {
+	return f(event)
+}
+
+// JSONHandler writes the events to a writer, e.g. stdout, in JSON Lines
+// format, i.e. one event per line.
+type JSONHandler struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONHandler returns an instance of JSONHandler.
+func NewJSONHandler(w io.Writer) *JSONHandler {
+	return &JSONHandler{enc: json.NewEncoder(w)}
+}
+
+// HandleEvent writes the event.
+func (h *JSONHandler) HandleEvent(event *client.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.enc.Encode(event)
+}
+
+// FileHandler appends the events to a file in JSON Lines format.
+type FileHandler struct {
+	*JSONHandler
+	file *os.File
+}
+
+// NewFileHandler returns an instance of FileHandler. The file is created
+// with 0600 permissions when it does not exist.
+func NewFileHandler(fp string) (*FileHandler, error) {
+	f, err := os.OpenFile(fp, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileHandler{JSONHandler: NewJSONHandler(f), file: f}, nil
+}
+
+// Close closes the file.
+func (h *FileHandler) Close() error {
+	return h.file.Close()
+}
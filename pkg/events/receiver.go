@@ -0,0 +1,271 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+// Package events receives the events iDRAC pushes to Redfish event
+// subscriptions, and fans them out to handlers.
+package events
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultDedupeSize is the number of event ids the receiver remembers
+// per host for the removal of duplicate deliveries.
+const DefaultDedupeSize = 1024
+
+// DefaultQueueSize is the number of events the receiver queues for the
+// handlers.
+const DefaultQueueSize = 1024
+
+// maxEventSize is the maximum size of the body of an event delivery.
+const maxEventSize = 1 << 20
+
+// Receiver is an HTTPS listener accepting Redfish events. It decodes the
+// events, matches their origin hosts against host profiles, removes the
+// records delivered more than once, and passes the events to handlers.
+type Receiver struct {
+	// Address is the address to listen on, e.g. :8443.
+	Address string
+	// Path is the path accepting events. It defaults to any path.
+	Path string
+	// CertFile and KeyFile are the TLS certificate and key. The receiver
+	// uses a self-signed certificate when they are not set.
+	CertFile string
+	KeyFile  string
+	// DedupeSize is the number of event ids remembered per host.
+	DedupeSize int
+	// QueueSize is the number of events queued for the handlers. The
+	// receiver rejects the deliveries when the queue is full, i.e. iDRAC
+	// retries them.
+	QueueSize int
+
+	mu       sync.Mutex
+	handlers []Handler
+	profiles map[string]string
+	seen     map[string]*eventIDCache
+	queue    chan *client.Event
+	done     chan struct{}
+	closed   bool
+}
+
+// NewReceiver returns an instance of Receiver.
+func NewReceiver() *Receiver {
+	return &Receiver{
+		DedupeSize: DefaultDedupeSize,
+		QueueSize:  DefaultQueueSize,
+		profiles:   make(map[string]string),
+		seen:       make(map[string]*eventIDCache),
+	}
+}
+
+// AddHandler adds a handler of the events.
+func (r *Receiver) AddHandler(h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, h)
+}
+
+// AddProfile registers a host profile. The events originating from the
+// host, or from the addresses the host resolves to, carry the name of the
+// profile.
+func (r *Receiver) AddProfile(profile *client.HostProfile) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if profile.Host == "" {
+		return
+	}
+	r.profiles[profile.Host] = profile.Name
+	if net.ParseIP(profile.Host) != nil {
+		return
+	}
+	addrs, err := net.LookupHost(profile.Host)
+	if err != nil {
+		log.Debugf("failed resolving host %s of profile %s: %s", profile.Host, profile.Name, err)
+		return
+	}
+	for _, addr := range addrs {
+		if _, exists := r.profiles[addr]; !exists {
+			r.profiles[addr] = profile.Name
+		}
+	}
+}
+
+// ServeHTTP accepts an event delivery.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.Path != "" && req.URL.Path != r.Path {
+		http.NotFound(w, req)
+		return
+	}
+	if req.Method != "POST" {
+		http.Error(w, "Method Not Allowed, expecting POST", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxEventSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	event, err := client.NewEventFromBytes(body)
+	if err != nil {
+		log.Debugf("rejected event from %s: %s", req.RemoteAddr, err)
+		http.Error(w, "Bad Request, expecting Redfish event", http.StatusBadRequest)
+		return
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	event.Host = host
+	event.ReceivedAt = time.Now().UTC()
+	// The response must not wait for the handlers, because iDRAC retries
+	// the delivery after a timeout.
+	if !r.enqueue(event) {
+		http.Error(w, "Service Unavailable, the event queue is full", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// enqueue queues the event for the handlers. It returns false when the
+// queue is full, or the receiver is closed.
+func (r *Receiver) enqueue(event *client.Event) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return false
+	}
+	if r.queue == nil {
+		size := r.QueueSize
+		if size < 1 {
+			size = DefaultQueueSize
+		}
+		r.queue = make(chan *client.Event, size)
+		r.done = make(chan struct{})
+		go r.run(r.queue, r.done)
+	}
+	select {
+	case r.queue <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// run passes the queued events to the handlers until the queue closes.
+func (r *Receiver) run(queue <-chan *client.Event, done chan<- struct{}) {
+	defer close(done)
+	for event := range queue {
+		r.dispatch(event)
+	}
+}
+
+// Close stops accepting events, and waits for the handlers to process the
+// queued events.
+func (r *Receiver) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	queue, done := r.queue, r.done
+	r.mu.Unlock()
+	if queue != nil {
+		close(queue)
+		<-done
+	}
+	return nil
+}
+
+// dispatch removes duplicate records of the event and passes it to the
+// handlers.
+func (r *Receiver) dispatch(event *client.Event) {
+	r.mu.Lock()
+	event.Profile = r.profiles[event.Host]
+	cache, exists := r.seen[event.Host]
+	if !exists {
+		cache = newEventIDCache(r.DedupeSize)
+		r.seen[event.Host] = cache
+	}
+	records := []*client.EventRecord{}
+	for _, record := range event.Events {
+		if record.EventID != "" && !cache.add(record.EventID) {
+			log.Debugf("dropped duplicate event %s from %s", record.EventID, event.Host)
+			continue
+		}
+		records = append(records, record)
+	}
+	handlers := r.handlers
+	r.mu.Unlock()
+
+	if len(records) == 0 {
+		return
+	}
+	event.Events = records
+	for _, h := range handlers {
+		if err := h.HandleEvent(event); err != nil {
+			log.Errorf("failed handling event %s from %s: %s", event.ID, event.Host, err)
+		}
+	}
+}
+
+// ListenAndServe accepts events over HTTPS on the address of the receiver.
+func (r *Receiver) ListenAndServe() error {
+	srv := &http.Server{
+		Addr:    r.Address,
+		Handler: r,
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+	if r.CertFile != "" || r.KeyFile != "" {
+		if r.CertFile == "" || r.KeyFile == "" {
+			return fmt.Errorf("both TLS certificate and key are required")
+		}
+		return srv.ListenAndServeTLS(r.CertFile, r.KeyFile)
+	}
+	cert, err := newSelfSignedCertificate()
+	if err != nil {
+		return err
+	}
+	srv.TLSConfig.Certificates = []tls.Certificate{*cert}
+	return srv.ListenAndServeTLS("", "")
+}
+
+// eventIDCache remembers the most recent event ids of a host.
+type eventIDCache struct {
+	size  int
+	ids   map[string]bool
+	order []string
+}
+
+func newEventIDCache(size int) *eventIDCache {
+	if size < 1 {
+		size = DefaultDedupeSize
+	}
+	return &eventIDCache{
+		size: size,
+		ids:  make(map[string]bool),
+	}
+}
+
+// add remembers the event id. It returns false when the id is known.
+func (c *eventIDCache) add(id string) bool {
+	if c.ids[id] {
+		return false
+	}
+	if len(c.order) >= c.size {
+		delete(c.ids, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.ids[id] = true
+	c.order = append(c.order, id)
+	return true
+}
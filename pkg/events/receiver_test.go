@@ -0,0 +1,170 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package events
+
+import (
+	"bytes"
+	"crypto/x509"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReceiver(t *testing.T) {
+	content, err := ioutil.ReadFile("../../assets/responses/event_1.json")
+	if err != nil {
+		t.Fatalf("failed reading event: %s", err)
+	}
+	outputFile := filepath.Join(t.TempDir(), "events.jsonl")
+
+	receiver := NewReceiver()
+	receiver.Path = "/events"
+	receiver.AddProfile(&client.HostProfile{Name: "r01", Host: "127.0.0.1"})
+	received := []*client.Event{}
+	receiver.AddHandler(HandlerFunc(func(event *client.Event) error {
+		received = append(received, event)
+		return nil
+	}))
+	fileHandler, err := NewFileHandler(outputFile)
+	if err != nil {
+		t.Fatalf("failed creating file handler: %s", err)
+	}
+	defer fileHandler.Close()
+	receiver.AddHandler(fileHandler)
+
+	server := httptest.NewTLSServer(receiver)
+	defer server.Close()
+	httpClient := server.Client()
+
+	for i, test := range []struct {
+		method     string
+		path       string
+		body       []byte
+		statusCode int
+	}{
+		{method: "POST", path: "/events", body: content, statusCode: http.StatusOK},
+		// iDRAC retries the delivery when it misses the response.
+		{method: "POST", path: "/events", body: content, statusCode: http.StatusOK},
+		{method: "POST", path: "/events", body: []byte(`{"Id": "1"}`), statusCode: http.StatusBadRequest},
+		{method: "POST", path: "/other", body: content, statusCode: http.StatusNotFound},
+		{method: "GET", path: "/events", statusCode: http.StatusMethodNotAllowed},
+	} {
+		req, _ := http.NewRequest(test.method, server.URL+test.path, bytes.NewReader(test.body))
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("Test %d: request failed: %s", i, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.statusCode {
+			t.Fatalf("Test %d: expected status code %d, but got %d", i, test.statusCode, resp.StatusCode)
+		}
+	}
+	// The handlers process the queued events before the receiver closes.
+	receiver.Close()
+
+	if len(received) != 1 {
+		t.Fatalf("expected 1 event, but got %d", len(received))
+	}
+	event := received[0]
+	if event.Host != "127.0.0.1" || event.Profile != "r01" {
+		t.Fatalf("unexpected origin of event: host %s, profile %s", event.Host, event.Profile)
+	}
+	if len(event.Events) != 2 || event.Events[0].MessageID != "TMP0118" {
+		t.Fatalf("unexpected event records: %+v", event.Events)
+	}
+	b, err := ioutil.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed reading output file: %s", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(b)), "\n"); len(lines) != 1 {
+		t.Fatalf("expected 1 event in output file, but got %d", len(lines))
+	}
+	if info, _ := os.Stat(outputFile); info.Mode().Perm() != 0600 {
+		t.Fatalf("expected output file permissions 0600, but got %#o", info.Mode().Perm())
+	}
+}
+
+func TestReceiverQueue(t *testing.T) {
+	content, err := ioutil.ReadFile("../../assets/responses/event_1.json")
+	if err != nil {
+		t.Fatalf("failed reading event: %s", err)
+	}
+	receiver := NewReceiver()
+	receiver.QueueSize = 1
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	receiver.AddHandler(HandlerFunc(func(event *client.Event) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}))
+	server := httptest.NewTLSServer(receiver)
+	defer server.Close()
+	httpClient := server.Client()
+
+	post := func() int {
+		resp, err := httpClient.Post(server.URL, "application/json", bytes.NewReader(content))
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	// The response does not wait for the blocked handler.
+	if code := post(); code != http.StatusOK {
+		t.Fatalf("expected status code %d, but got %d", http.StatusOK, code)
+	}
+	<-started
+	if code := post(); code != http.StatusOK {
+		t.Fatalf("expected queued delivery with status code %d, but got %d", http.StatusOK, code)
+	}
+	// The queue is full, i.e. iDRAC retries the delivery.
+	if code := post(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status code %d, but got %d", http.StatusServiceUnavailable, code)
+	}
+	close(release)
+	receiver.Close()
+	// The closed receiver rejects the deliveries.
+	if code := post(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status code %d, but got %d", http.StatusServiceUnavailable, code)
+	}
+}
+
+func TestEventIDCache(t *testing.T) {
+	cache := newEventIDCache(2)
+	for i, test := range []struct {
+		id  string
+		exp bool
+	}{
+		{id: "1", exp: true},
+		{id: "1", exp: false},
+		{id: "2", exp: true},
+		{id: "3", exp: true},
+		// The oldest id is forgotten.
+		{id: "1", exp: true},
+		{id: "3", exp: false},
+	} {
+		if added := cache.add(test.id); added != test.exp {
+			t.Fatalf("Test %d: id %s, expected %t, but got %t", i, test.id, test.exp, added)
+		}
+	}
+}
+
+func TestSelfSignedCertificate(t *testing.T) {
+	cert, err := newSelfSignedCertificate()
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("expected valid certificate, but got error: %s", err)
+	}
+	if leaf.ExtKeyUsage[0] != x509.ExtKeyUsageServerAuth {
+		t.Fatalf("expected server certificate, but got %v", leaf.ExtKeyUsage)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package firmware
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// Catalog is a set of firmware packages, i.e. either Dell Update Catalog,
+// e.g. Catalog.xml, or a baseline.
+type Catalog struct {
+	Name         string              `yaml:"name" json:"name" xml:"name"`
+	Version      string              `yaml:"version" json:"version" xml:"version"`
+	BaseLocation string              `yaml:"base_location" json:"base_location" xml:"base_location"`
+	Components   []*CatalogComponent `yaml:"components" json:"components" xml:"components"`
+}
+
+// CatalogComponent is a firmware package of a catalog. The package applies
+// to the devices with the component ids, and to the server models. The
+// package applies to any model when the models are empty.
+type CatalogComponent struct {
+	ComponentIDs []string `yaml:"component_ids" json:"component_ids" xml:"component_ids"`
+	Name         string   `yaml:"name" json:"name" xml:"name"`
+	Version      string   `yaml:"version" json:"version" xml:"version"`
+	Path         string   `yaml:"path" json:"path" xml:"path"`
+	ReleaseDate  string   `yaml:"release_date" json:"release_date" xml:"release_date"`
+	Models       []string `yaml:"models" json:"models" xml:"models"`
+}
+
+// appliesTo returns true when the package applies to a device of a model.
+func (c *CatalogComponent) appliesTo(componentID, model string) bool {
+	matched := false
+	for _, id := range c.ComponentIDs {
+		if id == componentID {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+	if len(c.Models) == 0 {
+		return true
+	}
+	model = normalizeModel(model)
+	for _, m := range c.Models {
+		m = normalizeModel(m)
+		// The catalog models may omit the brand, e.g. R640 matches
+		// PowerEdge R640.
+		if m == model || strings.HasSuffix(model, " "+m) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeModel(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+type catalogManifest struct {
+	BaseLocation string `xml:"baseLocation,attr"`
+	Version      string `xml:"version,attr"`
+	Components   []struct {
+		Path          string `xml:"path,attr"`
+		VendorVersion string `xml:"vendorVersion,attr"`
+		ReleaseDate   string `xml:"releaseDate,attr"`
+		Name          string `xml:"Name>Display"`
+		Devices       []struct {
+			ComponentID string `xml:"componentID,attr"`
+		} `xml:"SupportedDevices>Device"`
+		Brands []struct {
+			Name   string `xml:"Display"`
+			Models []struct {
+				Name string `xml:"Display"`
+			} `xml:"Model"`
+		} `xml:"SupportedSystems>Brand"`
+	} `xml:"SoftwareComponent"`
+}
+
+// NewCatalogFromXML returns Catalog instance from Dell Update Catalog, e.g.
+// Catalog.xml. The catalogs published by Dell are UTF-16 encoded.
+func NewCatalogFromXML(s []byte) (*Catalog, error) {
+	s = decodeUTF16(s)
+	manifest := &catalogManifest{}
+	decoder := xml.NewDecoder(bytes.NewReader(s))
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		// The input is UTF-8 encoded already.
+		return input, nil
+	}
+	if err := decoder.Decode(manifest); err != nil {
+		return nil, fmt.Errorf("catalog parsing error: %s", err)
+	}
+	catalog := &Catalog{
+		Name:         "Catalog",
+		Version:      manifest.Version,
+		BaseLocation: manifest.BaseLocation,
+		Components:   []*CatalogComponent{},
+	}
+	for _, entry := range manifest.Components {
+		component := &CatalogComponent{
+			ComponentIDs: []string{},
+			Name:         strings.TrimSpace(entry.Name),
+			Version:      entry.VendorVersion,
+			Path:         entry.Path,
+			ReleaseDate:  entry.ReleaseDate,
+			Models:       []string{},
+		}
+		for _, device := range entry.Devices {
+			if device.ComponentID != "" {
+				component.ComponentIDs = append(component.ComponentIDs, device.ComponentID)
+			}
+		}
+		for _, brand := range entry.Brands {
+			for _, model := range brand.Models {
+				component.Models = append(component.Models, strings.TrimSpace(strings.TrimSpace(brand.Name)+" "+strings.TrimSpace(model.Name)))
+			}
+		}
+		if len(component.ComponentIDs) == 0 || component.Version == "" {
+			continue
+		}
+		catalog.Components = append(catalog.Components, component)
+	}
+	return catalog, nil
+}
+
+// decodeUTF16 converts UTF-16 encoded input with byte order mark to UTF-8.
+// Any other input is returned as is.
+func decodeUTF16(s []byte) []byte {
+	if len(s) < 2 {
+		return s
+	}
+	var little bool
+	switch {
+	case s[0] == 0xFF && s[1] == 0xFE:
+		little = true
+	case s[0] == 0xFE && s[1] == 0xFF:
+	default:
+		return s
+	}
+	s = s[2:]
+	u := make([]uint16, len(s)/2)
+	for i := range u {
+		if little {
+			u[i] = uint16(s[2*i]) | uint16(s[2*i+1])<<8
+		} else {
+			u[i] = uint16(s[2*i])<<8 | uint16(s[2*i+1])
+		}
+	}
+	return []byte(string(utf16.Decode(u)))
+}
+
+type baselineDocument struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Components []struct {
+		ComponentID  string   `yaml:"component_id"`
+		ComponentIDs []string `yaml:"component_ids"`
+		Name         string   `yaml:"name"`
+		Version      string   `yaml:"version"`
+		Path         string   `yaml:"path"`
+		ReleaseDate  string   `yaml:"release_date"`
+		Models       []string `yaml:"models"`
+	} `yaml:"components"`
+}
+
+// NewCatalogFromYAML returns Catalog instance from a baseline, i.e. YAML
+// document listing the component id, the version, and, optionally, the
+// package path and the models of each firmware package.
+func NewCatalogFromYAML(s []byte) (*Catalog, error) {
+	doc := &baselineDocument{}
+	if err := yaml.Unmarshal(s, doc); err != nil {
+		return nil, fmt.Errorf("baseline parsing error: %s", err)
+	}
+	catalog := &Catalog{
+		Name:       doc.Name,
+		Version:    doc.Version,
+		Components: []*CatalogComponent{},
+	}
+	for i, entry := range doc.Components {
+		component := &CatalogComponent{
+			ComponentIDs: []string{},
+			Name:         entry.Name,
+			Version:      entry.Version,
+			Path:         entry.Path,
+			ReleaseDate:  entry.ReleaseDate,
+			Models:       []string{},
+		}
+		if entry.ComponentID != "" {
+			component.ComponentIDs = append(component.ComponentIDs, entry.ComponentID)
+		}
+		component.ComponentIDs = append(component.ComponentIDs, entry.ComponentIDs...)
+		component.Models = append(component.Models, entry.Models...)
+		if len(component.ComponentIDs) == 0 {
+			return nil, fmt.Errorf("baseline component %d has no component id", i+1)
+		}
+		if component.Version == "" {
+			return nil, fmt.Errorf("baseline component %d has no version", i+1)
+		}
+		catalog.Components = append(catalog.Components, component)
+	}
+	return catalog, nil
+}
+
+// LoadCatalog returns Catalog instance from a file, i.e. either Dell Update
+// Catalog with .xml extension, or a baseline with .yaml or .yml extension.
+func LoadCatalog(fp string) (*Catalog, error) {
+	s, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(fp)) {
+	case ".xml":
+		return NewCatalogFromXML(s)
+	case ".yaml", ".yml":
+		return NewCatalogFromYAML(s)
+	}
+	return nil, fmt.Errorf("unsupported catalog file %s, expecting .xml, .yaml, or .yml extension", fp)
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package firmware
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	dataDir := "../../assets/catalogs"
+	catalog, err := LoadCatalog(dataDir + "/catalog_1.xml")
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if catalog.Version != "20.11.00" || catalog.BaseLocation != "downloads.dell.com" {
+		t.Fatalf("unexpected catalog: %+v", *catalog)
+	}
+	if len(catalog.Components) != 6 {
+		t.Fatalf("expected 6 components, but got %d", len(catalog.Components))
+	}
+	expComponent := &CatalogComponent{
+		ComponentIDs: []string{"101548", "101549"},
+		Name:         "Intel NIC Family Version 20.5.13 Firmware for X710, XL710, XXV710, and X710-T",
+		Version:      "20.5.13",
+		Path:         "FOLDER06402712M/1/Network_Firmware_4WMT2_WN64_20.5.13_A00.EXE",
+		ReleaseDate:  "May 12, 2020",
+		Models:       []string{},
+	}
+	if !reflect.DeepEqual(catalog.Components[4], expComponent) {
+		t.Fatalf("unexpected component: %+v", *catalog.Components[4])
+	}
+	if !reflect.DeepEqual(catalog.Components[0].Models, []string{"PowerEdge R640", "PowerEdge R740"}) {
+		t.Fatalf("unexpected models: %v", catalog.Components[0].Models)
+	}
+
+	// The catalogs published by Dell are UTF-16 encoded.
+	content, err := ioutil.ReadFile(dataDir + "/catalog_1.xml")
+	if err != nil {
+		t.Fatalf("failed reading catalog: %s", err)
+	}
+	encoded := []byte{0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(string(content))) {
+		encoded = append(encoded, byte(u), byte(u>>8))
+	}
+	utf16Catalog, err := NewCatalogFromXML(encoded)
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if !reflect.DeepEqual(utf16Catalog, catalog) {
+		t.Fatalf("UTF-16 catalog mismatch: %+v vs. %+v", *utf16Catalog, *catalog)
+	}
+
+	baseline, err := LoadCatalog(dataDir + "/baseline_1.yaml")
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if baseline.Name != "2020.11 patch cycle" || len(baseline.Components) != 4 {
+		t.Fatalf("unexpected baseline: %+v", *baseline)
+	}
+	if !reflect.DeepEqual(baseline.Components[1].ComponentIDs, []string{"159"}) {
+		t.Fatalf("unexpected component ids: %v", baseline.Components[1].ComponentIDs)
+	}
+
+	for i, test := range []struct {
+		input string
+	}{
+		{input: "components:\n  - version: 1.0.0\n"},
+		{input: "components:\n  - component_id: \"159\"\n"},
+		{input: "components: foo\n"},
+	} {
+		if _, err := NewCatalogFromYAML([]byte(test.input)); err == nil {
+			t.Fatalf("Test %d: expected failure, but got non-error response", i)
+		}
+	}
+	if _, err := LoadCatalog(dataDir + "/../responses/root_1.json"); err == nil {
+		t.Fatalf("expected failure due to unsupported extension, but got non-error response")
+	}
+}
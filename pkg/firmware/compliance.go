@@ -0,0 +1,173 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package firmware
+
+import (
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The compliance states of firmware components.
+const (
+	StatusCurrent  = "current"
+	StatusOutdated = "outdated"
+	StatusUnknown  = "unknown"
+)
+
+// ComponentCompliance is the compliance of an installed firmware component
+// with a catalog. The target is the latest version of the catalog applying
+// to the component. The status is unknown when no catalog package applies
+// to the component.
+type ComponentCompliance struct {
+	ComponentID      string `yaml:"component_id" json:"component_id" xml:"component_id"`
+	Name             string `yaml:"name" json:"name" xml:"name"`
+	FQDD             string `yaml:"fqdd" json:"fqdd" xml:"fqdd"`
+	InstalledVersion string `yaml:"installed_version" json:"installed_version" xml:"installed_version"`
+	TargetVersion    string `yaml:"target_version" json:"target_version" xml:"target_version"`
+	Status           string `yaml:"status" json:"status" xml:"status"`
+	Path             string `yaml:"path" json:"path" xml:"path"`
+}
+
+// ComplianceSummary is the number of firmware components in each
+// compliance state.
+type ComplianceSummary struct {
+	Current  int `yaml:"current" json:"current" xml:"current"`
+	Outdated int `yaml:"outdated" json:"outdated" xml:"outdated"`
+	Unknown  int `yaml:"unknown" json:"unknown" xml:"unknown"`
+}
+
+// ComplianceReport is the compliance of the installed firmware of a server
+// with a catalog.
+type ComplianceReport struct {
+	Model      string                 `yaml:"model" json:"model" xml:"model"`
+	Summary    *ComplianceSummary     `yaml:"summary" json:"summary" xml:"summary"`
+	Components []*ComponentCompliance `yaml:"components" json:"components" xml:"components"`
+}
+
+// IsCompliant returns true when no firmware component is outdated.
+func (r *ComplianceReport) IsCompliant() bool {
+	return r.Summary.Outdated == 0
+}
+
+// CheckCompliance compares the installed firmware components of a server
+// model with a catalog.
+func CheckCompliance(catalog *Catalog, model string, inventory []*client.SoftwareInventory) *ComplianceReport {
+	report := &ComplianceReport{
+		Model:      model,
+		Summary:    &ComplianceSummary{},
+		Components: []*ComponentCompliance{},
+	}
+	for _, item := range inventory {
+		if item.InstallState != "Installed" {
+			continue
+		}
+		entry := &ComponentCompliance{
+			ComponentID:      item.ComponentID,
+			Name:             item.Name,
+			FQDD:             item.FQDD,
+			InstalledVersion: item.Version,
+			Status:           StatusUnknown,
+		}
+		var target *CatalogComponent
+		for _, component := range catalog.Components {
+			if !component.appliesTo(item.ComponentID, model) {
+				continue
+			}
+			if target == nil || CompareVersions(component.Version, target.Version) > 0 {
+				target = component
+			}
+		}
+		if target != nil {
+			entry.TargetVersion = target.Version
+			entry.Path = target.Path
+			entry.Status = StatusCurrent
+			if CompareVersions(item.Version, target.Version) < 0 {
+				entry.Status = StatusOutdated
+			}
+		}
+		switch entry.Status {
+		case StatusCurrent:
+			report.Summary.Current++
+		case StatusOutdated:
+			report.Summary.Outdated++
+		default:
+			report.Summary.Unknown++
+		}
+		report.Components = append(report.Components, entry)
+	}
+	return report
+}
+
+// CompareVersions compares firmware versions, e.g. 50.9.4-3025 and
+// 51.13.0-3485, and returns -1, 0, or 1. The versions are split into the
+// runs of digits and of letters, and the digits are compared numerically.
+func CompareVersions(a, b string) int {
+	x, y := splitVersion(a), splitVersion(b)
+	for i := 0; i < len(x) || i < len(y); i++ {
+		if i >= len(x) {
+			return -1
+		}
+		if i >= len(y) {
+			return 1
+		}
+		if c := compareVersionParts(x[i], y[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareVersionParts(a, b string) int {
+	i, errA := strconv.ParseUint(a, 10, 64)
+	j, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		switch {
+		case i < j:
+			return -1
+		case i > j:
+			return 1
+		}
+		return 0
+	case errA == nil:
+		// Numbers are newer than letters, e.g. 1.0 is newer than 1.rc.
+		return 1
+	case errB == nil:
+		return -1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func splitVersion(s string) []string {
+	parts := []string{}
+	var b strings.Builder
+	var digits bool
+	flush := func() {
+		if b.Len() > 0 {
+			parts = append(parts, b.String())
+			b.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case unicode.IsDigit(r):
+			if !digits {
+				flush()
+			}
+			digits = true
+			b.WriteRune(r)
+		case unicode.IsLetter(r):
+			if digits {
+				flush()
+			}
+			digits = false
+			b.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return parts
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package firmware
+
+import (
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	for i, test := range []struct {
+		a   string
+		b   string
+		exp int
+	}{
+		{a: "2.8.2", b: "2.9.4", exp: -1},
+		{a: "2.10.0", b: "2.9.4", exp: 1},
+		{a: "4.22.00.00", b: "4.22.0.0", exp: 0},
+		{a: "50.9.4-3025", b: "51.13.0-3485", exp: -1},
+		{a: "51.13.0-3485", b: "51.13.0", exp: 1},
+		{a: "1.0.rc1", b: "1.0.1", exp: -1},
+		{a: "A08", b: "A07", exp: 1},
+	} {
+		if got := CompareVersions(test.a, test.b); got != test.exp {
+			t.Fatalf("Test %d: expected %s vs. %s to be %d, but got %d", i, test.a, test.b, test.exp, got)
+		}
+	}
+}
+
+func TestCheckCompliance(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := client.NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+	inventory, err := cli.GetFirmwareInventory()
+	if err != nil {
+		t.Fatalf("client: expected success, but got error: %s", err)
+	}
+
+	for _, fp := range []string{"catalog_1.xml", "baseline_1.yaml"} {
+		catalog, err := LoadCatalog("../../assets/catalogs/" + fp)
+		if err != nil {
+			t.Fatalf("%s: expected success, but got error: %s", fp, err)
+		}
+		report := CheckCompliance(catalog, "PowerEdge R640", inventory)
+		// The previous BIOS is not installed.
+		if len(report.Components) != 5 {
+			t.Fatalf("%s: expected 5 components, but got %d", fp, len(report.Components))
+		}
+		statuses := make(map[string]string)
+		for _, entry := range report.Components {
+			statuses[entry.ComponentID] = entry.Status + " " + entry.TargetVersion
+		}
+		exp := map[string]string{
+			"25227":  "current 4.22.00.00",
+			"159":    "outdated 2.9.4",
+			"101548": "current 20.5.13",
+			"104356": "outdated 51.13.0-3485",
+			"18765":  "unknown ",
+		}
+		if fp == "baseline_1.yaml" {
+			exp["101548"] = "unknown "
+		}
+		for k, v := range exp {
+			if statuses[k] != v {
+				t.Fatalf("%s: expected component %s to be %q, but got %q", fp, k, v, statuses[k])
+			}
+		}
+		if report.IsCompliant() || report.Summary.Outdated != 2 {
+			t.Fatalf("%s: unexpected summary: %+v", fp, *report.Summary)
+		}
+		if report.Components[1].Path != "FOLDER06728321M/1/BIOS_R6XK3_WN64_2.9.4.EXE" {
+			t.Fatalf("%s: unexpected package path: %s", fp, report.Components[1].Path)
+		}
+
+		// The R740 BIOS does not apply to R640, and vice versa.
+		report = CheckCompliance(catalog, "PowerEdge R740", inventory)
+		if report.Components[1].TargetVersion != "2.10.0" {
+			t.Fatalf("%s: expected R740 BIOS 2.10.0, but got %q", fp, report.Components[1].TargetVersion)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package plan
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// Document is the desired state of a server. The sections absent from the
+// document, and the attributes absent from a section, remain unchanged.
+type Document struct {
+	SystemID string                        `yaml:"system_id"`
+	BIOS     *AttributeSettings            `yaml:"bios"`
+	IDRAC    *AttributeSettings            `yaml:"idrac"`
+	Boot     *BootSettings                 `yaml:"boot"`
+	NICs     map[string]*AttributeSettings `yaml:"nics"`
+	Accounts []*AccountSettings            `yaml:"accounts"`
+}
+
+// AttributeSettings are the desired attributes of a resource, e.g. BIOS,
+// and the apply time of the changes, i.e. Immediate or OnReset. The changes
+// of BIOS and NIC attributes default to OnReset, while the changes of iDRAC
+// attributes always apply immediately.
+type AttributeSettings struct {
+	Attributes map[string]interface{} `yaml:"attributes"`
+	ApplyTime  string                 `yaml:"apply_time"`
+}
+
+// BootSettings is the desired boot order. The boot options in the order
+// come first, and the other boot options keep their current order.
+type BootSettings struct {
+	Order []string `yaml:"order"`
+}
+
+// AccountSettings is the desired state of a user account. The password is
+// used when the account does not exist, i.e. the password of an existing
+// account remains unchanged.
+type AccountSettings struct {
+	UserName string                   `yaml:"user_name"`
+	RoleID   string                   `yaml:"role_id"`
+	Enabled  *bool                    `yaml:"enabled"`
+	Password *client.CredentialSource `yaml:"password"`
+}
+
+// NewDocumentFromYAML returns Document instance from YAML document.
+func NewDocumentFromYAML(s []byte) (*Document, error) {
+	doc := &Document{}
+	dec := yaml.NewDecoder(bytes.NewReader(s))
+	dec.KnownFields(true)
+	if err := dec.Decode(doc); err != nil {
+		return nil, fmt.Errorf("desired state parsing error: %s", err)
+	}
+	if doc.SystemID == "" {
+		doc.SystemID = client.DefaultSystemID
+	}
+	if doc.BIOS != nil {
+		if err := doc.BIOS.validate("bios"); err != nil {
+			return nil, err
+		}
+	}
+	if doc.IDRAC != nil {
+		if err := doc.IDRAC.validate("idrac"); err != nil {
+			return nil, err
+		}
+		if doc.IDRAC.ApplyTime != "" {
+			return nil, fmt.Errorf("idrac: the attributes apply immediately, remove apply_time")
+		}
+	}
+	for fqdd, settings := range doc.NICs {
+		if settings == nil {
+			return nil, fmt.Errorf("nics: %s has no attributes", fqdd)
+		}
+		if err := settings.validate("nics: " + fqdd); err != nil {
+			return nil, err
+		}
+	}
+	if doc.Boot != nil && len(doc.Boot.Order) == 0 {
+		return nil, fmt.Errorf("boot: the order is empty")
+	}
+	userNames := make(map[string]bool)
+	for i, account := range doc.Accounts {
+		if account == nil || account.UserName == "" {
+			return nil, fmt.Errorf("accounts: account %d has no user_name", i+1)
+		}
+		if userNames[account.UserName] {
+			return nil, fmt.Errorf("accounts: account %s is duplicate", account.UserName)
+		}
+		userNames[account.UserName] = true
+	}
+	return doc, nil
+}
+
+// LoadDocument returns Document instance from YAML file.
+func LoadDocument(fp string) (*Document, error) {
+	s, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := NewDocumentFromYAML(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", fp, err)
+	}
+	return doc, nil
+}
+
+func (settings *AttributeSettings) validate(section string) error {
+	if len(settings.Attributes) == 0 {
+		return fmt.Errorf("%s: the attributes are empty", section)
+	}
+	// The service returns no values for the passwords, i.e. a desired
+	// password would never match. Without a registry, the attributes with
+	// names ending with Password are passwords.
+	var registry *client.AttributeRegistry
+	names := []string{}
+	for name := range settings.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if registry.IsPassword("", name) {
+			return fmt.Errorf("%s: attribute %s is a password, set the passwords of the accounts with the password credential source of accounts", section, name)
+		}
+	}
+	switch settings.ApplyTime {
+	case "", client.ApplyTimeImmediate, client.ApplyTimeOnReset:
+	default:
+		return fmt.Errorf("%s: unsupported apply_time %s, expecting %s", section, settings.ApplyTime,
+			strings.Join([]string{client.ApplyTimeImmediate, client.ApplyTimeOnReset}, " or "))
+	}
+	return nil
+}
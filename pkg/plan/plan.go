@@ -0,0 +1,458 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The resources of the changes.
+const (
+	ResourceIDRAC   = "idrac"
+	ResourceAccount = "account"
+	ResourceNIC     = "nic"
+	ResourceBIOS    = "bios"
+	ResourceBoot    = "boot"
+)
+
+// The states of the changes after apply.
+const (
+	StatusApplied   = "applied"
+	StatusScheduled = "scheduled"
+	StatusFailed    = "failed"
+)
+
+// Change is a difference between the current and the desired state of a
+// server. The pending changes are the changes already scheduled, i.e.
+// awaiting the next reboot of the server, and are not applied again.
+type Change struct {
+	Resource  string `yaml:"resource" json:"resource" xml:"resource"`
+	Target    string `yaml:"target" json:"target" xml:"target"`
+	Name      string `yaml:"name" json:"name" xml:"name"`
+	Current   string `yaml:"current" json:"current" xml:"current"`
+	Desired   string `yaml:"desired" json:"desired" xml:"desired"`
+	ApplyTime string `yaml:"apply_time" json:"apply_time" xml:"apply_time"`
+	Pending   bool   `yaml:"pending" json:"pending" xml:"pending"`
+}
+
+// Result is the outcome of applying the changes of a resource, e.g. the
+// BIOS attributes, along with the configuration job, if any.
+type Result struct {
+	Resource  string `yaml:"resource" json:"resource" xml:"resource"`
+	Target    string `yaml:"target" json:"target" xml:"target"`
+	ApplyTime string `yaml:"apply_time" json:"apply_time" xml:"apply_time"`
+	Changes   int    `yaml:"changes" json:"changes" xml:"changes"`
+	JobID     string `yaml:"job_id" json:"job_id" xml:"job_id"`
+	Status    string `yaml:"status" json:"status" xml:"status"`
+	Message   string `yaml:"message" json:"message" xml:"message"`
+}
+
+// Plan is the set of changes bringing a server to the desired state.
+type Plan struct {
+	Host    string    `yaml:"host" json:"host" xml:"host"`
+	Changes []*Change `yaml:"changes" json:"changes" xml:"changes"`
+	groups  []*group
+}
+
+// group is the changes of a resource applied together, e.g. by one
+// configuration job. The changes of the resources requiring a reboot are
+// applied by configuration jobs.
+type group struct {
+	resource  string
+	target    string
+	applyTime string
+	reboot    bool
+	changes   int
+	apply     func(applyTime string) (*client.TaskHandle, error)
+}
+
+// HasChanges returns true when the plan has changes to apply, i.e. the
+// changes which are not pending.
+func (p *Plan) HasChanges() bool {
+	for _, change := range p.Changes {
+		if !change.Pending {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPlan compares the current state of a server with the desired state,
+// and returns the changes.
+func NewPlan(cli *client.Client, host string, doc *Document) (*Plan, error) {
+	p := &Plan{
+		Host:    host,
+		Changes: []*Change{},
+		groups:  []*group{},
+	}
+	if doc.IDRAC != nil {
+		current, err := cli.GetManagerAttributes()
+		if err != nil {
+			return nil, err
+		}
+		attributes, err := p.diffAttributes(ResourceIDRAC, "iDRAC.Embedded.1", current, nil, doc.IDRAC.Attributes, client.ApplyTimeImmediate)
+		if err != nil {
+			return nil, err
+		}
+		if len(attributes) > 0 {
+			p.addGroup(ResourceIDRAC, "iDRAC.Embedded.1", client.ApplyTimeImmediate, false, len(attributes), func(string) (*client.TaskHandle, error) {
+				return nil, cli.SetManagerAttributes(attributes)
+			})
+		}
+	}
+	if len(doc.Accounts) > 0 {
+		if err := p.planAccounts(cli, host, doc.Accounts); err != nil {
+			return nil, err
+		}
+	}
+	fqdds := []string{}
+	for fqdd := range doc.NICs {
+		fqdds = append(fqdds, fqdd)
+	}
+	sort.Strings(fqdds)
+	for _, fqdd := range fqdds {
+		fqdd := fqdd
+		settings := doc.NICs[fqdd]
+		current, err := cli.GetNICAttributes(fqdd)
+		if err != nil {
+			return nil, err
+		}
+		pending, err := cli.GetPendingNICAttributes(fqdd)
+		if err != nil {
+			return nil, err
+		}
+		applyTime := defaultApplyTime(settings.ApplyTime)
+		attributes, err := p.diffAttributes(ResourceNIC, fqdd, current, pending, settings.Attributes, applyTime)
+		if err != nil {
+			return nil, err
+		}
+		if len(attributes) > 0 {
+			p.addGroup(ResourceNIC, fqdd, applyTime, true, len(attributes), func(applyTime string) (*client.TaskHandle, error) {
+				return cli.SetNICAttributes(fqdd, attributes, applyTime)
+			})
+		}
+	}
+	if doc.BIOS != nil {
+		current, err := cli.GetBIOSAttributes(doc.SystemID)
+		if err != nil {
+			return nil, err
+		}
+		pending, err := cli.GetPendingBIOSAttributes(doc.SystemID)
+		if err != nil {
+			return nil, err
+		}
+		applyTime := defaultApplyTime(doc.BIOS.ApplyTime)
+		attributes, err := p.diffAttributes(ResourceBIOS, doc.SystemID, current, pending, doc.BIOS.Attributes, applyTime)
+		if err != nil {
+			return nil, err
+		}
+		if len(attributes) > 0 {
+			p.addGroup(ResourceBIOS, doc.SystemID, applyTime, true, len(attributes), func(applyTime string) (*client.TaskHandle, error) {
+				return cli.SetBIOSAttributes(doc.SystemID, attributes, applyTime)
+			})
+		}
+	}
+	if doc.Boot != nil {
+		current, err := cli.GetBootOrder(doc.SystemID)
+		if err != nil {
+			return nil, err
+		}
+		pending, err := cli.GetPendingBootOrder(doc.SystemID)
+		if err != nil {
+			return nil, err
+		}
+		desired := bootOrder(current, doc.Boot.Order)
+		if strings.Join(current, ",") != strings.Join(desired, ",") {
+			change := &Change{
+				Resource:  ResourceBoot,
+				Target:    doc.SystemID,
+				Name:      "order",
+				Current:   strings.Join(current, ","),
+				Desired:   strings.Join(desired, ","),
+				ApplyTime: client.ApplyTimeOnReset,
+				Pending:   strings.Join(pending, ",") == strings.Join(desired, ","),
+			}
+			p.Changes = append(p.Changes, change)
+			if !change.Pending {
+				p.addGroup(ResourceBoot, doc.SystemID, client.ApplyTimeOnReset, false, 1, func(string) (*client.TaskHandle, error) {
+					return nil, cli.SetBootOrder(doc.SystemID, desired)
+				})
+			}
+		}
+	}
+	return p, nil
+}
+
+func (p *Plan) addGroup(resource, target, applyTime string, reboot bool, changes int, apply func(string) (*client.TaskHandle, error)) {
+	p.groups = append(p.groups, &group{
+		resource:  resource,
+		target:    target,
+		applyTime: applyTime,
+		reboot:    reboot,
+		changes:   changes,
+		apply:     apply,
+	})
+}
+
+// diffAttributes adds the changes of the attributes of a resource to the
+// plan, and returns the attributes to apply. The attributes whose desired
+// values are pending already are reported, but not applied.
+func (p *Plan) diffAttributes(resource, target string, current, pending, desired map[string]interface{}, applyTime string) (map[string]interface{}, error) {
+	names := []string{}
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	attributes := make(map[string]interface{})
+	for _, name := range names {
+		currentValue, exists := current[name]
+		if !exists {
+			return nil, fmt.Errorf("%s %s: attribute %s not found", resource, target, name)
+		}
+		if client.FormatAttributeValue(currentValue) == client.FormatAttributeValue(desired[name]) {
+			continue
+		}
+		change := &Change{
+			Resource:  resource,
+			Target:    target,
+			Name:      name,
+			Current:   client.FormatAttributeValue(currentValue),
+			Desired:   client.FormatAttributeValue(desired[name]),
+			ApplyTime: applyTime,
+		}
+		if pendingValue, exists := pending[name]; exists && client.FormatAttributeValue(pendingValue) == change.Desired {
+			change.Pending = true
+		} else {
+			attributes[name] = desired[name]
+		}
+		p.Changes = append(p.Changes, change)
+	}
+	return attributes, nil
+}
+
+// planAccounts adds the changes of the user accounts to the plan.
+func (p *Plan) planAccounts(cli *client.Client, host string, desired []*AccountSettings) error {
+	accounts, err := cli.ListAccounts()
+	if err != nil {
+		return err
+	}
+	for _, settings := range desired {
+		settings := settings
+		var account *client.Account
+		for _, entry := range accounts {
+			if entry.UserName == settings.UserName {
+				account = entry
+				break
+			}
+		}
+		add := func(name, current, desired string) {
+			p.Changes = append(p.Changes, &Change{
+				Resource:  ResourceAccount,
+				Target:    settings.UserName,
+				Name:      name,
+				Current:   current,
+				Desired:   desired,
+				ApplyTime: client.ApplyTimeImmediate,
+			})
+		}
+		if account == nil {
+			if settings.Password == nil || settings.RoleID == "" {
+				return fmt.Errorf("account %s does not exist, creating it requires role_id and password", settings.UserName)
+			}
+			provider, err := settings.Password.Provider()
+			if err != nil {
+				return fmt.Errorf("account %s password: %s", settings.UserName, err)
+			}
+			creds, err := provider.GetCredentials(host)
+			if err != nil {
+				return fmt.Errorf("account %s password: %s", settings.UserName, err)
+			}
+			add("user_name", "", settings.UserName)
+			add("role_id", "", settings.RoleID)
+			enabled := settings.Enabled == nil || *settings.Enabled
+			add("enabled", "", strconv.FormatBool(enabled))
+			p.addGroup(ResourceAccount, settings.UserName, client.ApplyTimeImmediate, false, 3, func(string) (*client.TaskHandle, error) {
+				created, err := cli.CreateAccount(settings.UserName, creds.Password, settings.RoleID)
+				if err != nil {
+					return nil, err
+				}
+				if !enabled {
+					return nil, cli.DisableAccount(created.ID)
+				}
+				return nil, nil
+			})
+			continue
+		}
+		changes := &client.AccountChanges{}
+		count := 0
+		if settings.RoleID != "" && settings.RoleID != account.RoleID {
+			add("role_id", account.RoleID, settings.RoleID)
+			changes.RoleID = &settings.RoleID
+			count++
+		}
+		if settings.Enabled != nil && *settings.Enabled != account.Enabled {
+			add("enabled", strconv.FormatBool(account.Enabled), strconv.FormatBool(*settings.Enabled))
+			changes.Enabled = settings.Enabled
+			count++
+		}
+		if count > 0 {
+			accountID := account.ID
+			p.addGroup(ResourceAccount, settings.UserName, client.ApplyTimeImmediate, false, count, func(string) (*client.TaskHandle, error) {
+				return nil, cli.UpdateAccount(accountID, changes)
+			})
+		}
+	}
+	return nil
+}
+
+// Apply applies the changes of the plan, and waits for the configuration
+// jobs applied immediately. The configuration jobs applied immediately
+// reboot the server, i.e. all of them but the last one are scheduled on
+// reset, so that one reboot applies them all. The function stops at the
+// first change failing to apply, and returns the results so far. The
+// changes scheduled on reset, and the changes of the jobs the function did
+// not wait for, are reported as scheduled and marked as pending.
+func (p *Plan) Apply(ctx context.Context, cli *client.Client) ([]*Result, error) {
+	lastImmediate := -1
+	for i, g := range p.groups {
+		if g.reboot && g.applyTime == client.ApplyTimeImmediate {
+			lastImmediate = i
+		}
+	}
+	type wait struct {
+		handle *client.TaskHandle
+		result *Result
+	}
+	waits := []*wait{}
+	results := []*Result{}
+	for i, g := range p.groups {
+		result := &Result{
+			Resource:  g.resource,
+			Target:    g.target,
+			ApplyTime: g.applyTime,
+			Changes:   g.changes,
+			Status:    StatusApplied,
+		}
+		results = append(results, result)
+		applyTime := g.applyTime
+		if g.reboot && applyTime == client.ApplyTimeImmediate && i != lastImmediate {
+			applyTime = client.ApplyTimeOnReset
+		}
+		if !g.reboot {
+			applyTime = ""
+		}
+		handle, err := g.apply(applyTime)
+		if err != nil {
+			result.Status = StatusFailed
+			result.Message = err.Error()
+			p.markScheduled(results)
+			return results, fmt.Errorf("%s %s: %s", g.resource, g.target, err)
+		}
+		if g.applyTime == client.ApplyTimeOnReset || applyTime == client.ApplyTimeOnReset {
+			// The changes apply on the next reboot, e.g. the boot order, or
+			// the immediate jobs scheduled on reset.
+			result.Status = StatusScheduled
+		}
+		if handle == nil {
+			continue
+		}
+		result.JobID = handle.JobID
+		if g.applyTime == client.ApplyTimeImmediate {
+			// The job is scheduled until it completes.
+			result.Status = StatusScheduled
+			waits = append(waits, &wait{handle: handle, result: result})
+		}
+	}
+	var failed error
+	for _, w := range waits {
+		task, err := cli.WaitForTask(ctx, w.handle, nil)
+		if err != nil {
+			w.result.Status = StatusFailed
+			w.result.Message = err.Error()
+			if failed == nil {
+				failed = fmt.Errorf("%s %s: %s", w.result.Resource, w.result.Target, err)
+			}
+			continue
+		}
+		w.result.Status = StatusApplied
+		if len(task.Messages) > 0 {
+			w.result.Message = task.Messages[len(task.Messages)-1].Message
+		}
+	}
+	p.markScheduled(results)
+	return results, failed
+}
+
+// markScheduled marks the changes of the scheduled results as pending.
+func (p *Plan) markScheduled(results []*Result) {
+	for _, result := range results {
+		if result.Status != StatusScheduled {
+			continue
+		}
+		for _, change := range p.Changes {
+			if change.Resource == result.Resource && change.Target == result.Target {
+				change.Pending = true
+			}
+		}
+	}
+}
+
+// Remaining returns the changes of the plan which are neither pending, nor
+// pending in the applied plan, e.g. the boot order the service does not
+// report as pending.
+func (p *Plan) Remaining(applied *Plan) []*Change {
+	scheduled := make(map[string]bool)
+	if applied != nil {
+		for _, change := range applied.Changes {
+			if change.Pending {
+				scheduled[change.key()] = true
+			}
+		}
+	}
+	remaining := []*Change{}
+	for _, change := range p.Changes {
+		if !change.Pending && !scheduled[change.key()] {
+			remaining = append(remaining, change)
+		}
+	}
+	return remaining
+}
+
+// key returns the identity of a change, including its desired value.
+func (change *Change) key() string {
+	return strings.Join([]string{change.Resource, change.Target, change.Name, change.Desired}, "\x00")
+}
+
+// defaultApplyTime returns the apply time of the changes requiring a
+// reboot, i.e. OnReset, unless set otherwise.
+func defaultApplyTime(applyTime string) string {
+	if applyTime == "" {
+		return client.ApplyTimeOnReset
+	}
+	return applyTime
+}
+
+// bootOrder returns the desired boot order, i.e. the desired boot options
+// followed by the other current boot options.
+func bootOrder(current, desired []string) []string {
+	order := []string{}
+	seen := make(map[string]bool)
+	for _, entry := range desired {
+		if !seen[entry] {
+			order = append(order, entry)
+			seen[entry] = true
+		}
+	}
+	for _, entry := range current {
+		if !seen[entry] {
+			order = append(order, entry)
+			seen[entry] = true
+		}
+	}
+	return order
+}
@@ -0,0 +1,257 @@
+// Copyright 2020 Paul Greenberg (greenpau@outlook.com)
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	. "github.com/greenpau/go-redfish-api-idrac/internal/client"
+	"github.com/greenpau/go-redfish-api-idrac/pkg/client"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDocumentFromYAML(t *testing.T) {
+	doc, err := LoadDocument("../../assets/plans/desired_1.yaml")
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if doc.SystemID != client.DefaultSystemID || len(doc.Accounts) != 2 || doc.NICs["NIC.Integrated.1-1-1"].ApplyTime != "Immediate" {
+		t.Fatalf("unexpected document: %+v", *doc)
+	}
+	for i, s := range []string{
+		"bios:\n  apply_time: AtMaintenanceWindowStart\n  attributes:\n    BootMode: Uefi\n",
+		"bios:\n  attributes: {}\n",
+		"idrac:\n  apply_time: OnReset\n  attributes:\n    IPMILan.1.Enable: Disabled\n",
+		"boot:\n  order: []\n",
+		"accounts:\n  - role_id: Operator\n",
+		"accounts:\n  - user_name: ops\n  - user_name: ops\n",
+		"nics:\n  NIC.Integrated.1-1-1:\n",
+		"firmware:\n  catalog: Catalog.xml\n",
+		"idrac:\n  attributes:\n    Users.3.Password: secret\n",
+		"bios:\n  attributes:\n    SetupPassword: secret\n",
+	} {
+		if _, err := NewDocumentFromYAML([]byte(s)); err == nil {
+			t.Fatalf("Test %d: expected failure, but got non-error response", i)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	server, err := NewMockTestServer(nil, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := client.NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+	cli.SetTaskPollInterval(time.Millisecond)
+
+	doc, err := LoadDocument("../../assets/plans/desired_1.yaml")
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if _, err := NewPlan(cli, "10.10.10.10", doc); err == nil {
+		t.Fatalf("expected failure due to missing password of new account, but got non-error response")
+	}
+	t.Setenv("IDRAC_DEPLOY_PASSWORD", "secret123")
+	p, err := NewPlan(cli, "10.10.10.10", doc)
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	changes := []string{}
+	for _, change := range p.Changes {
+		changes = append(changes, fmt.Sprintf("%s %s %s: %s -> %s (%s, pending: %t)",
+			change.Resource, change.Target, change.Name, change.Current, change.Desired, change.ApplyTime, change.Pending))
+	}
+	expChanges := []string{
+		"idrac iDRAC.Embedded.1 Time.1.Timezone: UTC -> America/Chicago (Immediate, pending: false)",
+		"account operator role_id: Operator -> ReadOnly (Immediate, pending: false)",
+		"account deploy user_name:  -> deploy (Immediate, pending: false)",
+		"account deploy role_id:  -> Operator (Immediate, pending: false)",
+		"account deploy enabled:  -> true (Immediate, pending: false)",
+		"nic NIC.Integrated.1-1-1 LegacyBootProto: NONE -> PXE (Immediate, pending: false)",
+		"bios System.Embedded.1 BootMode: Bios -> Uefi (OnReset, pending: false)",
+		"bios System.Embedded.1 LogicalProc: Enabled -> Disabled (OnReset, pending: true)",
+		"bios System.Embedded.1 SysProfile: PerfPerWattOptimizedDapc -> PerfOptimized (OnReset, pending: false)",
+		"boot System.Embedded.1 order: Boot0001,Boot0002 -> Boot0002,Boot0001 (OnReset, pending: false)",
+	}
+	if !reflect.DeepEqual(changes, expChanges) {
+		t.Fatalf("unexpected changes:\n%v\nexpected:\n%v", changes, expChanges)
+	}
+	if !p.HasChanges() {
+		t.Fatalf("expected changes, but the plan has no changes")
+	}
+
+	results, err := p.Apply(context.Background(), cli)
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	statuses := []string{}
+	for _, result := range results {
+		statuses = append(statuses, fmt.Sprintf("%s %s %d %s %s", result.Resource, result.Target, result.Changes, result.JobID, result.Status))
+	}
+	expStatuses := []string{
+		"idrac iDRAC.Embedded.1 1  applied",
+		"account operator 1  applied",
+		"account deploy 3  applied",
+		"nic NIC.Integrated.1-1-1 1 JID_467799000001 applied",
+		"bios System.Embedded.1 2 JID_467799000000 scheduled",
+		"boot System.Embedded.1 1  scheduled",
+	}
+	if !reflect.DeepEqual(statuses, expStatuses) {
+		t.Fatalf("unexpected results:\n%v\nexpected:\n%v", statuses, expStatuses)
+	}
+
+	// The mock server does not apply the changes, i.e. the changes but the
+	// scheduled ones remain.
+	verified, err := NewPlan(cli, "10.10.10.10", doc)
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	remaining := []string{}
+	for _, change := range verified.Remaining(p) {
+		remaining = append(remaining, change.Resource+" "+change.Name)
+	}
+	expRemaining := []string{
+		"idrac Time.1.Timezone",
+		"account role_id",
+		"account user_name",
+		"account role_id",
+		"account enabled",
+		"nic LegacyBootProto",
+	}
+	if !reflect.DeepEqual(remaining, expRemaining) {
+		t.Fatalf("unexpected remaining changes:\n%v\nexpected:\n%v", remaining, expRemaining)
+	}
+
+	doc.BIOS.Attributes["NoSuchAttribute"] = "Enabled"
+	if _, err := NewPlan(cli, "10.10.10.10", doc); err == nil {
+		t.Fatalf("expected failure due to unknown attribute, but got non-error response")
+	}
+}
+
+func TestPendingBootOrder(t *testing.T) {
+	server, err := NewMockTestServer(map[string]string{
+		"/redfish/v1/Systems/System.Embedded.1/Settings": "computer_system_settings_1.json",
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to start mock test server: %s", err)
+	}
+	defer server.Close()
+
+	cli := client.NewClient()
+	cli.SetHost(server.NonTLS.Hostname)
+	cli.SetPort(server.NonTLS.Port)
+	cli.SetProtocol(server.NonTLS.Protocol)
+	cli.SetUsername("admin")
+	cli.SetPassword("secret")
+
+	doc, err := NewDocumentFromYAML([]byte("boot:\n  order:\n    - Boot0002\n"))
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	p, err := NewPlan(cli, "10.10.10.10", doc)
+	if err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	if len(p.Changes) != 1 || !p.Changes[0].Pending {
+		t.Fatalf("expected pending boot order, but got %+v", p.Changes)
+	}
+	if p.HasChanges() {
+		t.Fatalf("expected no changes to apply, but the plan has changes")
+	}
+}
+
+func TestApplyTimes(t *testing.T) {
+	// The immediate configuration jobs but the last one are scheduled on
+	// reset, i.e. one reboot applies them all.
+	applied := []string{}
+	p := &Plan{}
+	for _, g := range []struct {
+		resource  string
+		applyTime string
+		reboot    bool
+	}{
+		{ResourceIDRAC, client.ApplyTimeImmediate, false},
+		{ResourceNIC, client.ApplyTimeImmediate, true},
+		{ResourceNIC, client.ApplyTimeOnReset, true},
+		{ResourceBIOS, client.ApplyTimeImmediate, true},
+		{ResourceBoot, client.ApplyTimeOnReset, false},
+	} {
+		resource := g.resource
+		p.addGroup(g.resource, "", g.applyTime, g.reboot, 1, func(applyTime string) (*client.TaskHandle, error) {
+			applied = append(applied, resource+" "+applyTime)
+			return nil, nil
+		})
+	}
+	if _, err := p.Apply(context.Background(), nil); err != nil {
+		t.Fatalf("expected success, but got error: %s", err)
+	}
+	exp := []string{"idrac ", "nic OnReset", "nic OnReset", "bios Immediate", "boot "}
+	if !reflect.DeepEqual(applied, exp) {
+		t.Fatalf("unexpected apply times: %v", applied)
+	}
+}
+
+func TestApplyStatuses(t *testing.T) {
+	for i, test := range []struct {
+		groups []string
+		exp    []string
+	}{
+		{
+			// The immediate jobs scheduled on reset, and the changes applied
+			// on reset, apply on the next reboot.
+			groups: []string{"idrac Immediate", "nic Immediate", "nic OnReset", "bios Immediate", "boot OnReset"},
+			exp:    []string{"idrac applied", "nic scheduled", "nic scheduled", "bios applied", "boot scheduled"},
+		},
+		{
+			// The job of the immediate changes scheduled on reset stays
+			// scheduled when a later change fails.
+			groups: []string{"nic Immediate job", "bios Immediate error"},
+			exp:    []string{"nic scheduled", "bios failed"},
+		},
+	} {
+		p := &Plan{}
+		for _, entry := range test.groups {
+			fields := strings.Fields(entry)
+			resource, applyTime := fields[0], fields[1]
+			outcome := ""
+			if len(fields) > 2 {
+				outcome = fields[2]
+			}
+			p.Changes = append(p.Changes, &Change{Resource: resource, ApplyTime: applyTime})
+			p.addGroup(resource, "", applyTime, resource == ResourceNIC || resource == ResourceBIOS, 1, func(string) (*client.TaskHandle, error) {
+				switch outcome {
+				case "job":
+					return &client.TaskHandle{JobID: "JID_1"}, nil
+				case "error":
+					return nil, fmt.Errorf("failed")
+				}
+				return nil, nil
+			})
+		}
+		results, _ := p.Apply(context.Background(), nil)
+		statuses := []string{}
+		for _, result := range results {
+			statuses = append(statuses, result.Resource+" "+result.Status)
+		}
+		if !reflect.DeepEqual(statuses, test.exp) {
+			t.Fatalf("Test %d: unexpected statuses: %v, expected: %v", i, statuses, test.exp)
+		}
+		for _, change := range p.Changes {
+			for _, result := range results {
+				if result.Resource == change.Resource && (result.Status == StatusScheduled) != change.Pending {
+					t.Fatalf("Test %d: change of %s is pending %t, but the result is %s", i, change.Resource, change.Pending, result.Status)
+				}
+			}
+		}
+	}
+}